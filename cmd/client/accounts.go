@@ -0,0 +1,184 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+)
+
+func accountsMenu(login string, db *sql.DB) *common.Menu {
+	var accounts []bank.Account
+	hasAccounts := func() bool {
+		return len(accounts) > 0
+	}
+	return &common.Menu{
+		Title: common.T("accounts.title"),
+		Before: func() error {
+			fmt.Println(common.Box(common.T("accounts.title")))
+			var err error
+			accounts, err = bank.GetAccounts(login, db)
+			if err != nil {
+				logger.Errorf("unable to get list of client accounts: %v", err)
+				fmt.Println(common.T("accounts.error"))
+				return common.ErrMenuExit
+			}
+			if len(accounts) == 0 {
+				logger.Info("list of accounts is empty")
+				fmt.Println(common.T("accounts.empty"))
+			}
+			printAccounts(accounts)
+			return nil
+		},
+		Items: []common.MenuItem{
+			{Label: common.T("accounts.rename"), Log: "rename account operation selected", Guard: hasAccounts, Handler: func() error {
+				account, ok := chooseListedAccount(accounts)
+				if ok {
+					renameAccount(login, account, db)
+				}
+				return nil
+			}},
+			{Label: common.T("accounts.make_default"), Log: "default account operation selected", Guard: hasAccounts, Handler: func() error {
+				account, ok := chooseListedAccount(accounts)
+				if ok {
+					makeDefaultAccount(login, account, db)
+				}
+				return nil
+			}},
+		},
+	}
+}
+
+// accountCurrencies returns a lookup of the currencies of the accounts of login, the accounts it
+// doesn't know are taken to be in money.DefaultCurrency.
+func accountCurrencies(login string, db *sql.DB) (currencyOf func(accountId int64) money.Currency, err error) {
+	accounts, err := bank.GetAccounts(login, db)
+	currencies := make(map[int64]money.Currency, len(accounts))
+	for _, account := range accounts {
+		currencies[account.Id] = account.Currency
+	}
+	return func(accountId int64) money.Currency {
+		if currency, ok := currencies[accountId]; ok {
+			return currency
+		}
+		return money.DefaultCurrency
+	}, err
+}
+
+func printAccounts(accounts []bank.Account) {
+	for idx, account := range accounts {
+		line := common.T("accounts.item", idx+1, account.Id, account.Balance.Format(account.Currency))
+		if account.Nickname != "" {
+			line += common.T("accounts.nickname", account.Nickname)
+		}
+		if account.Default {
+			line += common.T("accounts.default")
+		}
+		fmt.Println(line)
+	}
+}
+
+func chooseListedAccount(accounts []bank.Account) (account bank.Account, ok bool) {
+	logger.Debug("asking to enter account number")
+	number, err := common.GetIntegerInput(common.T("accounts.prompt.number"))
+	if err != nil {
+		logger.Warnf("unable to read account number: %v", err)
+		return account, false
+	}
+	if number < 1 || number > int64(len(accounts)) {
+		logger.Warnf("invalid account number: %d", number)
+		fmt.Println(common.T("accounts.invalid_number"))
+		return account, false
+	}
+	logger.Debug("account number entered")
+	return accounts[number-1], true
+}
+
+// chooseAccount lists the accounts of login and asks the client to pick the one to debit amount
+// from, accounts holding less than amount are refused. preferred is offered when the client has
+// it, the default account otherwise.
+func chooseAccount(login string, preferred int64, amount money.Money, db *sql.DB) (account bank.Account, ok bool) {
+	accounts, err := bank.GetAccounts(login, db)
+	if err != nil {
+		logger.Errorf("unable to get list of client accounts: %v", err)
+		fmt.Println(common.T("accounts.error"))
+		return account, false
+	}
+	if len(accounts) == 0 {
+		logger.Warn("list of accounts is empty")
+		fmt.Println(common.T("accounts.empty"))
+		return account, false
+	}
+	offered, enough := 0, false
+	for idx, account := range accounts {
+		if account.Id == preferred || (account.Default && offered == 0) {
+			offered = idx + 1
+		}
+		enough = enough || account.Balance >= amount
+	}
+	if !enough {
+		logger.Warnf("no account holds %s", amount.Decimal())
+		fmt.Println(common.T("accounts.none_enough"))
+		return account, false
+	}
+	printAccounts(accounts)
+
+	for {
+		logger.Debug("asking to choose account")
+		var number int64
+		if offered > 0 {
+			number, err = common.GetIntegerInputOr(common.T("accounts.prompt.choose"), int64(offered))
+		} else {
+			number, err = common.GetIntegerInput(common.T("accounts.prompt.choose"))
+		}
+		if err != nil {
+			logger.Warnf("unable to read account number: %v", err)
+			return account, false
+		}
+		if number < 1 || number > int64(len(accounts)) {
+			logger.Warnf("invalid account number: %d", number)
+			fmt.Println(common.T("accounts.invalid_number"))
+			continue
+		}
+		account = accounts[number-1]
+		if account.Balance < amount {
+			logger.Warnf("insufficient funds on account %d", account.Id)
+			fmt.Println(common.T("accounts.insufficient", account.Id, account.Balance.Format(account.Currency)))
+			continue
+		}
+		logger.Debug("account chosen")
+		return account, true
+	}
+}
+
+func renameAccount(login string, account bank.Account, db *sql.DB) {
+	logger.Debug("asking to enter account nickname")
+	nickname, err := common.GetOptionalStringInput(common.T("accounts.prompt.nickname"))
+	if err != nil {
+		logger.Warnf("unable to read account nickname: %v", err)
+		return
+	}
+	common.ClearConsole()
+	err = bank.SetAccountNickname(login, account.Id, nickname, db)
+	if err != nil {
+		logger.Errorf("unable to rename account: %v", err)
+		fmt.Println(common.T("accounts.failed"))
+		return
+	}
+	logger.Infof("account %d renamed", account.Id)
+	fmt.Println(common.T("accounts.renamed", account.Id))
+}
+
+func makeDefaultAccount(login string, account bank.Account, db *sql.DB) {
+	common.ClearConsole()
+	err := bank.SetDefaultAccount(login, account.Id, db)
+	if err != nil {
+		logger.Errorf("unable to set default account: %v", err)
+		fmt.Println(common.T("accounts.failed"))
+		return
+	}
+	logger.Infof("account %d made default", account.Id)
+	fmt.Println(common.T("accounts.default_set", account.Id))
+}
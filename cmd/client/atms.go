@@ -0,0 +1,159 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// atmsMenu lists the ATMs found by a filter the client changes with its items.
+func atmsMenu(db *sql.DB) *common.Menu {
+	var filter bank.ATMFilter
+	openNow := false
+	return &common.Menu{
+		Title: common.T("menu.atms"),
+		Before: func() error {
+			filter.OpenAt = time.Time{}
+			if openNow {
+				filter.OpenAt = time.Now()
+			}
+			printListOfATMs(filter, db)
+			fmt.Println()
+			return nil
+		},
+		Items: []common.MenuItem{
+			{Label: common.T("atms.search"), Log: "search of atms selected", Handler: func() error {
+				logger.Debug("asking to enter text to search")
+				text, err := common.GetOptionalStringInput(common.T("atms.prompt.text"))
+				if err != nil {
+					logger.Warnf("unable to read text: %v", err)
+					return nil
+				}
+				common.ClearConsole()
+				filter.Text = text
+				return nil
+			}},
+			{Label: common.T("atms.feature"), Log: "filter of atms by feature selected", Handler: func() error {
+				askATMFeature(&filter)
+				return nil
+			}},
+			{Label: common.T("atms.open_now"), Log: "open atms filter toggled", Handler: func() error {
+				openNow = !openNow
+				return nil
+			}},
+			{Label: common.T("atms.near"), Log: "sort of atms by distance selected", Handler: func() error {
+				askATMPoint(&filter)
+				return nil
+			}},
+		},
+	}
+}
+
+func askATMFeature(filter *bank.ATMFilter) {
+	choices := []string{"0"}
+	fmt.Println(common.T("journal.choice", 0, common.T("atms.any_feature")))
+	for idx, feature := range bank.ATMFeatures {
+		choices = append(choices, strconv.Itoa(idx+1))
+		fmt.Println(common.T("journal.choice", idx+1, common.T("atms.feature."+feature)))
+	}
+	logger.Debug("asking to choose feature of atms")
+	choice, err := common.GetChoiceInput(common.T("atms.prompt.feature"), choices...)
+	if err != nil {
+		logger.Warnf("unable to read feature of atms: %v", err)
+		return
+	}
+	common.ClearConsole()
+	filter.Feature = ""
+	if index, _ := strconv.Atoi(choice); index > 0 {
+		filter.Feature = bank.ATMFeatures[index-1]
+	}
+	logger.Debugf("feature %q chosen", filter.Feature)
+}
+
+func askATMPoint(filter *bank.ATMFilter) {
+	logger.Debug("asking to enter coordinates")
+	value, err := common.GetOptionalStringInput(common.T("atms.prompt.point"))
+	if err != nil {
+		logger.Warnf("unable to read coordinates: %v", err)
+		return
+	}
+	common.ClearConsole()
+	if value == "" {
+		filter.Near = nil
+		return
+	}
+	point, err := bank.ParsePoint(value)
+	if err != nil {
+		logger.Warnf("invalid coordinates: %v", err)
+		fmt.Println(common.T("atms.invalid_point"))
+		return
+	}
+	logger.Debug("coordinates entered")
+	filter.Near = &point
+}
+
+func printListOfATMs(filter bank.ATMFilter, db *sql.DB) {
+	logger.Debug("start getting list of atms")
+	listOfATMs, err := bank.FindATMs(filter, db)
+	if err != nil {
+		logger.Errorf("unable to get list of atms: %v", err)
+		fmt.Println(common.T("atms.error"))
+		return
+	}
+	logger.Debug("list of atms received")
+
+	fmt.Println(describeATMFilter(filter))
+	if len(listOfATMs) == 0 {
+		logger.Info("list of atms is empty")
+		if filter == (bank.ATMFilter{}) {
+			fmt.Println(common.T("atms.empty"))
+		} else {
+			fmt.Println(common.T("atms.not_found"))
+		}
+		return
+	}
+	for idx, atm := range listOfATMs {
+		fmt.Println(common.T("atms.item", idx+1, atm.Name, atm.Location))
+		fmt.Println("   " + describeATM(atm, filter.Near))
+	}
+}
+
+// describeATM tells the hours and the features of atm and how far it is from near, when it is set.
+func describeATM(atm bank.ATM, near *bank.Point) string {
+	parts := []string{common.T("atms.around_clock")}
+	if atm.Opens != "" {
+		parts[0] = common.T("atms.hours", atm.Opens, atm.Closes)
+	}
+	for _, feature := range atm.Features {
+		parts = append(parts, common.T("atms.feature."+feature))
+	}
+	if near != nil && atm.Point != nil {
+		parts = append(parts, common.T("atms.distance", atm.Point.Distance(*near)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describeATMFilter(filter bank.ATMFilter) string {
+	var parts []string
+	if filter.Text != "" {
+		parts = append(parts, common.T("journal.desc.text", filter.Text))
+	}
+	if filter.Feature != "" {
+		parts = append(parts, common.T("atms.feature."+filter.Feature))
+	}
+	if !filter.OpenAt.IsZero() {
+		parts = append(parts, common.T("atms.desc.open"))
+	}
+	if filter.Near != nil {
+		parts = append(parts, common.T("atms.desc.near", filter.Near))
+	}
+	if len(parts) == 0 {
+		return common.T("journal.no_filter")
+	}
+	return common.T("journal.filters", strings.Join(parts, ", "))
+}
@@ -3,380 +3,550 @@ package main
 import (
 	"database/sql"
 	"errors"
+	"flag"
 	"fmt"
 	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/auth"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/config"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/storage"
 	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
-	_ "github.com/mattn/go-sqlite3"
-	"log"
 	"os"
+	"time"
 )
 
+// settings holds the effective configuration loaded in main.
+var settings config.Config
+
 func main() {
-	file, err := os.OpenFile("client_log.txt", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
-	if err != nil {
-		log.Fatal(err)
+	flag.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, common.T("command.usage"))
 	}
-	log.SetOutput(file)
-	log.Print("start application")
-	log.Print("start opening db")
-	db, err := sql.Open("sqlite3", "db.sqlite")
+	config.Flags(flag.CommandLine)
+	flag.Parse()
+	defaults := config.Default()
+	defaults.LogPath = "client_log.txt"
+	var err error
+	settings, err = config.Load(defaults, flag.CommandLine)
 	if err != nil {
-		log.Fatalf("can't open db: %v", err)
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitUsage)
 	}
-	log.Print("db opened")
+	common.SetLanguage(common.DetectLanguage(settings.Language))
+
+	exitCode := exitOk
 	defer func() {
-		log.Print("start closing db")
-		if err := db.Close(); err != nil {
-			log.Fatalf("can't close db: %v", err)
+		if exitCode != exitOk {
+			os.Exit(exitCode)
 		}
-		log.Println("db closed")
-
-		if err = file.Close(); err != nil {
-			log.Fatalf("error closing file: %v", err)
+	}()
+	err = logger.Open(settings.LoggerOptions())
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "can't open log: %v\n", err)
+		exitCode = exitFailure
+		return
+	}
+	defer func() {
+		if err := logger.Close(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "can't close log: %v\n", err)
+			exitCode = exitFailure
 		}
 	}()
-	log.Println("initialising db")
-	err = core.Init(db)
+	logger.NewSession()
+	logger.Info("start application")
+	if flag.Arg(0) == "config" {
+		exitCode = configCommand(flag.Args()[1:])
+		logger.Info("finish application")
+		return
+	}
+	logger.Debugf("start opening %s db", settings.Driver)
+	db, err := storage.Open(settings.Driver, settings.DSN)
 	if err != nil {
-		log.Fatalf("can't initialise db: %v", err)
+		logger.Errorf("can't open db: %v", err)
+		exitCode = exitFailure
+		return
 	}
-	log.Println("db initialised")
-
-	fmt.Println(welcomeTitle)
-	log.Println("unauthorised operations loop started")
-	unauthorisedOperationsLoop(db, unauthorisedOperations)
-	log.Println("finish unauthorised operations loop")
-	log.Println("finish application")
-}
-
-func unauthorisedOperationsLoop(db *sql.DB, commands string) {
-	for {
-		fmt.Print(commands)
-		cmd := common.GetCommand()
-		common.ClearConsole()
-		switch cmd {
-		case "1":
-			log.Println("login operation selected")
-			loginOperations(db)
-		case "2":
-			log.Println("get list of atms operation selected")
-			printListOfATMs(db)
-		case "q":
-			log.Println("exit operation selected")
+	logger.Debug("db opened")
+	defer func() {
+		logger.Debug("start closing db")
+		if err := db.Close(); err != nil {
+			logger.Errorf("can't close db: %v", err)
+			exitCode = exitFailure
 			return
-		default:
-			log.Println("incorrect operation selected")
-			fmt.Printf("Вы выбрали неверную команду: %s\n", cmd)
 		}
+		logger.Debug("db closed")
+	}()
+	logger.Info("initialising db")
+	err = core.Init(db)
+	if err == nil {
+		err = bank.Init(db)
+	}
+	if err == nil {
+		err = auth.Init(db)
 	}
-}
-
-func printListOfATMs(db *sql.DB) {
-	log.Println("start getting list of atms")
-	listOfATMs, err := core.GetListOfATMs(db)
 	if err != nil {
-		log.Printf("unable to get list of atms: %v", err)
-		fmt.Println("Не удалось получить список бакоматов!")
+		logger.Errorf("can't initialise db: %v", err)
+		exitCode = exitFailure
 		return
 	}
-	log.Println("list of atms received")
+	logger.Debug("db initialised")
 
-	if listOfATMs == nil {
-		log.Print("list of atms is empty")
-		fmt.Println("Список банкоматов пуст.")
+	if flag.NArg() > 0 {
+		exitCode = runCommand(flag.Args(), db)
+		logger.Info("finish application")
 		return
 	}
-	for idx, atm := range listOfATMs {
-		fmt.Printf("%d) Название: %s расположение: %s\n", idx+1, atm.Name, atm.Location)
+
+	fmt.Println(common.T("welcome"))
+	logger.Info("unauthorised operations loop started")
+	_ = unauthorisedMenu(db).Run()
+	logger.Info("finish unauthorised operations loop")
+	logger.Info("finish application")
+}
+
+func unauthorisedMenu(db *sql.DB) *common.Menu {
+	return &common.Menu{
+		Title: common.T("menu.main"),
+		Quit:  common.T("menu.exit"),
+		Items: []common.MenuItem{
+			{Label: common.T("menu.login"), Log: "login operation selected", Handler: func() error {
+				loginOperations(db)
+				return nil
+			}},
+			{Label: common.T("menu.atms"), Log: "get list of atms operation selected", Submenu: atmsMenu(db)},
+		},
 	}
 }
 
-func printListOfAccounts(login string, db *sql.DB) {
-	accounts, err := core.GetListOfClientAccounts(login, db)
+func loginOperations(db *sql.DB) {
+	fmt.Println(common.Box(common.T("login.title")))
+	logger.Debug("asking to enter login")
+	login, err := common.GetStringInput(common.T("login.prompt.login"))
 	if err != nil {
-		log.Printf("unable to get list of client accounts")
-		fmt.Println("Не удалось получить список счетов")
+		logger.Warnf("unable to read login: %v", err)
 		return
 	}
-	if accounts == nil {
-		log.Println("list of accounts is empty")
-		fmt.Println("Список счетов пуст")
+	logger.Debug("login entered")
+
+	logger.Debug("asking to enter password")
+	password, err := common.GetPasswordInput(common.T("password.prompt"))
+	if err != nil {
+		logger.Warnf("unable to read password: %v", err)
 		return
 	}
-	for idx, account := range accounts {
-		fmt.Printf("%d) Счет: %d баланc: %f\n", idx+1, account.Id, account.Balance)
+	logger.Debug("password entered")
+
+	logger.Debug("trying to login")
+	phoneNumber, err := auth.Login(login, password, settings.LoginPolicy(), db)
+	if errors.Is(err, auth.ErrCodeRequired) {
+		logger.Debug("asking to enter one-time code")
+		var code string
+		code, err = common.GetStringInput(common.T("twofactor.prompt.login"))
+		if err != nil {
+			logger.Warnf("unable to read one-time code: %v", err)
+			return
+		}
+		logger.Debug("one-time code entered")
+		err = auth.VerifyCode(login, code, settings.LoginPolicy(), db)
 	}
-}
-
-func loginOperations(db *sql.DB) {
-	fmt.Println(loginTitle)
-	log.Print("asking to enter login")
-	fmt.Print("Введите логин: ")
-	login := common.GetStringInput()
-	log.Print("login entered")
-
-	log.Print("asking to enter password")
-	fmt.Print("Введите пароль: ")
-	password := common.GetStringInput()
-	log.Print("password entered")
-
-	log.Print("trying to login")
-	phoneNumber, err := core.Login(login, password, db)
 	if err != nil {
+		var delay *auth.DelayError
+		if errors.As(err, &delay) {
+			logger.Warnf("login too soon after failures: %v", err)
+			fmt.Println(common.T("login.wait", int64(delay.Wait.Seconds())))
+			return
+		}
+		if errors.Is(err, auth.ErrLockedOut) {
+			logger.Warn("client locked after failed logins")
+			fmt.Println(common.T("login.locked_out"))
+			return
+		}
+		if errors.Is(err, auth.ErrInvalidCode) {
+			logger.Warn("invalid one-time code")
+			fmt.Println(common.T("twofactor.invalid"))
+			return
+		}
 		if errors.Is(err, core.ErrInvalidPass) {
-			log.Println("invalid password")
-			fmt.Println("Неверный пароль.")
+			logger.Warn("invalid password")
+			fmt.Println(common.T("login.invalid_password"))
 		}
 		if errors.Is(err, core.ErrClientIsLocked) {
-			log.Println("client is locked")
-			fmt.Println("Просим прощения, но ваш аккаунт был заблокирован по каким-то серьёзным причинам (")
+			logger.Warn("client is locked")
+			fmt.Println(common.T("login.locked"))
 		}
-		log.Printf("unable to login: %v", err)
+		logger.Errorf("unable to login: %v", err)
 		return
 	}
 	if phoneNumber == -1 {
-		log.Print("invalid login or password")
-		fmt.Println("Неверный логин или пароль.")
+		logger.Warn("invalid login or password")
+		fmt.Println(common.T("login.invalid"))
 		return
 	}
-	log.Print("login success")
-	log.Print("authorised operations loop started")
-	authorisedOperationsLoop(phoneNumber, login, authorisedOperations, db)
-	log.Print("authorised operations loop ended")
-}
-
-func authorisedOperationsLoop(phoneNumber int64, login, commands string, db *sql.DB) {
-	for {
-		fmt.Print(commands)
-		cmd := common.GetCommand()
+	startSession(login, db)
+	logger.Info("authorised operations loop started")
+	err = authorisedMenu(phoneNumber, login, db).Run()
+	switch {
+	case errors.Is(err, common.ErrSessionExpired):
 		common.ClearConsole()
-		switch cmd {
-		case "1":
-			log.Println("get list of client accounts")
-			printListOfAccounts(login, db)
-		case "2":
-			log.Println("transfer money operation selected")
-			transferMoneyOperationsLoop(login, phoneNumber, db)
-		case "3":
-			log.Println("pay for service operation selected")
-			payForService(login, db)
-		case "4":
-			log.Println("get journal list operation selected")
-			printJournalListOperationsLoop(login, db)
-		case "q":
-			log.Println("exit operation selected")
-			return
-		default:
-			log.Println("incorrect operation selected")
-			fmt.Printf("Вы выбрали неверную команду: %s\n", cmd)
-		}
+		logger.Warnf("logged out: %v", err)
+		fmt.Println(common.T("session.expired"))
+	case errors.Is(err, auth.ErrSessionRevoked):
+		common.ClearConsole()
+		logger.Warnf("logged out: %v", err)
+		fmt.Println(common.T("session.revoked"))
 	}
+	logger.Info("authorised operations loop ended")
+	endSession()
 }
 
-func printJournalListOperationsLoop(login string, db *sql.DB) {
-	log.Println("start paging")
-	var page int64
-	page = 1
-	for {
-		offset := page * 10
-		if page == 1 {
-			offset = 0
-		}
-		log.Println("start getting list of journals")
-		journals, err := core.GetJournalListFormatted(login, 10, offset, db)
-		if err != nil {
-			log.Printf("unable to get list of journals: %v", err)
-			fmt.Println("Не удалось получить журнал операций!")
-			return
-		}
-		log.Println("list of journals received")
-		if journals == nil {
-			log.Println("list of journals is empty")
-			fmt.Println("Пусто")
-			return
-		}
+// startSession gives the entries of a logged in client their own correlation id. The session
+// ends once the client changes the password, in this or another session.
+func startSession(login string, db *sql.DB) {
+	previous := logger.Session()
+	logger.SetField("login", login)
+	logger.Infof("login success, session %s continues %s", logger.NewSession(), previous)
+	started := time.Now()
+	idle, max := settings.SessionLimits()
+	common.StartSession(idle, max, func() error {
+		return auth.CheckSession(login, started, db)
+	})
+}
 
-		for indx, journal := range journals {
-			fmt.Println(indx+1, ") ", journal.Date, journal.Type, journal.TransferredTo, journal.Amount)
-		}
+func endSession() {
+	common.EndSession()
+	logger.Info("session ended")
+	logger.SetField("login", "")
+	logger.NewSession()
+}
 
-		fmt.Println()
-		fmt.Print(pagingOperations)
-		cmd := common.GetCommand()
-		common.ClearConsole()
-		switch cmd {
-		case "1":
-			log.Println("next operation selected")
-			page++
-		case "2":
-			log.Println("prev operation selected")
-			if page != 0 {
-				page--
-			}
-		case "q":
-			log.Println("exit operation selected")
-			return
-		default:
-			log.Println("incorrect operation selected")
-			fmt.Printf("Вы выбрали неверную команду: %s\n", cmd)
-		}
+func authorisedMenu(phoneNumber int64, login string, db *sql.DB) *common.Menu {
+	return &common.Menu{
+		Title: common.T("menu.cabinet"),
+		Items: []common.MenuItem{
+			{Label: common.T("menu.accounts"), Log: "get list of client accounts", Submenu: accountsMenu(login, db)},
+			{Label: common.T("menu.transfer"), Log: "transfer money operation selected", Submenu: transferMenu(login, phoneNumber, db)},
+			{Label: common.T("menu.pay"), Log: "pay for service operation selected", Handler: func() error {
+				payForService(login, db)
+				return nil
+			}},
+			{Label: common.T("menu.journal"), Log: "get journal list operation selected", Handler: func() error {
+				printJournalListOperationsLoop(login, db)
+				return nil
+			}},
+			{Label: common.T("menu.scheduled"), Log: "scheduled transfers operation selected", Submenu: scheduledMenu(login, phoneNumber, db)},
+			{Label: common.T("menu.statement"), Log: "statement operation selected", Handler: func() error {
+				statementOperations(login, db)
+				return nil
+			}},
+			{Label: common.T("menu.password"), Log: "change password operation selected", Handler: func() error {
+				return changePassword(login, db)
+			}},
+			{Label: common.T("menu.two_factor"), Log: "two-factor authentication operation selected", Handler: func() error {
+				twoFactorOperations(login, db)
+				return nil
+			}},
+		},
 	}
 }
 
-func transferMoneyOperationsLoop(login string, phoneNumber int64, db *sql.DB) {
-	fmt.Println(transferTitle)
-	fmt.Print(transferMoneyOperations)
-	cmd := common.GetCommand()
-	common.ClearConsole()
-	switch cmd {
-	case "1":
-		log.Println("transfer money by account id operation selected")
-
-		transferByAccount(login, db)
-	case "2":
-		log.Println("transfer money by phone number operation selected")
+func printJournalListOperationsLoop(login string, db *sql.DB) {
+	logger.Debug("start paging")
+	var filter bank.JournalFilter
+	filtered := func() bool {
+		return filter != bank.JournalFilter{}
+	}
+	pager := &common.Pager{
+		Title:   common.T("journal.title"),
+		Size:    settings.PageSize,
+		Columns: common.Columns("journal.sort.", bank.JournalColumns),
+		Error:   common.T("journal.error"),
+		Before: func() {
+			if filtered() {
+				fmt.Println(describeJournalFilter(filter))
+			}
+		},
+		Fetch: func(limit, offset int64, sort common.Sort) (page common.Page, err error) {
+			journal, err := bank.GetJournal(login, filter, sort.Column, sort.Desc, limit, offset, db)
+			if err != nil {
+				return page, err
+			}
+			for _, operation := range journal.Operations {
+				page.Rows = append(page.Rows, fmt.Sprintf("%s %s %s %s", operation.Date.Format(bank.OperationDateFormat),
+					common.OperationType(operation), common.OperationParty(operation), common.OperationAmount(operation)))
+			}
+			page.Total = journal.Count
+			page.Footer = describeJournalTotals(journal)
+			return page, nil
+		},
+	}
+	pager.Items = []common.MenuItem{
+		{Label: common.T("journal.filter"), Key: "f", Log: "journal filter selected", Submenu: journalFilterMenu(&filter, pager.Reset)},
+		{Label: common.T("journal.reset"), Key: "r", Log: "reset journal filter selected", Guard: filtered, Handler: func() error {
+			filter = bank.JournalFilter{}
+			pager.Reset()
+			return nil
+		}},
+	}
+	_ = pager.Run()
+}
 
-		transferByPhoneNumber(phoneNumber, login, db)
-	case "q":
-		log.Println("exit operation selected")
-		return
-	default:
-		log.Println("incorrect operation selected")
-		fmt.Printf("Вы выбрали неверную команду: %s\n", cmd)
+func transferMenu(login string, phoneNumber int64, db *sql.DB) *common.Menu {
+	return &common.Menu{
+		Title:  common.T("transfer.title"),
+		Header: common.Box(common.T("transfer.title")),
+		Once:   true,
+		Items: []common.MenuItem{
+			{Label: common.T("transfer.by_account"), Log: "transfer money by account id operation selected", Handler: func() error {
+				transferByAccount(login, db)
+				return nil
+			}},
+			{Label: common.T("transfer.by_phone"), Log: "transfer money by phone number operation selected", Handler: func() error {
+				transferByPhoneNumber(phoneNumber, login, db)
+				return nil
+			}},
+			{Label: common.T("transfer.by_payee"), Log: "payees operation selected", Submenu: payeesMenu(login, phoneNumber, db)},
+		},
 	}
 }
 
 func transferByPhoneNumber(phoneNumber int64, login string, db *sql.DB) {
-	fmt.Println(transferTitle)
-	log.Println("asking to enter target phone number")
-	fmt.Print("Введите номер телефона цели: ")
-	targetPhoneNumber := common.GetIntegerInput()
-	log.Println("target phone number entered")
-
-	log.Println("asking to enter account id")
-	fmt.Print("Введите номер счета: ")
-	accountId := common.GetIntegerInput()
-	log.Println("account id entered")
-
-	log.Println("asking to enter amount")
-	fmt.Print("Введите сумму для перевода: ")
-	amount := common.GetIntegerInput()
-	log.Println("amount entered")
-
-	log.Println("trying to transfer money")
-	if phoneNumber == targetPhoneNumber {
-		log.Println("can't transfer money to the same person")
-		fmt.Println("Перевод средств на свой номер невозможен!")
+	fmt.Println(common.Box(common.T("transfer.title")))
+	logger.Debug("asking to enter target phone number")
+	targetPhoneNumber, err := common.GetPhoneNumberInput(common.T("transfer.prompt.phone"))
+	if err != nil {
+		logger.Warnf("unable to read target phone number: %v", err)
 		return
 	}
+	logger.Debug("target phone number entered")
 
-	ok, err := checkAccountIfValid(login, db, accountId)
-	if !ok {
-		log.Printf("unable to transfer money: %v", err)
-		fmt.Println("Перевод средств не удался!")
+	logger.Debug("asking to enter amount")
+	amount, err := common.GetAmountInput(common.T("transfer.prompt.amount"))
+	if err != nil {
+		logger.Warnf("unable to read amount: %v", err)
 		return
 	}
+	logger.Debug("amount entered")
 
-	err = core.TransferToByPhoneNumber(targetPhoneNumber, login, accountId, float64(amount), db)
-	if err != nil {
-		if errors.Is(err, core.ErrClientIsLocked) {
-			log.Println("target client is locked")
-			fmt.Println("Пользователь заблокирован!")
-		}
-		log.Printf("can't transfer money: %v", err)
-		fmt.Println("Перевод средств невозможен")
+	account, ok := chooseAccount(login, 0, amount, db)
+	if !ok {
 		return
 	}
-	log.Println("money transferred")
-	fmt.Println("Средства начислены!")
+	common.ClearConsole()
+
+	payee := bank.Payee{Login: login, PhoneNumber: targetPhoneNumber}
+	if transferTo(payee, phoneNumber, login, account.Id, amount, db) {
+		offerToSavePayee(payee, account.Id, amount, db)
+	}
 }
 
-func checkAccountIfValid(login string, db *sql.DB, accountId int64) (ok bool, err error) {
+func checkAccountIfValid(login string, db *sql.DB, accountId int64) (account bank.Account, ok bool, err error) {
 	ok = false
-	accounts, err := core.GetListOfClientAccounts(login, db)
-	for _, account := range accounts {
-		if account.Id == accountId {
-			ok = true
+	accounts, err := bank.GetAccounts(login, db)
+	for _, clientAccount := range accounts {
+		if clientAccount.Id == accountId {
+			account, ok = clientAccount, true
 		}
 	}
-	return ok, err
+	return account, ok, err
+}
+
+// checkTarget refuses transfers of the client with phoneNumber to themselves or from accountId to itself.
+func checkTarget(payee bank.Payee, phoneNumber, accountId int64) bool {
+	if payee.PhoneNumber != 0 && payee.PhoneNumber == phoneNumber {
+		logger.Warn("can't transfer money to the same person")
+		fmt.Println(common.T("transfer.same_phone"))
+		return false
+	}
+	if payee.AccountId != 0 && payee.AccountId == accountId {
+		logger.Warn("can't transfer money to the same account")
+		fmt.Println(common.T("transfer.same_account"))
+		return false
+	}
+	return true
+}
+
+// findRecipient returns the masked name of the owner of the account or phone number of payee.
+func findRecipient(payee bank.Payee, db *sql.DB) (recipient string, ok bool) {
+	var err error
+	if payee.AccountId != 0 {
+		recipient, err = bank.RecipientByAccountId(payee.AccountId, db)
+	} else {
+		recipient, err = bank.RecipientByPhoneNumber(payee.PhoneNumber, db)
+	}
+	if err == nil {
+		return recipient, true
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		logger.Warn("recipient does not exist")
+		fmt.Println(common.T("transfer.no_recipient"))
+		return "", false
+	}
+	logger.Errorf("unable to find recipient: %v", err)
+	fmt.Println(common.T("transfer.failed"))
+	return "", false
+}
+
+// quoteTransfer returns what the transfer from accountId to payee credits at the current rates.
+func quoteTransfer(payee bank.Payee, accountId int64, amount money.Money, db *sql.DB) (conversion bank.Conversion, ok bool) {
+	conversion, err := bank.QuoteTransfer(payee.AccountId, payee.PhoneNumber, accountId, amount, db)
+	if err == nil {
+		return conversion, true
+	}
+	switch {
+	case errors.Is(err, bank.ErrNoRate):
+		logger.Warnf("unable to convert transfer: %v", err)
+		fmt.Println(common.T("transfer.no_rate"))
+	case errors.Is(err, core.ErrClientIsLocked):
+		logger.Warn("target client is locked")
+		fmt.Println(common.T("transfer.target_locked"))
+	default:
+		logger.Errorf("unable to convert transfer: %v", err)
+		fmt.Println(common.T("transfer.failed"))
+	}
+	return conversion, false
+}
+
+// confirmTransfer shows the transfer to the client and asks to go on with it.
+func confirmTransfer(account bank.Account, conversion bank.Conversion, recipient string) bool {
+	amount := conversion.Amount
+	fmt.Println(common.Box(common.T("transfer.confirm.title")))
+	fmt.Println(common.T("transfer.confirm.account", account.Id, (account.Balance - amount).Format(account.Currency)))
+	fmt.Println(common.T("transfer.confirm.amount", amount.Format(conversion.From)))
+	if conversion.Converts() {
+		fmt.Println(common.T("transfer.confirm.converted", conversion.Converted.Format(conversion.To)))
+		fmt.Println(common.T("transfer.confirm.rate", conversion.From, conversion.Rate, conversion.To))
+	}
+	fmt.Println(common.T("transfer.confirm.recipient", recipient))
+	logger.Debug("asking to confirm transfer")
+	confirmed, err := common.GetConfirmInput(common.T("transfer.confirm.prompt"))
+	if err != nil {
+		logger.Warnf("unable to read confirmation: %v", err)
+		return false
+	}
+	if !confirmed {
+		logger.Infof("transfer of %s from account %d cancelled", amount.Decimal(), account.Id)
+		fmt.Println(common.T("transfer.cancelled"))
+		return false
+	}
+	logger.Debug("transfer confirmed")
+	return true
 }
 
 func transferByAccount(login string, db *sql.DB) {
-	fmt.Println(transferTitle)
-	log.Print("asking to enter account id")
-	fmt.Print("Введите свой номер счета: ")
-	accountId := common.GetIntegerInput()
-	log.Print("account id entered")
-
-	log.Print("asking to enter amount")
-	fmt.Print("Введите сумму для перевода средств: ")
-	amount := common.GetIntegerInput()
-	log.Print("amount entered")
-
-	log.Print("asking to enter target account id")
-	fmt.Print("Введите номер счета цели: ")
-	targetAccountId := common.GetIntegerInput()
-	log.Print("target account id entered")
+	fmt.Println(common.Box(common.T("transfer.title")))
+	logger.Debug("asking to enter amount")
+	amount, err := common.GetAmountInput(common.T("transfer.prompt.amount"))
+	if err != nil {
+		logger.Warnf("unable to read amount: %v", err)
+		return
+	}
+	logger.Debug("amount entered")
 
-	common.ClearConsole()
+	account, ok := chooseAccount(login, 0, amount, db)
+	if !ok {
+		return
+	}
 
-	log.Print("trying to transfer money")
-	if accountId == targetAccountId {
-		log.Print("can't transfer money to the same account")
-		fmt.Println("Вы не можете перевести средства на один и тот-же счет.")
+	logger.Debug("asking to enter target account id")
+	targetAccountId, err := common.GetIntegerInput(common.T("transfer.prompt.target"))
+	if err != nil {
+		logger.Warnf("unable to read target account id: %v", err)
 		return
 	}
+	logger.Debug("target account id entered")
+
+	common.ClearConsole()
+
+	payee := bank.Payee{Login: login, AccountId: targetAccountId}
+	if transferTo(payee, 0, login, account.Id, amount, db) {
+		offerToSavePayee(payee, account.Id, amount, db)
+	}
+}
 
-	ok, err := checkAccountIfValid(login, db, accountId)
+// transferTo moves amount from accountId to the account or phone number of payee after the client confirms it.
+// phoneNumber is the one of the client, 0 skips the check of a transfer to themselves.
+func transferTo(payee bank.Payee, phoneNumber int64, login string, accountId int64, amount money.Money, db *sql.DB) bool {
+	logger.Debug("trying to transfer money")
+	if !checkTarget(payee, phoneNumber, accountId) {
+		return false
+	}
 
+	account, ok, err := checkAccountIfValid(login, db, accountId)
 	if !ok {
-		log.Printf("unable to transfer money: %v", err)
-		fmt.Println("Перевод средств не удался!")
-		return
+		logger.Errorf("unable to transfer money: %v", err)
+		fmt.Println(common.T("transfer.failed"))
+		return false
 	}
 
-	err = core.TransferToByAccountId(targetAccountId, login, accountId, float64(amount), db)
+	recipient, ok := findRecipient(payee, db)
+	if !ok {
+		return false
+	}
+	conversion, ok := quoteTransfer(payee, accountId, amount, db)
+	if !ok || !confirmTransfer(account, conversion, recipient) {
+		return false
+	}
+
+	if payee.AccountId != 0 {
+		err = bank.TransferToByAccountId(payee.AccountId, login, accountId, amount, db)
+	} else {
+		err = bank.TransferToByPhoneNumber(payee.PhoneNumber, login, accountId, amount, db)
+	}
 	if err != nil {
 		if errors.Is(err, core.ErrClientIsLocked) {
-			log.Println("target client is locked")
-			fmt.Println("Пользователь заблокирован.")
+			logger.Warn("target client is locked")
+			fmt.Println(common.T("transfer.target_locked"))
 		}
-		log.Printf("unable to transfer money: %v", err)
-		fmt.Println("Перевод средств не удался!")
-		return
+		logger.Errorf("unable to transfer money: %v", err)
+		fmt.Println(common.T("transfer.failed"))
+		return false
 	}
-	log.Print("money transferred")
-	fmt.Println("Средства начислены.")
+	logger.Info("money transferred")
+	fmt.Println(common.T("transfer.done"))
+	return true
 }
 
 func payForService(login string, db *sql.DB) {
-	fmt.Println(payForServiceTitle)
-	log.Println("asking to enter account id")
-	fmt.Print("Введите номер счета: ")
-	accountId := common.GetIntegerInput()
-	log.Println("account id entered")
-	log.Println("asking to enter payment amount")
-	fmt.Print("Введите оплачеваемую сумму: ")
-	amount := common.GetIntegerInput()
-	log.Println("payment amount entered")
-	log.Println("asking to enter name of service")
-	fmt.Print("Введите название услуги: ")
-	nameOfService := common.GetStringInput()
-	log.Println("name of service entered")
-	log.Println("trying to pay for service")
-	err := core.PayForService(nameOfService, accountId, login, float64(amount), db)
+	fmt.Println(common.Box(common.T("pay.title")))
+	service, ok := chooseService(db)
+	if !ok {
+		return
+	}
+	details, ok := askDetails(service)
+	if !ok {
+		return
+	}
+	logger.Debug("asking to enter payment amount")
+	amount, err := common.GetAmountInput(common.T("pay.prompt.amount"))
+	if err != nil {
+		logger.Warnf("unable to read payment amount: %v", err)
+		return
+	}
+	logger.Debug("payment amount entered")
+	account, ok := chooseAccount(login, 0, amount, db)
+	if !ok {
+		return
+	}
+	logger.Debug("trying to pay for service")
+	err = bank.PayForService(service.Name, details, account.Id, login, amount, db)
 	common.ClearConsole()
 	if err != nil {
-		if errors.Is(err, core.ErrServiceNotExist) {
-			log.Println("service does not exist")
-			fmt.Println("Данная услуга не существует.")
-			return
+		var fieldErr *bank.FieldError
+		switch {
+		case errors.Is(err, core.ErrServiceNotExist):
+			logger.Warn("service does not exist")
+			fmt.Println(common.T("pay.service_not_exist"))
+		case errors.As(err, &fieldErr):
+			logger.Warnf("invalid payment details: %v", err)
+			fmt.Println(common.T("pay.field_invalid", fieldErr.Field.Name))
+		default:
+			logger.Errorf("unable to pay for service: %v", err)
+			fmt.Println(common.T("pay.failed"))
 		}
-		log.Printf("unable to pay for service: %v", err)
-		fmt.Println("Не удалось оплатить услугу.")
 		return
 	}
-	log.Println("payment done")
-	fmt.Printf("Услуга \"%s\" оплачена!\n", nameOfService)
+	logger.Info("payment done")
+	fmt.Println(common.T("pay.done", service.Name))
 }
@@ -0,0 +1,503 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/auth"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	exitOk = iota
+	exitFailure
+	exitUsage
+	exitInvalidPassword
+	exitClientLocked
+	exitInsufficientFunds
+)
+
+const (
+	loginEnv    = "IBANK_LOGIN"
+	passwordEnv = "IBANK_PASSWORD"
+	codeEnv     = "IBANK_CODE"
+)
+
+var commands = map[string]func(args []string, db *sql.DB) int{
+	"atms":      atmsCommand,
+	"accounts":  accountsCommand,
+	"account":   accountCommand,
+	"transfer":  transferCommand,
+	"pay":       payCommand,
+	"journal":   journalCommand,
+	"statement": statementCommand,
+}
+
+// runCommand executes a single non-interactive command and returns the process exit code.
+func runCommand(args []string, db *sql.DB) int {
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Print(common.T("command.usage"))
+		return exitOk
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		logger.Warnf("unknown command: %s", args[0])
+		_, _ = fmt.Fprint(os.Stderr, common.T("command.usage"))
+		return exitUsage
+	}
+	common.SetInput(os.Stdin, os.Stderr)
+	logger.Infof("command %s started", args[0])
+	code := command(args[1:], db)
+	logger.Infof("command %s finished with code %d", args[0], code)
+	return code
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	return flags
+}
+
+func loginFlag(flags *flag.FlagSet) *string {
+	return flags.String("login", os.Getenv(loginEnv), "client login (default $"+loginEnv+")")
+}
+
+// authenticate logs the client in with the password from the environment or stdin.
+func authenticate(login string, db *sql.DB) (phoneNumber int64, code int) {
+	if login == "" {
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.no_login"))
+		return -1, exitUsage
+	}
+	password, err := common.GetSecretInput(passwordEnv, common.T("password.prompt"))
+	if err != nil {
+		logger.Warnf("unable to read password: %v", err)
+		return -1, exitUsage
+	}
+
+	phoneNumber, err = auth.Login(login, password, settings.LoginPolicy(), db)
+	if errors.Is(err, auth.ErrCodeRequired) {
+		var code string
+		code, err = common.GetSecretInput(codeEnv, common.T("twofactor.prompt.login"))
+		if err != nil {
+			logger.Warnf("unable to read one-time code: %v", err)
+			return -1, exitUsage
+		}
+		err = auth.VerifyCode(login, code, settings.LoginPolicy(), db)
+	}
+	if err != nil {
+		var delay *auth.DelayError
+		if errors.As(err, &delay) {
+			logger.Warnf("login too soon after failures: %v", err)
+			_, _ = fmt.Fprintln(os.Stderr, common.T("login.wait", int64(delay.Wait.Seconds())))
+			return -1, exitInvalidPassword
+		}
+		if errors.Is(err, auth.ErrLockedOut) {
+			logger.Warn("client locked after failed logins")
+			_, _ = fmt.Fprintln(os.Stderr, common.T("login.locked_out"))
+			return -1, exitClientLocked
+		}
+		if errors.Is(err, auth.ErrInvalidCode) {
+			logger.Warn("invalid one-time code")
+			_, _ = fmt.Fprintln(os.Stderr, common.T("twofactor.invalid"))
+			return -1, exitInvalidPassword
+		}
+		if errors.Is(err, core.ErrInvalidPass) {
+			logger.Warn("invalid password")
+			_, _ = fmt.Fprintln(os.Stderr, common.T("login.invalid_password"))
+			return -1, exitInvalidPassword
+		}
+		if errors.Is(err, core.ErrClientIsLocked) {
+			logger.Warn("client is locked")
+			_, _ = fmt.Fprintln(os.Stderr, common.T("command.locked"))
+			return -1, exitClientLocked
+		}
+		logger.Errorf("unable to login: %v", err)
+		return -1, exitFailure
+	}
+	if phoneNumber == -1 {
+		logger.Warn("invalid login or password")
+		_, _ = fmt.Fprintln(os.Stderr, common.T("login.invalid"))
+		return -1, exitInvalidPassword
+	}
+	startSession(login, db)
+	return phoneNumber, exitOk
+}
+
+// checkFunds makes sure accountId belongs to the client and holds at least amount.
+func checkFunds(login string, accountId int64, amount money.Money, db *sql.DB) error {
+	accounts, err := bank.GetAccounts(login, db)
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		if account.Id != accountId {
+			continue
+		}
+		if account.Balance < amount {
+			return bank.ErrInsufficientFunds
+		}
+		return nil
+	}
+	return fmt.Errorf("account %d does not belong to %s", accountId, login)
+}
+
+func checkFundsCode(login string, accountId int64, amount money.Money, db *sql.DB) int {
+	err := checkFunds(login, accountId, amount, db)
+	if err == nil {
+		return exitOk
+	}
+	logger.Warnf("funds check failed: %v", err)
+	if errors.Is(err, bank.ErrInsufficientFunds) {
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.insufficient_funds"))
+		return exitInsufficientFunds
+	}
+	_, _ = fmt.Fprintln(os.Stderr, common.T("command.invalid_account"))
+	return exitFailure
+}
+
+func atmsCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("atms")
+	search := flags.String("search", "", "text to look for in the name and location")
+	feature := flags.String("feature", "", strings.Join(bank.ATMFeatures, ", "))
+	openNow := flags.Bool("open-now", false, "only the ATMs working now")
+	near := flags.String("near", "", "latitude,longitude to sort the ATMs by distance from")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	filter := bank.ATMFilter{Text: *search, Feature: *feature}
+	point, err := bank.ParsePoint(*near)
+	if (*feature != "" && !bank.IsATMFeature(*feature)) || (*near != "" && err != nil) {
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.atms_usage", strings.Join(bank.ATMFeatures, ", ")))
+		return exitUsage
+	}
+	if *near != "" {
+		filter.Near = &point
+	}
+	if *openNow {
+		filter.OpenAt = time.Now()
+	}
+	atms, err := bank.FindATMs(filter, db)
+	if err != nil {
+		logger.Errorf("unable to get list of atms: %v", err)
+		return exitFailure
+	}
+	for _, atm := range atms {
+		var hours, distance string
+		if atm.Opens != "" {
+			hours = atm.Opens + "-" + atm.Closes
+		}
+		if filter.Near != nil && atm.Point != nil {
+			distance = strconv.FormatFloat(atm.Point.Distance(*filter.Near), 'f', 2, 64)
+		}
+		fmt.Printf("%d\t%s\t%s\t%s\t%s\t%s\n", atm.Id, atm.Name, atm.Location, hours, strings.Join(atm.Features, ","), distance)
+	}
+	return exitOk
+}
+
+func accountsCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("accounts")
+	login := loginFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if _, code := authenticate(*login, db); code != exitOk {
+		return code
+	}
+	accounts, err := bank.GetAccounts(*login, db)
+	if err != nil {
+		logger.Errorf("unable to get list of client accounts: %v", err)
+		return exitFailure
+	}
+	for _, account := range accounts {
+		fmt.Printf("%d\t%s\t%s\t%t\t%s\n", account.Id, account.Balance.Decimal(), account.Currency, account.Default,
+			account.Nickname)
+	}
+	return exitOk
+}
+
+func accountCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("account")
+	login := loginFlag(flags)
+	accountId := flags.Int64("account", 0, "account id")
+	nickname := flags.String("nickname", "", "name of the account, empty removes it")
+	makeDefault := flags.Bool("default", false, "make the account the default one")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	rename := false
+	flags.Visit(func(set *flag.Flag) {
+		rename = rename || set.Name == "nickname"
+	})
+	if *accountId <= 0 || (!rename && !*makeDefault) {
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.account_usage"))
+		return exitUsage
+	}
+	if _, code := authenticate(*login, db); code != exitOk {
+		return code
+	}
+
+	var err error
+	if rename {
+		err = bank.SetAccountNickname(*login, *accountId, *nickname, db)
+	}
+	if err == nil && *makeDefault {
+		err = bank.SetDefaultAccount(*login, *accountId, db)
+	}
+	if err != nil {
+		logger.Warnf("unable to change account %d: %v", *accountId, err)
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.invalid_account"))
+		return exitFailure
+	}
+	logger.Infof("account %d changed", *accountId)
+	return exitOk
+}
+
+// sourceAccount returns from or, when it is 0, the default account of login.
+func sourceAccount(login string, from int64, db *sql.DB) (accountId int64, code int) {
+	if from != 0 {
+		return from, exitOk
+	}
+	accountId, err := bank.DefaultAccount(login, db)
+	if err != nil {
+		logger.Errorf("unable to get default account: %v", err)
+		return 0, exitFailure
+	}
+	if accountId == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.no_default"))
+		return 0, exitUsage
+	}
+	return accountId, exitOk
+}
+
+func transferCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("transfer")
+	login := loginFlag(flags)
+	from := flags.Int64("from", 0, "source account id, the default account when not set")
+	toAccount := flags.Int64("to-account", 0, "target account id")
+	toPhone := flags.Int64("to-phone", 0, "target phone number")
+	amountFlag := flags.String("amount", "", "amount to transfer, e.g. 150.50")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	amount, err := money.ParseAmount(*amountFlag)
+	if *from < 0 || err != nil || (*toAccount == 0) == (*toPhone == 0) {
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.transfer_usage"))
+		return exitUsage
+	}
+	phoneNumber, code := authenticate(*login, db)
+	if code != exitOk {
+		return code
+	}
+	if *from, code = sourceAccount(*login, *from, db); code != exitOk {
+		return code
+	}
+	if *toAccount == *from || *toPhone == phoneNumber {
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.self_transfer"))
+		return exitUsage
+	}
+	if code = checkFundsCode(*login, *from, amount, db); code != exitOk {
+		return code
+	}
+
+	if *toAccount != 0 {
+		err = bank.TransferToByAccountId(*toAccount, *login, *from, amount, db)
+	} else {
+		err = bank.TransferToByPhoneNumber(*toPhone, *login, *from, amount, db)
+	}
+	if err != nil {
+		logger.Errorf("unable to transfer money: %v", err)
+		if errors.Is(err, core.ErrClientIsLocked) {
+			_, _ = fmt.Fprintln(os.Stderr, common.T("command.target_locked"))
+			return exitClientLocked
+		}
+		if errors.Is(err, bank.ErrNoRate) {
+			_, _ = fmt.Fprintln(os.Stderr, common.T("transfer.no_rate"))
+			return exitFailure
+		}
+		_, _ = fmt.Fprintln(os.Stderr, common.T("transfer.failed"))
+		return exitFailure
+	}
+	logger.Info("money transferred")
+	return exitOk
+}
+
+// detailsFlag collects the payment details given as name=value, one per flag.
+type detailsFlag []bank.PaymentDetail
+
+func (receiver *detailsFlag) String() string {
+	details := make([]string, len(*receiver))
+	for idx, detail := range *receiver {
+		details[idx] = detail.Name + "=" + detail.Value
+	}
+	return strings.Join(details, " ")
+}
+
+func (receiver *detailsFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return errors.New("want name=value")
+	}
+	*receiver = append(*receiver, bank.PaymentDetail{Name: parts[0], Value: parts[1]})
+	return nil
+}
+
+func payCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("pay")
+	login := loginFlag(flags)
+	from := flags.Int64("from", 0, "source account id, the default account when not set")
+	service := flags.String("service", "", "name of service")
+	amountFlag := flags.String("amount", "", "amount to pay, e.g. 150.50")
+	var details detailsFlag
+	flags.Var(&details, "detail", "payment field as name=value, repeat it for every field of the service")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	amount, err := money.ParseAmount(*amountFlag)
+	if *from < 0 || err != nil || *service == "" {
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.pay_usage"))
+		return exitUsage
+	}
+	_, code := authenticate(*login, db)
+	if code != exitOk {
+		return code
+	}
+	if *from, code = sourceAccount(*login, *from, db); code != exitOk {
+		return code
+	}
+	if code = checkFundsCode(*login, *from, amount, db); code != exitOk {
+		return code
+	}
+
+	err = bank.PayForService(*service, details, *from, *login, amount, db)
+	if err != nil {
+		logger.Errorf("unable to pay for service: %v", err)
+		if errors.Is(err, core.ErrServiceNotExist) {
+			_, _ = fmt.Fprintln(os.Stderr, common.T("pay.service_not_exist"))
+		}
+		var fieldErr *bank.FieldError
+		if errors.As(err, &fieldErr) {
+			_, _ = fmt.Fprintln(os.Stderr, fieldMessage(fieldErr))
+			return exitUsage
+		}
+		return exitFailure
+	}
+	logger.Info("payment done")
+	return exitOk
+}
+
+func fieldMessage(err *bank.FieldError) string {
+	switch {
+	case errors.Is(err, bank.ErrUnknownField):
+		return common.T("command.unknown_field", err.Field.Name)
+	case errors.Is(err, bank.ErrMissingValue):
+		return common.T("command.field_missing", err.Field.Name)
+	}
+	return common.T("pay.field_invalid", err.Field.Name)
+}
+
+func journalCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("journal")
+	login := loginFlag(flags)
+	limit := flags.Int64("limit", settings.PageSize, "number of records")
+	offset := flags.Int64("offset", 0, "number of records to skip")
+	from := flags.String("from", "", "first day of the period")
+	to := flags.String("to", "", "last day of the period")
+	kind := flags.String("type", "", strings.Join(bank.OperationKinds, ", "))
+	min := flags.String("min", "", "minimal amount")
+	max := flags.String("max", "", "maximal amount")
+	counterparty := flags.String("counterparty", "", "account id or phone number of the other side of transfers")
+	service := flags.String("service", "", "part of the service name")
+	search := flags.String("search", "", "text to look for in counterparties and dates")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	filter, ok := journalFilter(*from, *to, *kind, *min, *max)
+	if !ok {
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.journal_usage", strings.Join(bank.OperationKinds, ", ")))
+		return exitUsage
+	}
+	filter.Counterparty, filter.Service, filter.Text = *counterparty, *service, *search
+	if _, code := authenticate(*login, db); code != exitOk {
+		return code
+	}
+	journal, err := bank.GetJournal(*login, filter, "date", false, *limit, *offset, db)
+	if err != nil {
+		logger.Errorf("unable to get list of journals: %v", err)
+		return exitFailure
+	}
+	for _, operation := range journal.Operations {
+		fmt.Printf("%s\t%s\t%s\t%s\t%s", operation.Date.Format(bank.OperationDateFormat), operation.Kind(),
+			common.OperationParty(operation), operation.Amount.Decimal(), operation.Currency)
+		if exchange := operation.Exchange; exchange.Currency != "" {
+			fmt.Printf("\t%s\t%s\t%s", exchange.Amount.Decimal(), exchange.Currency, exchange.Rate)
+		}
+		fmt.Println()
+	}
+	return exitOk
+}
+
+// journalFilter parses the flags of the journal command, empty ones don't restrict it.
+func journalFilter(from, to, kind, min, max string) (filter bank.JournalFilter, ok bool) {
+	var err error
+	if from != "" {
+		if filter.From, err = common.ParseDate(from); err != nil {
+			return filter, false
+		}
+	}
+	if to != "" {
+		if filter.To, err = common.ParseDate(to); err != nil {
+			return filter, false
+		}
+	}
+	if min != "" {
+		if filter.MinAmount, err = money.Parse(min); err != nil {
+			return filter, false
+		}
+	}
+	if max != "" {
+		if filter.MaxAmount, err = money.Parse(max); err != nil {
+			return filter, false
+		}
+	}
+	if kind != "" {
+		for _, known := range bank.OperationKinds {
+			if kind == known {
+				filter.Type = kind
+			}
+		}
+		if filter.Type == "" {
+			return filter, false
+		}
+	}
+	if !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return filter, false
+	}
+	if filter.MaxAmount != 0 && filter.MaxAmount < filter.MinAmount {
+		return filter, false
+	}
+	return filter, true
+}
+
+// configCommand prints the effective settings. It runs before the database is opened,
+// so the settings can be checked even when the dsn is wrong.
+func configCommand(args []string) int {
+	if len(args) != 1 || args[0] != "show" {
+		_, _ = fmt.Fprint(os.Stderr, common.T("command.usage"))
+		return exitUsage
+	}
+	err := settings.Write(os.Stdout)
+	if err != nil {
+		logger.Errorf("unable to print config: %v", err)
+		return exitFailure
+	}
+	return exitOk
+}
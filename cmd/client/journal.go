@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"strconv"
+	"strings"
+)
+
+// journalFilterMenu edits filter, changed is called after every change of it.
+func journalFilterMenu(filter *bank.JournalFilter, changed func()) *common.Menu {
+	return &common.Menu{
+		Title: common.T("journal.filter.title"),
+		Before: func() error {
+			fmt.Println(common.Box(common.T("journal.filter.title")))
+			fmt.Println(describeJournalFilter(*filter))
+			fmt.Println()
+			return nil
+		},
+		Items: []common.MenuItem{
+			{Label: common.T("journal.filter.period"), Log: "filter by period selected", Handler: func() error {
+				askJournalPeriod(filter, changed)
+				return nil
+			}},
+			{Label: common.T("journal.filter.type"), Log: "filter by type selected", Handler: func() error {
+				askJournalType(filter, changed)
+				return nil
+			}},
+			{Label: common.T("journal.filter.amount"), Log: "filter by amount selected", Handler: func() error {
+				askJournalAmount(filter, changed)
+				return nil
+			}},
+			{Label: common.T("journal.filter.party"), Log: "filter by counterparty selected", Handler: func() error {
+				logger.Debug("asking to enter counterparty")
+				counterparty, err := common.GetOptionalStringInput(common.T("journal.prompt.party"))
+				if err != nil {
+					logger.Warnf("unable to read counterparty: %v", err)
+					return nil
+				}
+				filter.Counterparty = counterparty
+				changed()
+				return nil
+			}},
+			{Label: common.T("journal.filter.service"), Log: "filter by service selected", Handler: func() error {
+				logger.Debug("asking to enter service name")
+				service, err := common.GetOptionalStringInput(common.T("journal.prompt.service"))
+				if err != nil {
+					logger.Warnf("unable to read service name: %v", err)
+					return nil
+				}
+				filter.Service = service
+				changed()
+				return nil
+			}},
+			{Label: common.T("journal.filter.text"), Log: "search by text selected", Handler: func() error {
+				logger.Debug("asking to enter text to search")
+				text, err := common.GetOptionalStringInput(common.T("journal.prompt.text"))
+				if err != nil {
+					logger.Warnf("unable to read text: %v", err)
+					return nil
+				}
+				filter.Text = text
+				changed()
+				return nil
+			}},
+		},
+	}
+}
+
+// describeJournalTotals tells the number of the operations found and their totals in every currency.
+func describeJournalTotals(journal bank.Journal) string {
+	lines := []string{common.T("journal.count", journal.Count)}
+	for _, total := range journal.Totals {
+		lines = append(lines, common.T("journal.totals", total.In.Format(total.Currency), total.Out.Format(total.Currency)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func askJournalPeriod(filter *bank.JournalFilter, changed func()) {
+	logger.Debug("asking to enter period")
+	from, err := common.GetOptionalDateInput(common.T("journal.prompt.from"))
+	if err != nil {
+		logger.Warnf("unable to read date: %v", err)
+		return
+	}
+	to, err := common.GetOptionalDateInput(common.T("journal.prompt.to"))
+	if err != nil {
+		logger.Warnf("unable to read date: %v", err)
+		return
+	}
+	common.ClearConsole()
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		logger.Warn("invalid period of journal filter")
+		fmt.Println(common.T("journal.invalid_period"))
+		return
+	}
+	logger.Debug("period entered")
+	filter.From, filter.To = from, to
+	changed()
+}
+
+func askJournalType(filter *bank.JournalFilter, changed func()) {
+	choices := []string{"0"}
+	fmt.Println(common.T("journal.choice", 0, common.T("journal.all")))
+	for idx, kind := range bank.OperationKinds {
+		choices = append(choices, strconv.Itoa(idx+1))
+		fmt.Println(common.T("journal.choice", idx+1, common.T("operation."+kind)))
+	}
+	logger.Debug("asking to choose type of operations")
+	choice, err := common.GetChoiceInput(common.T("journal.prompt.type"), choices...)
+	if err != nil {
+		logger.Warnf("unable to read type of operations: %v", err)
+		return
+	}
+	common.ClearConsole()
+	filter.Type = ""
+	if index, _ := strconv.Atoi(choice); index > 0 {
+		filter.Type = bank.OperationKinds[index-1]
+	}
+	logger.Debugf("type %q chosen", filter.Type)
+	changed()
+}
+
+func askJournalAmount(filter *bank.JournalFilter, changed func()) {
+	logger.Debug("asking to enter amount range")
+	min, err := common.GetOptionalAmountInput(common.T("journal.prompt.min"))
+	if err != nil {
+		logger.Warnf("unable to read amount: %v", err)
+		return
+	}
+	max, err := common.GetOptionalAmountInput(common.T("journal.prompt.max"))
+	if err != nil {
+		logger.Warnf("unable to read amount: %v", err)
+		return
+	}
+	common.ClearConsole()
+	if max != 0 && max < min {
+		logger.Warn("invalid amount range of journal filter")
+		fmt.Println(common.T("journal.invalid_amounts"))
+		return
+	}
+	logger.Debug("amount range entered")
+	filter.MinAmount, filter.MaxAmount = min, max
+	changed()
+}
+
+func describeJournalFilter(filter bank.JournalFilter) string {
+	var parts []string
+	if !filter.From.IsZero() {
+		parts = append(parts, common.T("journal.desc.from", filter.From.Format(bank.DateFormat)))
+	}
+	if !filter.To.IsZero() {
+		parts = append(parts, common.T("journal.desc.to", filter.To.Format(bank.DateFormat)))
+	}
+	if filter.Type != "" {
+		parts = append(parts, common.T("journal.desc.type", common.T("operation."+filter.Type)))
+	}
+	if filter.MinAmount != 0 {
+		parts = append(parts, common.T("journal.desc.min", filter.MinAmount.Decimal()))
+	}
+	if filter.MaxAmount != 0 {
+		parts = append(parts, common.T("journal.desc.max", filter.MaxAmount.Decimal()))
+	}
+	if filter.Counterparty != "" {
+		parts = append(parts, common.T("journal.desc.party", filter.Counterparty))
+	}
+	if filter.Service != "" {
+		parts = append(parts, common.T("journal.desc.service", filter.Service))
+	}
+	if filter.Text != "" {
+		parts = append(parts, common.T("journal.desc.text", filter.Text))
+	}
+	if len(parts) == 0 {
+		return common.T("journal.no_filter")
+	}
+	return common.T("journal.filters", strings.Join(parts, ", "))
+}
@@ -0,0 +1,882 @@
+package main
+
+import "github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+
+func init() {
+	for lang, messages := range messages {
+		common.AddMessages(lang, messages)
+	}
+}
+
+var messages = map[common.Language]map[string]string{
+	common.Russian: {
+		"menu.login":                 "Войти",
+		"menu.atms":                  "Получить список банкоматов",
+		"menu.cabinet":               "Личный кабинет",
+		"menu.accounts":              "Мои счета",
+		"menu.transfer":              "Перевести деньги другому клиенту",
+		"menu.pay":                   "Оплатить услугу",
+		"menu.journal":               "Просмотреть журнал",
+		"atms.error":                 "Не удалось получить список банкоматов!",
+		"atms.empty":                 "Список банкоматов пуст.",
+		"atms.item":                  "%d) Название: %s расположение: %s",
+		"atms.not_found":             "Банкоматы не найдены.",
+		"atms.search":                "Поиск по адресу",
+		"atms.feature":               "Фильтр по возможностям",
+		"atms.open_now":              "Только открытые сейчас / все",
+		"atms.near":                  "Сортировка по расстоянию",
+		"atms.prompt.text":           "Введите название или адрес (пусто — все): ",
+		"atms.prompt.feature":        "Выберите возможность: ",
+		"atms.prompt.point":          "Введите широту и долготу, например 41.311, 69.279 (пусто — без сортировки): ",
+		"atms.invalid_point":         "Координаты заданы неверно.",
+		"atms.any_feature":           "Любые",
+		"atms.feature.cash-in":       "приём наличных",
+		"atms.feature.currency":      "выдача валюты",
+		"atms.around_clock":          "круглосуточно",
+		"atms.hours":                 "%s–%s",
+		"atms.distance":              "%.1f км",
+		"atms.desc.open":             "открыты сейчас",
+		"atms.desc.near":             "ближе к %s",
+		"accounts.error":             "Не удалось получить список счетов",
+		"accounts.empty":             "Список счетов пуст",
+		"accounts.item":              "%d) Счет: %d баланс: %s",
+		"accounts.title":             "Мои счета",
+		"accounts.nickname":          " «%s»",
+		"accounts.default":           " (по умолчанию)",
+		"accounts.rename":            "Задать название счёта",
+		"accounts.make_default":      "Сделать счётом по умолчанию",
+		"accounts.prompt.number":     "Введите номер счёта в списке: ",
+		"accounts.prompt.choose":     "Выберите счёт списания: ",
+		"accounts.prompt.nickname":   "Название счёта (пусто — убрать название): ",
+		"accounts.invalid_number":    "Нет счёта с таким номером в списке.",
+		"accounts.insufficient":      "На счёте %d недостаточно средств, остаток: %s. Выберите другой счёт.",
+		"accounts.none_enough":       "Ни на одном счёте нет такой суммы.",
+		"accounts.renamed":           "Название счёта %d сохранено.",
+		"accounts.default_set":       "Счёт %d теперь используется по умолчанию.",
+		"accounts.failed":            "Не удалось изменить счёт.",
+		"login.title":                "Войти",
+		"login.prompt.login":         "Введите логин: ",
+		"login.invalid_password":     "Неверный пароль.",
+		"login.locked":               "Просим прощения, но ваш аккаунт был заблокирован по каким-то серьёзным причинам (",
+		"login.invalid":              "Неверный логин или пароль.",
+		"login.wait":                 "Слишком много неудачных попыток, повторите через %d с.",
+		"login.locked_out":           "Слишком много неудачных попыток входа, доступ заблокирован. Обратитесь в банк.",
+		"session.expired":            "Сеанс завершён по истечении времени, войдите снова.",
+		"session.revoked":            "Пароль был изменён, сеанс завершён. Войдите с новым паролем.",
+		"journal.title":              "Журнал",
+		"journal.error":              "Не удалось получить журнал операций!",
+		"journal.sort.date":          "Дата",
+		"journal.sort.amount":        "Сумма",
+		"journal.sort.counterparty":  "Контрагент",
+		"journal.filter":             "Фильтр",
+		"journal.reset":              "Сбросить фильтр",
+		"journal.count":              "Найдено операций: %d",
+		"journal.totals":             "Поступления: %s, списания: %s",
+		"journal.filters":            "Фильтр: %s",
+		"journal.no_filter":          "Фильтр не задан",
+		"journal.filter.title":       "Фильтр журнала",
+		"journal.filter.period":      "Период",
+		"journal.filter.type":        "Тип операции",
+		"journal.filter.amount":      "Сумма",
+		"journal.filter.party":       "Контрагент",
+		"journal.filter.service":     "Услуга",
+		"journal.filter.text":        "Поиск по тексту",
+		"journal.prompt.from":        "Начало периода (ГГГГ-ММ-ДД, пусто — без ограничения): ",
+		"journal.prompt.to":          "Конец периода (ГГГГ-ММ-ДД, пусто — без ограничения): ",
+		"journal.prompt.type":        "Выберите тип операции: ",
+		"journal.prompt.min":         "Сумма от (пусто — без ограничения): ",
+		"journal.prompt.max":         "Сумма до (пусто — без ограничения): ",
+		"journal.prompt.party":       "Номер счёта или телефона (пусто — любой): ",
+		"journal.prompt.service":     "Название услуги или его часть (пусто — любая): ",
+		"journal.prompt.text":        "Текст для поиска (пусто — без поиска): ",
+		"journal.choice":             "%d. %s",
+		"journal.all":                "Все операции",
+		"journal.invalid_period":     "Начало периода позже его конца.",
+		"journal.invalid_amounts":    "Сумма «до» меньше суммы «от».",
+		"journal.desc.from":          "с %s",
+		"journal.desc.to":            "по %s",
+		"journal.desc.type":          "тип: %s",
+		"journal.desc.min":           "от %s",
+		"journal.desc.max":           "до %s",
+		"journal.desc.party":         "контрагент: %s",
+		"journal.desc.service":       "услуга: %s",
+		"journal.desc.text":          "поиск: «%s»",
+		"command.journal_usage":      "Даты --from и --to задаются в формате ГГГГ-ММ-ДД, суммы --min и --max — неотрицательные, --type — один из: %s.",
+		"transfer.title":             "Перевод денег",
+		"transfer.by_account":        "По номеру счета",
+		"transfer.by_phone":          "По номеру телефона",
+		"transfer.prompt.phone":      "Введите номер телефона цели: ",
+		"transfer.prompt.target":     "Введите номер счета цели: ",
+		"transfer.prompt.amount":     "Введите сумму для перевода: ",
+		"transfer.same_phone":        "Перевод средств на свой номер невозможен!",
+		"transfer.same_account":      "Вы не можете перевести средства на один и тот же счет.",
+		"transfer.failed":            "Перевод средств не удался!",
+		"transfer.target_locked":     "Пользователь заблокирован!",
+		"transfer.done":              "Средства начислены!",
+		"transfer.no_recipient":      "Получатель не найден.",
+		"transfer.no_rate":           "Нет курса для перевода в другую валюту, попробуйте позже.",
+		"transfer.confirm.title":     "Подтверждение перевода",
+		"transfer.confirm.account":   "Со счёта %d, остаток после перевода: %s",
+		"transfer.confirm.amount":    "Сумма: %s",
+		"transfer.confirm.converted": "Получатель получит: %s",
+		"transfer.confirm.rate":      "Курс: 1 %s = %s %s",
+		"transfer.confirm.recipient": "Получатель: %s",
+		"transfer.confirm.prompt":    "Подтвердить перевод? (да/нет): ",
+		"transfer.cancelled":         "Перевод отменён.",
+		"transfer.by_payee":          "Из списка получателей",
+		"payees.title":               "Мои получатели",
+		"payees.error":               "Не удалось получить список получателей.",
+		"payees.empty":               "Список получателей пуст. Сохранить получателя можно после перевода.",
+		"payees.item":                "%d) %s: %s",
+		"payees.account":             "счёт %d",
+		"payees.phone":               "телефон %d",
+		"payees.template":            "   шаблон: %s со счёта %d",
+		"payees.transfer":            "Перевести получателю",
+		"payees.rename":              "Переименовать получателя",
+		"payees.delete":              "Удалить получателя",
+		"payees.prompt.number":       "Введите номер получателя: ",
+		"payees.invalid_number":      "Получателя с таким номером нет.",
+		"payees.prompt.save":         "Сохранить получателя? (да/нет): ",
+		"payees.prompt.name":         "Введите название получателя: ",
+		"payees.prompt.template":     "Запомнить сумму и счёт как шаблон? (да/нет): ",
+		"payees.prompt.delete":       "Удалить получателя \"%s\"? (да/нет): ",
+		"payees.exist":               "Получатель \"%s\" уже есть.",
+		"payees.failed":              "Не удалось изменить список получателей.",
+		"payees.saved":               "Получатель \"%s\" сохранён.",
+		"payees.renamed":             "Получатель переименован в \"%s\".",
+		"payees.deleted":             "Получатель \"%s\" удалён.",
+		"menu.scheduled":             "Запланированные переводы",
+		"scheduled.title":            "Запланированные переводы",
+		"scheduled.error":            "Не удалось получить запланированные переводы.",
+		"scheduled.empty":            "Запланированных переводов нет.",
+		"scheduled.by_account":       "Запланировать по номеру счета",
+		"scheduled.by_phone":         "Запланировать по номеру телефона",
+		"scheduled.cancel":           "Отменить перевод",
+		"scheduled.item":             "%d) %s со счёта %d на %s, %s",
+		"scheduled.next":             "   следующий перевод: %s",
+		"scheduled.status":           "   %s",
+		"scheduled.status.done":      "выполнен",
+		"scheduled.status.cancelled": "отменён",
+		"scheduled.status.failed":    "не выполнен",
+		"scheduled.last_failed":      "   последняя попытка не удалась: %s",
+		"scheduled.period.once":      "однократно",
+		"scheduled.period.weekly":    "каждую неделю",
+		"scheduled.period.monthly":   "каждый месяц %d числа",
+		"scheduled.period.days":      "раз в %d дн.",
+		"scheduled.choice.once":      "%d) однократно",
+		"scheduled.choice.weekly":    "%d) каждую неделю",
+		"scheduled.choice.monthly":   "%d) каждый месяц",
+		"scheduled.choice.days":      "%d) раз в несколько дней",
+		"scheduled.prompt.date":      "Введите дату первого перевода (ГГГГ-ММ-ДД): ",
+		"scheduled.prompt.period":    "Выберите периодичность: ",
+		"scheduled.prompt.days":      "Введите число дней между переводами: ",
+		"scheduled.invalid_days":     "Число дней должно быть больше нуля.",
+		"scheduled.past_date":        "Дата перевода уже прошла.",
+		"scheduled.summary":          "%s со счёта %d, получатель: %s",
+		"scheduled.first":            "Первый перевод %s, %s",
+		"scheduled.prompt.confirm":   "Запланировать перевод? (да/нет): ",
+		"scheduled.done":             "Перевод запланирован!",
+		"scheduled.failed":           "Не удалось запланировать перевод.",
+		"scheduled.prompt.number":    "Введите номер перевода: ",
+		"scheduled.invalid_number":   "Перевода с таким номером нет.",
+		"scheduled.not_active":       "Этот перевод уже не активен.",
+		"scheduled.cancelled":        "Перевод отменён.",
+		"menu.statement":             "Выписка по счёту",
+		"statement.box":              "Выписка по счёту",
+		"statement.prompt.from":      "Введите начало периода (ГГГГ-ММ-ДД): ",
+		"statement.prompt.to":        "Введите конец периода (ГГГГ-ММ-ДД): ",
+		"statement.prompt.format":    "Выберите формат (%s): ",
+		"statement.invalid_period":   "Начало периода позже его конца.",
+		"statement.failed":           "Не удалось сформировать выписку.",
+		"statement.done":             "Выписка сохранена в %s",
+		"command.statement_usage":    "Укажите --account, даты --from и --to в формате ГГГГ-ММ-ДД и --format: csv, json, txt, html.",
+		"menu.password":              "Сменить пароль",
+		"password.box":               "Смена пароля",
+		"password.prompt.current":    "Введите текущий пароль: ",
+		"password.prompt.new":        "Введите новый пароль: ",
+		"password.prompt.repeat":     "Повторите новый пароль: ",
+		"password.invalid":           "Текущий пароль введён неверно.",
+		"password.too_short":         "Пароль должен содержать не менее %d символов.",
+		"password.too_weak":          "Пароль должен содержать буквы и цифры.",
+		"password.has_login":         "Пароль не должен содержать логин.",
+		"password.same":              "Новый пароль совпадает с текущим.",
+		"password.failed":            "Не удалось сменить пароль.",
+		"password.changed":           "Пароль изменён. Все сеансы завершены, войдите с новым паролем.",
+		"menu.two_factor":            "Двухфакторная аутентификация",
+		"twofactor.box":              "Двухфакторная аутентификация",
+		"twofactor.scan":             "Отсканируйте код в приложении-аутентификаторе:",
+		"twofactor.secret":           "Или введите ключ вручную: %s",
+		"twofactor.prompt.code":      "Введите %d-значный код из приложения: ",
+		"twofactor.prompt.login":     "Введите код из приложения или код восстановления: ",
+		"twofactor.invalid":          "Неверный код.",
+		"twofactor.enabled":          "Двухфакторная аутентификация включена.",
+		"twofactor.recovery":         "Сохраните коды восстановления, каждый из них можно использовать для входа один раз:",
+		"twofactor.already":          "Двухфакторная аутентификация уже включена, осталось кодов восстановления: %d. Чтобы сменить устройство, обратитесь в банк.",
+		"twofactor.failed":           "Не удалось настроить двухфакторную аутентификацию.",
+		"pay.title":                  "Оплата услуги",
+		"pay.prompt.amount":          "Введите оплачиваемую сумму: ",
+		"pay.services_error":         "Не удалось получить список услуг.",
+		"pay.no_services":            "Список услуг пуст.",
+		"pay.other":                  "Прочее",
+		"pay.category":               "%s:",
+		"pay.service_item":           "  %d) %s",
+		"pay.prompt.number":          "Выберите услугу: ",
+		"pay.invalid_number":         "Нет услуги с таким номером в списке.",
+		"pay.prompt.field":           "%s (%s): ",
+		"pay.field_invalid":          "Значение не подходит для поля «%s».",
+		"pay.rule.text":              "текст",
+		"pay.rule.digits":            "цифры",
+		"pay.rule.phone":             "номер телефона",
+		"pay.rule.length":            "от %d до %d знаков",
+		"pay.rule.exact":             "%d знаков",
+		"pay.rule.min":               "не меньше %d знаков",
+		"pay.rule.max":               "не больше %d знаков",
+		"pay.service_not_exist":      "Данная услуга не существует.",
+		"pay.failed":                 "Не удалось оплатить услугу.",
+		"pay.done":                   "Услуга \"%s\" оплачена!",
+		"command.no_login":           "Не указан логин.",
+		"command.locked":             "Аккаунт заблокирован.",
+		"command.insufficient_funds": "Недостаточно средств на счёте.",
+		"command.invalid_account":    "Неверный номер счета.",
+		"command.transfer_usage":     "Укажите --amount и один из --to-account или --to-phone, без --from перевод идёт со счёта по умолчанию.",
+		"command.self_transfer":      "Перевод самому себе невозможен.",
+		"command.target_locked":      "Получатель заблокирован.",
+		"command.pay_usage":          "Укажите --service и --amount, без --from платёж идёт со счёта по умолчанию.",
+		"command.atms_usage":         "--feature — одно из: %s, --near — широта и долгота через запятую.",
+		"command.field_missing":      "Не указано поле «%s», задайте его флагом --detail.",
+		"command.unknown_field":      "У услуги нет поля «%s».",
+		"command.no_default":         "Не указан --from и не выбран счёт по умолчанию.",
+		"command.account_usage":      "Укажите --account и --nickname или --default.",
+		"command.usage": `Использование: client [флаги] [команда] [флаги команды]
+
+Без команды запускается интерактивное меню.
+
+Флаги:
+  --config                                    файл настроек (.toml, .json, .yaml)
+  --driver                                    драйвер базы: sqlite3, postgres
+  --dsn                                       строка подключения к базе
+  --log-path                                  файл журнала
+  --log-level                                 уровень журнала: debug, info, warn, error
+  --log-format                                формат журнала: text, json
+  --log-max-size                              размер журнала в МБ до ротации (0 без ограничения)
+  --log-max-age                               возраст журнала в днях до ротации (0 без ограничения)
+  --log-max-backups                           сколько старых журналов хранить
+  --page-size                                 строк на странице
+  --lang                                      язык: ru, en, uz (по умолчанию из LANG)
+  --export-dir                                папка для экспорта
+  --session-idle                              минут без ввода до выхода из сеанса (0 без ограничения)
+  --session-max                               наибольшая длительность сеанса в минутах (0 без ограничения)
+  --login-max-failures                        неудачных входов до блокировки (0 без ограничения)
+  --login-window                              за сколько минут считаются неудачные входы
+  --login-delay                               пауза в секундах после неудачного входа, растёт вдвое
+
+Команды:
+  atms     --search --feature --open-now
+           --near                             список банкоматов
+  accounts --login                            список счетов
+  account  --login --account
+           --nickname --default               название счёта, счёт по умолчанию
+  transfer --login --from --amount
+           (--to-account | --to-phone)        перевод денег
+  pay      --login --from --service --amount
+           --detail                           оплата услуги
+  journal  --login --limit --offset
+           --from --to --type --min --max
+           --counterparty --service --search  журнал операций
+  statement --login --account --from --to
+            --format --out                    выписка по счёту
+  config show                                 вывести действующие настройки
+
+Каждый флаг можно задать переменной IBANK_<ФЛАГ>, например IBANK_PAGE_SIZE,
+или в файле ibank.toml, ibank.yaml или ibank.json в текущей папке.
+
+Логин можно задать переменной IBANK_LOGIN, пароль берётся из
+IBANK_PASSWORD или читается из stdin. Одноразовый код
+двухфакторной аутентификации берётся из IBANK_CODE или из stdin.
+
+Коды выхода: 0 успех, 1 ошибка, 2 неверные аргументы,
+3 неверный логин, пароль или код, 4 клиент заблокирован,
+5 недостаточно средств.
+`,
+	},
+	common.English: {
+		"menu.login":                 "Log in",
+		"menu.atms":                  "List ATMs",
+		"menu.cabinet":               "My account",
+		"menu.accounts":              "My accounts",
+		"menu.transfer":              "Transfer money to another client",
+		"menu.pay":                   "Pay for a service",
+		"menu.journal":               "View history",
+		"atms.error":                 "Couldn't get the list of ATMs!",
+		"atms.empty":                 "There are no ATMs.",
+		"atms.item":                  "%d) Name: %s location: %s",
+		"atms.not_found":             "No ATMs found.",
+		"atms.search":                "Search by location",
+		"atms.feature":               "Filter by feature",
+		"atms.open_now":              "Open now only / all",
+		"atms.near":                  "Sort by distance",
+		"atms.prompt.text":           "Enter a name or location (empty for all): ",
+		"atms.prompt.feature":        "Choose a feature: ",
+		"atms.prompt.point":          "Enter latitude and longitude, e.g. 41.311, 69.279 (empty not to sort): ",
+		"atms.invalid_point":         "Invalid coordinates.",
+		"atms.any_feature":           "Any",
+		"atms.feature.cash-in":       "cash-in",
+		"atms.feature.currency":      "currency",
+		"atms.around_clock":          "24/7",
+		"atms.hours":                 "%s–%s",
+		"atms.distance":              "%.1f km",
+		"atms.desc.open":             "open now",
+		"atms.desc.near":             "nearest to %s",
+		"accounts.error":             "Couldn't get the list of accounts",
+		"accounts.empty":             "You have no accounts",
+		"accounts.item":              "%d) Account: %d balance: %s",
+		"accounts.title":             "My accounts",
+		"accounts.nickname":          " “%s”",
+		"accounts.default":           " (default)",
+		"accounts.rename":            "Name an account",
+		"accounts.make_default":      "Make an account the default one",
+		"accounts.prompt.number":     "Enter the number of the account in the list: ",
+		"accounts.prompt.choose":     "Choose the account to pay from: ",
+		"accounts.prompt.nickname":   "Account name (empty removes the name): ",
+		"accounts.invalid_number":    "There is no account with this number in the list.",
+		"accounts.insufficient":      "Account %d doesn't have enough money, balance: %s. Choose another account.",
+		"accounts.none_enough":       "None of your accounts holds this amount.",
+		"accounts.renamed":           "The name of account %d has been saved.",
+		"accounts.default_set":       "Account %d is now the default one.",
+		"accounts.failed":            "Couldn't change the account.",
+		"login.title":                "Log in",
+		"login.prompt.login":         "Enter login: ",
+		"login.invalid_password":     "Wrong password.",
+		"login.locked":               "Sorry, your account has been locked.",
+		"login.invalid":              "Wrong login or password.",
+		"login.wait":                 "Too many failed attempts, try again in %d s.",
+		"login.locked_out":           "Too many failed logins, access is locked. Please contact the bank.",
+		"session.expired":            "The session has timed out, please log in again.",
+		"session.revoked":            "The password was changed, the session has ended. Log in with the new password.",
+		"journal.title":              "History",
+		"journal.error":              "Couldn't get the history of operations!",
+		"journal.sort.date":          "Date",
+		"journal.sort.amount":        "Amount",
+		"journal.sort.counterparty":  "Counterparty",
+		"journal.filter":             "Filter",
+		"journal.reset":              "Reset filter",
+		"journal.count":              "Operations found: %d",
+		"journal.totals":             "Received: %s, spent: %s",
+		"journal.filters":            "Filter: %s",
+		"journal.no_filter":          "No filter set",
+		"journal.filter.title":       "History filter",
+		"journal.filter.period":      "Period",
+		"journal.filter.type":        "Type of operation",
+		"journal.filter.amount":      "Amount",
+		"journal.filter.party":       "Counterparty",
+		"journal.filter.service":     "Service",
+		"journal.filter.text":        "Search by text",
+		"journal.prompt.from":        "Start of the period (YYYY-MM-DD, empty for no limit): ",
+		"journal.prompt.to":          "End of the period (YYYY-MM-DD, empty for no limit): ",
+		"journal.prompt.type":        "Choose the type of operations: ",
+		"journal.prompt.min":         "Amount from (empty for no limit): ",
+		"journal.prompt.max":         "Amount up to (empty for no limit): ",
+		"journal.prompt.party":       "Account id or phone number (empty for any): ",
+		"journal.prompt.service":     "Service name or a part of it (empty for any): ",
+		"journal.prompt.text":        "Text to search for (empty for none): ",
+		"journal.choice":             "%d. %s",
+		"journal.all":                "All operations",
+		"journal.invalid_period":     "The period starts after it ends.",
+		"journal.invalid_amounts":    "The amount \"up to\" is less than the amount \"from\".",
+		"journal.desc.from":          "from %s",
+		"journal.desc.to":            "to %s",
+		"journal.desc.type":          "type: %s",
+		"journal.desc.min":           "at least %s",
+		"journal.desc.max":           "at most %s",
+		"journal.desc.party":         "counterparty: %s",
+		"journal.desc.service":       "service: %s",
+		"journal.desc.text":          "search: \"%s\"",
+		"command.journal_usage":      "Set the dates --from and --to as YYYY-MM-DD, non-negative amounts --min and --max and --type as one of: %s.",
+		"transfer.title":             "Money transfer",
+		"transfer.by_account":        "By account number",
+		"transfer.by_phone":          "By phone number",
+		"transfer.prompt.phone":      "Enter recipient's phone number: ",
+		"transfer.prompt.target":     "Enter recipient's account number: ",
+		"transfer.prompt.amount":     "Enter amount to transfer: ",
+		"transfer.same_phone":        "You can't transfer money to your own phone number!",
+		"transfer.same_account":      "You can't transfer money to the same account.",
+		"transfer.failed":            "The transfer failed!",
+		"transfer.target_locked":     "The recipient is locked!",
+		"transfer.done":              "Money transferred!",
+		"transfer.no_recipient":      "Recipient not found.",
+		"transfer.no_rate":           "There is no exchange rate for a transfer to another currency, try again later.",
+		"transfer.confirm.title":     "Confirm transfer",
+		"transfer.confirm.account":   "From account %d, balance after transfer: %s",
+		"transfer.confirm.amount":    "Amount: %s",
+		"transfer.confirm.converted": "The recipient gets: %s",
+		"transfer.confirm.rate":      "Rate: 1 %s = %s %s",
+		"transfer.confirm.recipient": "Recipient: %s",
+		"transfer.confirm.prompt":    "Confirm the transfer? (yes/no): ",
+		"transfer.cancelled":         "Transfer cancelled.",
+		"transfer.by_payee":          "From my payees",
+		"payees.title":               "My payees",
+		"payees.error":               "Couldn't get the list of payees.",
+		"payees.empty":               "You have no payees yet. A recipient can be saved after a transfer.",
+		"payees.item":                "%d) %s: %s",
+		"payees.account":             "account %d",
+		"payees.phone":               "phone %d",
+		"payees.template":            "   template: %s from account %d",
+		"payees.transfer":            "Transfer to a payee",
+		"payees.rename":              "Rename a payee",
+		"payees.delete":              "Delete a payee",
+		"payees.prompt.number":       "Enter payee number: ",
+		"payees.invalid_number":      "There is no payee with this number.",
+		"payees.prompt.save":         "Save the recipient? (yes/no): ",
+		"payees.prompt.name":         "Enter payee name: ",
+		"payees.prompt.template":     "Remember the amount and account as a template? (yes/no): ",
+		"payees.prompt.delete":       "Delete payee \"%s\"? (yes/no): ",
+		"payees.exist":               "Payee \"%s\" already exists.",
+		"payees.failed":              "Couldn't change the list of payees.",
+		"payees.saved":               "Payee \"%s\" saved.",
+		"payees.renamed":             "Payee renamed to \"%s\".",
+		"payees.deleted":             "Payee \"%s\" deleted.",
+		"menu.scheduled":             "Scheduled transfers",
+		"scheduled.title":            "Scheduled transfers",
+		"scheduled.error":            "Couldn't get the scheduled transfers.",
+		"scheduled.empty":            "There are no scheduled transfers.",
+		"scheduled.by_account":       "Schedule by account number",
+		"scheduled.by_phone":         "Schedule by phone number",
+		"scheduled.cancel":           "Cancel a transfer",
+		"scheduled.item":             "%d) %s from account %d to %s, %s",
+		"scheduled.next":             "   next transfer: %s",
+		"scheduled.status":           "   %s",
+		"scheduled.status.done":      "done",
+		"scheduled.status.cancelled": "cancelled",
+		"scheduled.status.failed":    "failed",
+		"scheduled.last_failed":      "   the last attempt failed: %s",
+		"scheduled.period.once":      "once",
+		"scheduled.period.weekly":    "every week",
+		"scheduled.period.monthly":   "every month on day %d",
+		"scheduled.period.days":      "every %d days",
+		"scheduled.choice.once":      "%d) once",
+		"scheduled.choice.weekly":    "%d) every week",
+		"scheduled.choice.monthly":   "%d) every month",
+		"scheduled.choice.days":      "%d) every few days",
+		"scheduled.prompt.date":      "Enter the date of the first transfer (YYYY-MM-DD): ",
+		"scheduled.prompt.period":    "Choose how often: ",
+		"scheduled.prompt.days":      "Enter the number of days between transfers: ",
+		"scheduled.invalid_days":     "The number of days must be greater than zero.",
+		"scheduled.past_date":        "The date of the transfer has passed.",
+		"scheduled.summary":          "%s from account %d, recipient: %s",
+		"scheduled.first":            "First transfer on %s, %s",
+		"scheduled.prompt.confirm":   "Schedule the transfer? (yes/no): ",
+		"scheduled.done":             "Transfer scheduled!",
+		"scheduled.failed":           "Couldn't schedule the transfer.",
+		"scheduled.prompt.number":    "Enter transfer number: ",
+		"scheduled.invalid_number":   "There is no transfer with this number.",
+		"scheduled.not_active":       "This transfer is no longer active.",
+		"scheduled.cancelled":        "Transfer cancelled.",
+		"menu.statement":             "Account statement",
+		"statement.box":              "Account statement",
+		"statement.prompt.from":      "Enter the start of the period (YYYY-MM-DD): ",
+		"statement.prompt.to":        "Enter the end of the period (YYYY-MM-DD): ",
+		"statement.prompt.format":    "Choose a format (%s): ",
+		"statement.invalid_period":   "The period starts after it ends.",
+		"statement.failed":           "Couldn't make the statement.",
+		"statement.done":             "Statement saved to %s",
+		"command.statement_usage":    "Set --account, the dates --from and --to as YYYY-MM-DD and --format: csv, json, txt, html.",
+		"menu.password":              "Change password",
+		"password.box":               "Change password",
+		"password.prompt.current":    "Enter the current password: ",
+		"password.prompt.new":        "Enter a new password: ",
+		"password.prompt.repeat":     "Repeat the new password: ",
+		"password.invalid":           "The current password is wrong.",
+		"password.too_short":         "The password must be at least %d characters long.",
+		"password.too_weak":          "The password must contain letters and digits.",
+		"password.has_login":         "The password must not contain the login.",
+		"password.same":              "The new password is the same as the current one.",
+		"password.failed":            "Unable to change the password.",
+		"password.changed":           "The password has been changed. All sessions have ended, log in with the new password.",
+		"menu.two_factor":            "Two-factor authentication",
+		"twofactor.box":              "Two-factor authentication",
+		"twofactor.scan":             "Scan the code with an authenticator app:",
+		"twofactor.secret":           "Or enter the key by hand: %s",
+		"twofactor.prompt.code":      "Enter the %d-digit code from the app: ",
+		"twofactor.prompt.login":     "Enter the code from the app or a recovery code: ",
+		"twofactor.invalid":          "Wrong code.",
+		"twofactor.enabled":          "Two-factor authentication is enabled.",
+		"twofactor.recovery":         "Keep the recovery codes, each of them lets you log in once:",
+		"twofactor.already":          "Two-factor authentication is already enabled, recovery codes left: %d. To change the device, contact the bank.",
+		"twofactor.failed":           "Unable to set up two-factor authentication.",
+		"pay.title":                  "Service payment",
+		"pay.prompt.amount":          "Enter amount to pay: ",
+		"pay.services_error":         "Couldn't get the list of services.",
+		"pay.no_services":            "There are no services.",
+		"pay.other":                  "Other",
+		"pay.category":               "%s:",
+		"pay.service_item":           "  %d) %s",
+		"pay.prompt.number":          "Choose a service: ",
+		"pay.invalid_number":         "There is no service with this number in the list.",
+		"pay.prompt.field":           "%s (%s): ",
+		"pay.field_invalid":          "The value doesn't fit the field “%s”.",
+		"pay.rule.text":              "text",
+		"pay.rule.digits":            "digits",
+		"pay.rule.phone":             "phone number",
+		"pay.rule.length":            "%d to %d characters",
+		"pay.rule.exact":             "%d characters",
+		"pay.rule.min":               "at least %d characters",
+		"pay.rule.max":               "at most %d characters",
+		"pay.service_not_exist":      "There is no such service.",
+		"pay.failed":                 "The payment failed.",
+		"pay.done":                   "Service \"%s\" paid!",
+		"command.no_login":           "Login is not set.",
+		"command.locked":             "The account is locked.",
+		"command.insufficient_funds": "Insufficient funds in the account.",
+		"command.invalid_account":    "Wrong account number.",
+		"command.transfer_usage":     "Set --amount and one of --to-account or --to-phone, without --from the default account is used.",
+		"command.self_transfer":      "You can't transfer money to yourself.",
+		"command.target_locked":      "The recipient is locked.",
+		"command.pay_usage":          "Set --service and --amount, without --from the default account is used.",
+		"command.atms_usage":         "Set --feature as one of: %s and --near as latitude,longitude.",
+		"command.field_missing":      "Set the field “%s” with --detail.",
+		"command.unknown_field":      "The service has no field “%s”.",
+		"command.no_default":         "Set --from or choose a default account.",
+		"command.account_usage":      "Set --account and --nickname or --default.",
+		"command.usage": `Usage: client [flags] [command] [command flags]
+
+Without a command the interactive menu is started.
+
+Flags:
+  --config                                    config file (.toml, .json, .yaml)
+  --driver                                    database driver: sqlite3, postgres
+  --dsn                                       database connection string
+  --log-path                                  log file
+  --log-level                                 log level: debug, info, warn, error
+  --log-format                                log format: text, json
+  --log-max-size                              log size in MB before rotation (0 for no limit)
+  --log-max-age                               log age in days before rotation (0 for no limit)
+  --log-max-backups                           number of old logs to keep
+  --page-size                                 rows on a page
+  --lang                                      language: ru, en, uz (default from LANG)
+  --export-dir                                export directory
+  --session-idle                              minutes without input that end a session (0 for no limit)
+  --session-max                               the longest session in minutes (0 for no limit)
+  --login-max-failures                        failed logins that lock the client (0 for no limit)
+  --login-window                              minutes the failed logins are counted for
+  --login-delay                               seconds to wait after a failed login, doubled each time
+
+Commands:
+  atms     --search --feature --open-now
+           --near                             list ATMs
+  accounts --login                            list accounts
+  account  --login --account
+           --nickname --default               name an account or make it the default
+  transfer --login --from --amount
+           (--to-account | --to-phone)        transfer money
+  pay      --login --from --service --amount
+           --detail                           pay for a service
+  journal  --login --limit --offset
+           --from --to --type --min --max
+           --counterparty --service --search  history of operations
+  statement --login --account --from --to
+            --format --out                    account statement
+  config show                                 print the effective settings
+
+Every flag can also be set with IBANK_<FLAG>, for example IBANK_PAGE_SIZE,
+or in ibank.toml, ibank.yaml or ibank.json in the working directory.
+
+Login can be set with IBANK_LOGIN, the password is taken from
+IBANK_PASSWORD or read from stdin. The one-time code of two-factor
+authentication is taken from IBANK_CODE or read from stdin.
+
+Exit codes: 0 success, 1 error, 2 invalid arguments,
+3 wrong login, password or code, 4 client is locked,
+5 insufficient funds.
+`,
+	},
+	common.Uzbek: {
+		"menu.login":                 "Kirish",
+		"menu.atms":                  "Bankomatlar ro‘yxati",
+		"menu.cabinet":               "Shaxsiy kabinet",
+		"menu.accounts":              "Mening hisoblarim",
+		"menu.transfer":              "Boshqa mijozga pul o‘tkazish",
+		"menu.pay":                   "Xizmat uchun to‘lash",
+		"menu.journal":               "Amallar tarixini ko‘rish",
+		"atms.error":                 "Bankomatlar ro‘yxatini olib bo‘lmadi!",
+		"atms.empty":                 "Bankomatlar ro‘yxati bo‘sh.",
+		"atms.item":                  "%d) Nomi: %s manzili: %s",
+		"atms.not_found":             "Bankomatlar topilmadi.",
+		"atms.search":                "Manzil bo‘yicha qidirish",
+		"atms.feature":               "Imkoniyatlar bo‘yicha filtr",
+		"atms.open_now":              "Faqat hozir ochiqlari / hammasi",
+		"atms.near":                  "Masofa bo‘yicha saralash",
+		"atms.prompt.text":           "Nomi yoki manzilini kiriting (bo‘sh — hammasi): ",
+		"atms.prompt.feature":        "Imkoniyatni tanlang: ",
+		"atms.prompt.point":          "Kenglik va uzunlikni kiriting, masalan 41.311, 69.279 (bo‘sh — saralashsiz): ",
+		"atms.invalid_point":         "Koordinatalar noto‘g‘ri.",
+		"atms.any_feature":           "Istalgan",
+		"atms.feature.cash-in":       "naqd pul qabul qilish",
+		"atms.feature.currency":      "valyuta berish",
+		"atms.around_clock":          "kecha-kunduz",
+		"atms.hours":                 "%s–%s",
+		"atms.distance":              "%.1f km",
+		"atms.desc.open":             "hozir ochiq",
+		"atms.desc.near":             "%s ga yaqinlari",
+		"accounts.error":             "Hisoblar ro‘yxatini olib bo‘lmadi",
+		"accounts.empty":             "Hisoblar ro‘yxati bo‘sh",
+		"accounts.item":              "%d) Hisob: %d balans: %s",
+		"accounts.title":             "Mening hisoblarim",
+		"accounts.nickname":          " «%s»",
+		"accounts.default":           " (asosiy)",
+		"accounts.rename":            "Hisobga nom berish",
+		"accounts.make_default":      "Hisobni asosiy qilish",
+		"accounts.prompt.number":     "Ro‘yxatdagi hisob raqamini kiriting: ",
+		"accounts.prompt.choose":     "Pul yechiladigan hisobni tanlang: ",
+		"accounts.prompt.nickname":   "Hisob nomi (bo‘sh — nomni olib tashlash): ",
+		"accounts.invalid_number":    "Ro‘yxatda bunday raqamli hisob yo‘q.",
+		"accounts.insufficient":      "%d hisobida mablag‘ yetarli emas, qoldiq: %s. Boshqa hisobni tanlang.",
+		"accounts.none_enough":       "Hech bir hisobingizda bunday summa yo‘q.",
+		"accounts.renamed":           "%d hisobining nomi saqlandi.",
+		"accounts.default_set":       "%d hisobi endi asosiy.",
+		"accounts.failed":            "Hisobni o‘zgartirib bo‘lmadi.",
+		"login.title":                "Kirish",
+		"login.prompt.login":         "Loginni kiriting: ",
+		"login.invalid_password":     "Parol noto‘g‘ri.",
+		"login.locked":               "Kechirasiz, hisobingiz bloklangan.",
+		"login.invalid":              "Login yoki parol noto‘g‘ri.",
+		"login.wait":                 "Muvaffaqiyatsiz urinishlar juda ko‘p, %d soniyadan keyin qayta urining.",
+		"login.locked_out":           "Kirishga muvaffaqiyatsiz urinishlar juda ko‘p, kirish bloklandi. Bankka murojaat qiling.",
+		"session.expired":            "Seans vaqti tugadi, qaytadan kiring.",
+		"session.revoked":            "Parol o‘zgartirildi, seans tugadi. Yangi parol bilan kiring.",
+		"journal.title":              "Amallar tarixi",
+		"journal.error":              "Amallar tarixini olib bo‘lmadi!",
+		"journal.sort.date":          "Sana",
+		"journal.sort.amount":        "Summa",
+		"journal.sort.counterparty":  "Kontragent",
+		"journal.filter":             "Filtr",
+		"journal.reset":              "Filtrni tozalash",
+		"journal.count":              "Topilgan amallar: %d",
+		"journal.totals":             "Tushumlar: %s, chiqimlar: %s",
+		"journal.filters":            "Filtr: %s",
+		"journal.no_filter":          "Filtr berilmagan",
+		"journal.filter.title":       "Amallar tarixi filtri",
+		"journal.filter.period":      "Davr",
+		"journal.filter.type":        "Amal turi",
+		"journal.filter.amount":      "Summa",
+		"journal.filter.party":       "Kontragent",
+		"journal.filter.service":     "Xizmat",
+		"journal.filter.text":        "Matn bo‘yicha qidirish",
+		"journal.prompt.from":        "Davr boshi (YYYY-OO-KK, bo‘sh — cheklovsiz): ",
+		"journal.prompt.to":          "Davr oxiri (YYYY-OO-KK, bo‘sh — cheklovsiz): ",
+		"journal.prompt.type":        "Amal turini tanlang: ",
+		"journal.prompt.min":         "Summa dan (bo‘sh — cheklovsiz): ",
+		"journal.prompt.max":         "Summa gacha (bo‘sh — cheklovsiz): ",
+		"journal.prompt.party":       "Hisob raqami yoki telefon (bo‘sh — istalgan): ",
+		"journal.prompt.service":     "Xizmat nomi yoki uning qismi (bo‘sh — istalgan): ",
+		"journal.prompt.text":        "Qidiriladigan matn (bo‘sh — qidiruvsiz): ",
+		"journal.choice":             "%d. %s",
+		"journal.all":                "Barcha amallar",
+		"journal.invalid_period":     "Davr boshi uning oxiridan keyin.",
+		"journal.invalid_amounts":    "«Gacha» summasi «dan» summasidan kichik.",
+		"journal.desc.from":          "%s dan",
+		"journal.desc.to":            "%s gacha",
+		"journal.desc.type":          "turi: %s",
+		"journal.desc.min":           "kamida %s",
+		"journal.desc.max":           "ko‘pi bilan %s",
+		"journal.desc.party":         "kontragent: %s",
+		"journal.desc.service":       "xizmat: %s",
+		"journal.desc.text":          "qidiruv: «%s»",
+		"command.journal_usage":      "--from va --to sanalarini YYYY-OO-KK ko‘rinishida, --min va --max summalarini manfiy bo‘lmagan holda, --type ni esa quyidagilardan biri sifatida ko‘rsating: %s.",
+		"transfer.title":             "Pul o‘tkazmasi",
+		"transfer.by_account":        "Hisob raqami bo‘yicha",
+		"transfer.by_phone":          "Telefon raqami bo‘yicha",
+		"transfer.prompt.phone":      "Qabul qiluvchining telefon raqamini kiriting: ",
+		"transfer.prompt.target":     "Qabul qiluvchining hisob raqamini kiriting: ",
+		"transfer.prompt.amount":     "O‘tkaziladigan summani kiriting: ",
+		"transfer.same_phone":        "O‘z raqamingizga pul o‘tkazib bo‘lmaydi!",
+		"transfer.same_account":      "Bir hisobning o‘ziga pul o‘tkazib bo‘lmaydi.",
+		"transfer.failed":            "Pul o‘tkazib bo‘lmadi!",
+		"transfer.target_locked":     "Foydalanuvchi bloklangan!",
+		"transfer.done":              "Pul o‘tkazildi!",
+		"transfer.no_recipient":      "Qabul qiluvchi topilmadi.",
+		"transfer.no_rate":           "Boshqa valyutaga o‘tkazma uchun kurs yo‘q, keyinroq urinib ko‘ring.",
+		"transfer.confirm.title":     "O‘tkazmani tasdiqlash",
+		"transfer.confirm.account":   "%d hisobidan, o‘tkazmadan keyingi qoldiq: %s",
+		"transfer.confirm.amount":    "Summa: %s",
+		"transfer.confirm.converted": "Oluvchi oladi: %s",
+		"transfer.confirm.rate":      "Kurs: 1 %s = %s %s",
+		"transfer.confirm.recipient": "Qabul qiluvchi: %s",
+		"transfer.confirm.prompt":    "O‘tkazmani tasdiqlaysizmi? (ha/yo‘q): ",
+		"transfer.cancelled":         "O‘tkazma bekor qilindi.",
+		"transfer.by_payee":          "Qabul qiluvchilar ro‘yxatidan",
+		"payees.title":               "Qabul qiluvchilarim",
+		"payees.error":               "Qabul qiluvchilar ro‘yxatini olib bo‘lmadi.",
+		"payees.empty":               "Qabul qiluvchilar ro‘yxati bo‘sh. Qabul qiluvchini o‘tkazmadan keyin saqlash mumkin.",
+		"payees.item":                "%d) %s: %s",
+		"payees.account":             "hisob %d",
+		"payees.phone":               "telefon %d",
+		"payees.template":            "   shablon: %[2]d hisobidan %[1]s",
+		"payees.transfer":            "Qabul qiluvchiga o‘tkazish",
+		"payees.rename":              "Qabul qiluvchi nomini o‘zgartirish",
+		"payees.delete":              "Qabul qiluvchini o‘chirish",
+		"payees.prompt.number":       "Qabul qiluvchi raqamini kiriting: ",
+		"payees.invalid_number":      "Bunday raqamli qabul qiluvchi yo‘q.",
+		"payees.prompt.save":         "Qabul qiluvchini saqlaysizmi? (ha/yo‘q): ",
+		"payees.prompt.name":         "Qabul qiluvchi nomini kiriting: ",
+		"payees.prompt.template":     "Summa va hisobni shablon sifatida eslab qolasizmi? (ha/yo‘q): ",
+		"payees.prompt.delete":       "\"%s\" qabul qiluvchisini o‘chirasizmi? (ha/yo‘q): ",
+		"payees.exist":               "\"%s\" qabul qiluvchisi allaqachon bor.",
+		"payees.failed":              "Qabul qiluvchilar ro‘yxatini o‘zgartirib bo‘lmadi.",
+		"payees.saved":               "\"%s\" qabul qiluvchisi saqlandi.",
+		"payees.renamed":             "Qabul qiluvchi nomi \"%s\" ga o‘zgartirildi.",
+		"payees.deleted":             "\"%s\" qabul qiluvchisi o‘chirildi.",
+		"menu.scheduled":             "Rejalashtirilgan o‘tkazmalar",
+		"scheduled.title":            "Rejalashtirilgan o‘tkazmalar",
+		"scheduled.error":            "Rejalashtirilgan o‘tkazmalarni olib bo‘lmadi.",
+		"scheduled.empty":            "Rejalashtirilgan o‘tkazmalar yo‘q.",
+		"scheduled.by_account":       "Hisob raqami bo‘yicha rejalashtirish",
+		"scheduled.by_phone":         "Telefon raqami bo‘yicha rejalashtirish",
+		"scheduled.cancel":           "O‘tkazmani bekor qilish",
+		"scheduled.item":             "%[1]d) %[3]d hisobidan %[4]s ga %[2]s, %[5]s",
+		"scheduled.next":             "   keyingi o‘tkazma: %s",
+		"scheduled.status":           "   %s",
+		"scheduled.status.done":      "bajarildi",
+		"scheduled.status.cancelled": "bekor qilindi",
+		"scheduled.status.failed":    "bajarilmadi",
+		"scheduled.last_failed":      "   oxirgi urinish muvaffaqiyatsiz: %s",
+		"scheduled.period.once":      "bir marta",
+		"scheduled.period.weekly":    "har hafta",
+		"scheduled.period.monthly":   "har oyning %d-kuni",
+		"scheduled.period.days":      "har %d kunda",
+		"scheduled.choice.once":      "%d) bir marta",
+		"scheduled.choice.weekly":    "%d) har hafta",
+		"scheduled.choice.monthly":   "%d) har oy",
+		"scheduled.choice.days":      "%d) bir necha kunda bir",
+		"scheduled.prompt.date":      "Birinchi o‘tkazma sanasini kiriting (YYYY-OO-KK): ",
+		"scheduled.prompt.period":    "Davriylikni tanlang: ",
+		"scheduled.prompt.days":      "O‘tkazmalar orasidagi kunlar sonini kiriting: ",
+		"scheduled.invalid_days":     "Kunlar soni noldan katta bo‘lishi kerak.",
+		"scheduled.past_date":        "O‘tkazma sanasi o‘tib ketgan.",
+		"scheduled.summary":          "%[2]d hisobidan %[1]s, qabul qiluvchi: %[3]s",
+		"scheduled.first":            "Birinchi o‘tkazma %s, %s",
+		"scheduled.prompt.confirm":   "O‘tkazmani rejalashtirasizmi? (ha/yo‘q): ",
+		"scheduled.done":             "O‘tkazma rejalashtirildi!",
+		"scheduled.failed":           "O‘tkazmani rejalashtirib bo‘lmadi.",
+		"scheduled.prompt.number":    "O‘tkazma raqamini kiriting: ",
+		"scheduled.invalid_number":   "Bunday raqamli o‘tkazma yo‘q.",
+		"scheduled.not_active":       "Bu o‘tkazma endi faol emas.",
+		"scheduled.cancelled":        "O‘tkazma bekor qilindi.",
+		"menu.statement":             "Hisob ko‘chirmasi",
+		"statement.box":              "Hisob ko‘chirmasi",
+		"statement.prompt.from":      "Davr boshini kiriting (YYYY-OO-KK): ",
+		"statement.prompt.to":        "Davr oxirini kiriting (YYYY-OO-KK): ",
+		"statement.prompt.format":    "Formatni tanlang (%s): ",
+		"statement.invalid_period":   "Davr boshi uning oxiridan keyin.",
+		"statement.failed":           "Ko‘chirmani tuzib bo‘lmadi.",
+		"statement.done":             "Ko‘chirma %s ga saqlandi",
+		"command.statement_usage":    "--account, --from va --to sanalarini YYYY-OO-KK ko‘rinishida va --format ni ko‘rsating: csv, json, txt, html.",
+		"menu.password":              "Parolni o‘zgartirish",
+		"password.box":               "Parolni o‘zgartirish",
+		"password.prompt.current":    "Joriy parolni kiriting: ",
+		"password.prompt.new":        "Yangi parolni kiriting: ",
+		"password.prompt.repeat":     "Yangi parolni takrorlang: ",
+		"password.invalid":           "Joriy parol noto‘g‘ri.",
+		"password.too_short":         "Parol kamida %d ta belgidan iborat bo‘lishi kerak.",
+		"password.too_weak":          "Parolda harflar va raqamlar bo‘lishi kerak.",
+		"password.has_login":         "Parolda login bo‘lmasligi kerak.",
+		"password.same":              "Yangi parol joriy parol bilan bir xil.",
+		"password.failed":            "Parolni o‘zgartirib bo‘lmadi.",
+		"password.changed":           "Parol o‘zgartirildi. Barcha seanslar tugadi, yangi parol bilan kiring.",
+		"menu.two_factor":            "Ikki bosqichli autentifikatsiya",
+		"twofactor.box":              "Ikki bosqichli autentifikatsiya",
+		"twofactor.scan":             "Kodni autentifikator ilovasida skanerlang:",
+		"twofactor.secret":           "Yoki kalitni qo‘lda kiriting: %s",
+		"twofactor.prompt.code":      "Ilovadagi %d xonali kodni kiriting: ",
+		"twofactor.prompt.login":     "Ilovadagi kodni yoki tiklash kodini kiriting: ",
+		"twofactor.invalid":          "Kod noto‘g‘ri.",
+		"twofactor.enabled":          "Ikki bosqichli autentifikatsiya yoqildi.",
+		"twofactor.recovery":         "Tiklash kodlarini saqlang, ularning har biri bilan bir marta kirish mumkin:",
+		"twofactor.already":          "Ikki bosqichli autentifikatsiya allaqachon yoqilgan, qolgan tiklash kodlari: %d. Qurilmani almashtirish uchun bankka murojaat qiling.",
+		"twofactor.failed":           "Ikki bosqichli autentifikatsiyani sozlab bo‘lmadi.",
+		"pay.title":                  "Xizmat uchun to‘lov",
+		"pay.prompt.amount":          "To‘lov summasini kiriting: ",
+		"pay.services_error":         "Xizmatlar ro‘yxatini olib bo‘lmadi.",
+		"pay.no_services":            "Xizmatlar ro‘yxati bo‘sh.",
+		"pay.other":                  "Boshqa",
+		"pay.category":               "%s:",
+		"pay.service_item":           "  %d) %s",
+		"pay.prompt.number":          "Xizmatni tanlang: ",
+		"pay.invalid_number":         "Ro‘yxatda bunday raqamli xizmat yo‘q.",
+		"pay.prompt.field":           "%s (%s): ",
+		"pay.field_invalid":          "Qiymat «%s» maydoniga mos emas.",
+		"pay.rule.text":              "matn",
+		"pay.rule.digits":            "raqamlar",
+		"pay.rule.phone":             "telefon raqami",
+		"pay.rule.length":            "%d dan %d gacha belgi",
+		"pay.rule.exact":             "%d ta belgi",
+		"pay.rule.min":               "kamida %d belgi",
+		"pay.rule.max":               "ko‘pi bilan %d belgi",
+		"pay.service_not_exist":      "Bunday xizmat mavjud emas.",
+		"pay.failed":                 "Xizmat uchun to‘lab bo‘lmadi.",
+		"pay.done":                   "\"%s\" xizmati uchun to‘landi!",
+		"command.no_login":           "Login ko‘rsatilmagan.",
+		"command.locked":             "Hisob bloklangan.",
+		"command.insufficient_funds": "Hisobda mablag‘ yetarli emas.",
+		"command.invalid_account":    "Hisob raqami noto‘g‘ri.",
+		"command.transfer_usage":     "--amount va --to-account yoki --to-phone dan birini ko‘rsating, --from bo‘lmasa asosiy hisob ishlatiladi.",
+		"command.self_transfer":      "O‘zingizga pul o‘tkazib bo‘lmaydi.",
+		"command.target_locked":      "Qabul qiluvchi bloklangan.",
+		"command.pay_usage":          "--service va --amount ni ko‘rsating, --from bo‘lmasa asosiy hisob ishlatiladi.",
+		"command.atms_usage":         "--feature ni quyidagilardan biri sifatida: %s, --near ni esa kenglik,uzunlik ko‘rinishida bering.",
+		"command.field_missing":      "«%s» maydoni ko‘rsatilmagan, uni --detail bilan bering.",
+		"command.unknown_field":      "Xizmatda «%s» maydoni yo‘q.",
+		"command.no_default":         "--from ko‘rsatilmagan va asosiy hisob tanlanmagan.",
+		"command.account_usage":      "--account va --nickname yoki --default ni ko‘rsating.",
+		"command.usage": `Foydalanish: client [bayroqlar] [buyruq] [buyruq bayroqlari]
+
+Buyruqsiz interaktiv menyu ishga tushadi.
+
+Bayroqlar:
+  --config                                    sozlamalar fayli (.toml, .json, .yaml)
+  --driver                                    baza drayveri: sqlite3, postgres
+  --dsn                                       bazaga ulanish satri
+  --log-path                                  jurnal fayli
+  --log-level                                 jurnal darajasi: debug, info, warn, error
+  --log-format                                jurnal formati: text, json
+  --log-max-size                              aylantirishgacha jurnal hajmi MB da (0 cheklovsiz)
+  --log-max-age                               aylantirishgacha jurnal yoshi kunlarda (0 cheklovsiz)
+  --log-max-backups                           saqlanadigan eski jurnallar soni
+  --page-size                                 sahifadagi qatorlar soni
+  --lang                                      til: ru, en, uz (standart qiymat LANG dan)
+  --export-dir                                eksport papkasi
+  --session-idle                              seans tugaguncha kiritishsiz daqiqalar (0 cheklovsiz)
+  --session-max                               seansning eng uzoq davomiyligi daqiqalarda (0 cheklovsiz)
+  --login-max-failures                        bloklashgacha muvaffaqiyatsiz kirishlar (0 cheklovsiz)
+  --login-window                              muvaffaqiyatsiz kirishlar necha daqiqada sanaladi
+  --login-delay                               muvaffaqiyatsiz kirishdan keyingi pauza soniyalarda, har safar ikki barobar
+
+Buyruqlar:
+  atms     --search --feature --open-now
+           --near                             bankomatlar ro‘yxati
+  accounts --login                            hisoblar ro‘yxati
+  account  --login --account
+           --nickname --default               hisob nomi, asosiy hisob
+  transfer --login --from --amount
+           (--to-account | --to-phone)        pul o‘tkazish
+  pay      --login --from --service --amount
+           --detail                           xizmat uchun to‘lash
+  journal  --login --limit --offset
+           --from --to --type --min --max
+           --counterparty --service --search  amallar tarixi
+  statement --login --account --from --to
+            --format --out                    hisob ko‘chirmasi
+  config show                                 amaldagi sozlamalarni chiqarish
+
+Har bir bayroqni IBANK_<BAYROQ> o‘zgaruvchisi, masalan IBANK_PAGE_SIZE, yoki
+joriy papkadagi ibank.toml, ibank.yaml yoki ibank.json fayli orqali berish mumkin.
+
+Loginni IBANK_LOGIN o‘zgaruvchisi bilan berish mumkin, parol
+IBANK_PASSWORD dan olinadi yoki stdin dan o‘qiladi. Ikki bosqichli
+autentifikatsiyaning bir martalik kodi IBANK_CODE dan yoki stdin dan olinadi.
+
+Chiqish kodlari: 0 muvaffaqiyat, 1 xato, 2 noto‘g‘ri argumentlar,
+3 login, parol yoki kod noto‘g‘ri, 4 mijoz bloklangan,
+5 mablag‘ yetarli emas.
+`,
+	},
+}
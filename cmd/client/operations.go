@@ -1,41 +0,0 @@
-package main
-
-const welcomeTitle = "Добро пожаловать!"
-
-const unauthorisedOperations = `1.  Войти
-2.  Получить список банкоматов
-q.  Выход
-
-Выберите операцию: `
-
-const authorisedOperations = `1.  Посмотреть список счетов
-2.  Перевести деньги другому клиенту
-3.  Оплатить услугу
-4.  Просмотреть журнал
-q.  Назад
-
-Выберите операцию: `
-
-const pagingOperations = `1.  cлед >
-2.  < пред
-q.  назад
-
-Выберите команду: `
-
-const transferMoneyOperations = `1.  По номеру счета
-2.  По номеру телефона
-q.  Назад
-
-Выберите операцию: `
-
-const loginTitle = `+-------+
-| Войти |
-+-------+`
-
-const transferTitle = `+---------------+
-| Перевод денег |
-+---------------+`
-
-const payForServiceTitle = `+---------------+
-| Оплата услуги |
-+---------------+`
\ No newline at end of file
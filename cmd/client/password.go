@@ -0,0 +1,83 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/auth"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+)
+
+// changePassword returns common.ErrMenuExit once the password is changed, the change
+// revokes the current session too, or once wrong current passwords locked the client.
+func changePassword(login string, db *sql.DB) error {
+	fmt.Println(common.Box(common.T("password.box")))
+	logger.Debug("asking to enter current password")
+	current, err := common.GetPasswordInput(common.T("password.prompt.current"))
+	if err != nil {
+		logger.Warnf("unable to read current password: %v", err)
+		return nil
+	}
+	logger.Debug("current password entered")
+
+	logger.Debug("asking to enter new password")
+	var password string
+	for {
+		password, err = common.GetNewPasswordInput(common.T("password.prompt.new"), common.T("password.prompt.repeat"))
+		if err != nil {
+			logger.Warnf("unable to read new password: %v", err)
+			return nil
+		}
+		err = auth.ValidatePassword(login, password)
+		if err == nil {
+			break
+		}
+		logger.Warnf("invalid new password: %v", err)
+		fmt.Println(passwordMessage(err))
+	}
+	logger.Debug("new password entered")
+	common.ClearConsole()
+
+	err = auth.ChangePassword(login, current, password, settings.LoginPolicy(), db)
+	var delay *auth.DelayError
+	if errors.As(err, &delay) {
+		logger.Warnf("password change too soon after failures: %v", err)
+		fmt.Println(common.T("login.wait", int64(delay.Wait.Seconds())))
+		return nil
+	}
+	if errors.Is(err, auth.ErrLockedOut) {
+		logger.Warn("client locked after failed password changes")
+		fmt.Println(common.T("login.locked_out"))
+		return common.ErrMenuExit
+	}
+	if err != nil {
+		if errors.Is(err, core.ErrInvalidPass) || errors.Is(err, auth.ErrSamePassword) {
+			logger.Warnf("password not changed: %v", err)
+		} else {
+			logger.Errorf("unable to change password: %v", err)
+		}
+		fmt.Println(passwordMessage(err))
+		return nil
+	}
+	logger.Info("password changed, sessions revoked")
+	fmt.Println(common.T("password.changed"))
+	return common.ErrMenuExit
+}
+
+func passwordMessage(err error) string {
+	switch {
+	case errors.Is(err, core.ErrInvalidPass):
+		return common.T("password.invalid")
+	case errors.Is(err, auth.ErrPasswordTooShort):
+		return common.T("password.too_short", auth.MinPasswordLength)
+	case errors.Is(err, auth.ErrPasswordTooWeak):
+		return common.T("password.too_weak")
+	case errors.Is(err, auth.ErrPasswordIsLogin):
+		return common.T("password.has_login")
+	case errors.Is(err, auth.ErrSamePassword):
+		return common.T("password.same")
+	}
+	return common.T("password.failed")
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+)
+
+func payeesMenu(login string, phoneNumber int64, db *sql.DB) *common.Menu {
+	var payees []bank.Payee
+	hasPayees := func() bool {
+		return len(payees) > 0
+	}
+	return &common.Menu{
+		Title: common.T("payees.title"),
+		Before: func() error {
+			fmt.Println(common.Box(common.T("payees.title")))
+			var err error
+			payees, err = bank.GetPayees(login, db)
+			if err != nil {
+				logger.Errorf("unable to get list of payees: %v", err)
+				fmt.Println(common.T("payees.error"))
+				return common.ErrMenuExit
+			}
+			currencyOf, err := accountCurrencies(login, db)
+			if err != nil {
+				logger.Errorf("unable to get list of client accounts: %v", err)
+				fmt.Println(common.T("payees.error"))
+				return common.ErrMenuExit
+			}
+			printPayees(payees, currencyOf)
+			return nil
+		},
+		Items: []common.MenuItem{
+			{Label: common.T("payees.transfer"), Log: "transfer to payee operation selected", Guard: hasPayees, Handler: func() error {
+				payee, ok := choosePayee(payees)
+				if ok {
+					transferToPayee(payee, phoneNumber, login, db)
+				}
+				return nil
+			}},
+			{Label: common.T("payees.rename"), Log: "rename payee operation selected", Guard: hasPayees, Handler: func() error {
+				payee, ok := choosePayee(payees)
+				if ok {
+					renamePayee(payee, db)
+				}
+				return nil
+			}},
+			{Label: common.T("payees.delete"), Log: "delete payee operation selected", Guard: hasPayees, Handler: func() error {
+				payee, ok := choosePayee(payees)
+				if ok {
+					deletePayee(payee, db)
+				}
+				return nil
+			}},
+		},
+	}
+}
+
+func printPayees(payees []bank.Payee, currencyOf func(accountId int64) money.Currency) {
+	if len(payees) == 0 {
+		fmt.Println(common.T("payees.empty"))
+		return
+	}
+	for idx, payee := range payees {
+		fmt.Println(common.T("payees.item", idx+1, payee.Name, payeeTarget(payee)))
+		if payee.IsTemplate() {
+			fmt.Println(common.T("payees.template", payee.Amount.Format(currencyOf(payee.FromAccountId)), payee.FromAccountId))
+		}
+	}
+}
+
+func payeeTarget(payee bank.Payee) string {
+	if payee.AccountId != 0 {
+		return common.T("payees.account", payee.AccountId)
+	}
+	return common.T("payees.phone", payee.PhoneNumber)
+}
+
+func choosePayee(payees []bank.Payee) (payee bank.Payee, ok bool) {
+	logger.Debug("asking to enter payee number")
+	number, err := common.GetIntegerInput(common.T("payees.prompt.number"))
+	if err != nil {
+		logger.Warnf("unable to read payee number: %v", err)
+		return payee, false
+	}
+	if number < 1 || number > int64(len(payees)) {
+		logger.Warnf("invalid payee number: %d", number)
+		fmt.Println(common.T("payees.invalid_number"))
+		return payee, false
+	}
+	logger.Debug("payee number entered")
+	return payees[number-1], true
+}
+
+// transferToPayee asks for the amount and the source account, templates offer their own ones.
+func transferToPayee(payee bank.Payee, phoneNumber int64, login string, db *sql.DB) {
+	var amount money.Money
+	var err error
+	logger.Debug("asking to enter amount")
+	if payee.IsTemplate() {
+		amount, err = common.GetAmountInputOr(common.T("transfer.prompt.amount"), payee.Amount)
+	} else {
+		amount, err = common.GetAmountInput(common.T("transfer.prompt.amount"))
+	}
+	if err != nil {
+		logger.Warnf("unable to read amount: %v", err)
+		return
+	}
+	logger.Debug("amount entered")
+
+	account, ok := chooseAccount(login, payee.FromAccountId, amount, db)
+	if !ok {
+		return
+	}
+	common.ClearConsole()
+	transferTo(payee, phoneNumber, login, account.Id, amount, db)
+}
+
+// offerToSavePayee adds the recipient of a successful transfer to the payee book if the client wants it.
+func offerToSavePayee(payee bank.Payee, accountId int64, amount money.Money, db *sql.DB) {
+	logger.Debug("asking to save payee")
+	save, err := common.GetConfirmInput(common.T("payees.prompt.save"))
+	if err != nil {
+		logger.Warnf("unable to read answer: %v", err)
+		return
+	}
+	if !save {
+		return
+	}
+	payee.Name, err = common.GetStringInput(common.T("payees.prompt.name"))
+	if err != nil {
+		logger.Warnf("unable to read payee name: %v", err)
+		return
+	}
+	template, err := common.GetConfirmInput(common.T("payees.prompt.template"))
+	if err != nil {
+		logger.Warnf("unable to read answer: %v", err)
+		return
+	}
+	if template {
+		payee.FromAccountId, payee.Amount = accountId, amount
+	}
+
+	err = bank.AddPayee(payee, db)
+	if err != nil {
+		if errors.Is(err, bank.ErrPayeeExist) {
+			logger.Warn("payee already exists")
+			fmt.Println(common.T("payees.exist", payee.Name))
+			return
+		}
+		logger.Errorf("unable to save payee: %v", err)
+		fmt.Println(common.T("payees.failed"))
+		return
+	}
+	logger.Info("payee saved")
+	fmt.Println(common.T("payees.saved", payee.Name))
+}
+
+func renamePayee(payee bank.Payee, db *sql.DB) {
+	logger.Debug("asking to enter payee name")
+	name, err := common.GetStringInput(common.T("payees.prompt.name"))
+	if err != nil {
+		logger.Warnf("unable to read payee name: %v", err)
+		return
+	}
+	common.ClearConsole()
+	err = bank.RenamePayee(payee.Login, payee.Id, name, db)
+	if err != nil {
+		if errors.Is(err, bank.ErrPayeeExist) {
+			logger.Warn("payee already exists")
+			fmt.Println(common.T("payees.exist", name))
+			return
+		}
+		logger.Errorf("unable to rename payee: %v", err)
+		fmt.Println(common.T("payees.failed"))
+		return
+	}
+	logger.Info("payee renamed")
+	fmt.Println(common.T("payees.renamed", name))
+}
+
+func deletePayee(payee bank.Payee, db *sql.DB) {
+	logger.Debug("asking to confirm payee deletion")
+	confirmed, err := common.GetConfirmInput(common.T("payees.prompt.delete", payee.Name))
+	if err != nil {
+		logger.Warnf("unable to read answer: %v", err)
+		return
+	}
+	common.ClearConsole()
+	if !confirmed {
+		return
+	}
+	err = bank.DeletePayee(payee.Login, payee.Id, db)
+	if err != nil {
+		logger.Errorf("unable to delete payee: %v", err)
+		fmt.Println(common.T("payees.failed"))
+		return
+	}
+	logger.Info("payee deleted")
+	fmt.Println(common.T("payees.deleted", payee.Name))
+}
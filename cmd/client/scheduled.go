@@ -0,0 +1,242 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"strconv"
+	"time"
+)
+
+func scheduledMenu(login string, phoneNumber int64, db *sql.DB) *common.Menu {
+	var orders []bank.ScheduledTransfer
+	return &common.Menu{
+		Title: common.T("scheduled.title"),
+		Before: func() error {
+			fmt.Println(common.Box(common.T("scheduled.title")))
+			var err error
+			orders, err = bank.GetScheduledTransfers(login, db)
+			if err != nil {
+				logger.Errorf("unable to get list of scheduled transfers: %v", err)
+				fmt.Println(common.T("scheduled.error"))
+				return common.ErrMenuExit
+			}
+			currencyOf, err := accountCurrencies(login, db)
+			if err != nil {
+				logger.Errorf("unable to get list of client accounts: %v", err)
+				fmt.Println(common.T("scheduled.error"))
+				return common.ErrMenuExit
+			}
+			printScheduledTransfers(orders, currencyOf)
+			return nil
+		},
+		Items: []common.MenuItem{
+			{Label: common.T("scheduled.by_account"), Log: "schedule transfer by account id operation selected", Handler: func() error {
+				scheduleByAccount(login, db)
+				return nil
+			}},
+			{Label: common.T("scheduled.by_phone"), Log: "schedule transfer by phone number operation selected", Handler: func() error {
+				scheduleByPhoneNumber(phoneNumber, login, db)
+				return nil
+			}},
+			{Label: common.T("scheduled.cancel"), Log: "cancel scheduled transfer operation selected", Guard: func() bool {
+				return len(orders) > 0
+			}, Handler: func() error {
+				cancelScheduledTransfer(orders, login, db)
+				return nil
+			}},
+		},
+	}
+}
+
+func printScheduledTransfers(orders []bank.ScheduledTransfer, currencyOf func(accountId int64) money.Currency) {
+	if len(orders) == 0 {
+		fmt.Println(common.T("scheduled.empty"))
+		return
+	}
+	for idx, order := range orders {
+		target := payeeTarget(bank.Payee{AccountId: order.AccountId, PhoneNumber: order.PhoneNumber})
+		fmt.Println(common.T("scheduled.item", idx+1, order.Amount.Format(currencyOf(order.FromAccountId)), order.FromAccountId, target, describePeriod(order)))
+		if order.Status != bank.ScheduleActive {
+			fmt.Println(common.T("scheduled.status", common.T("scheduled.status."+order.Status)))
+		} else {
+			fmt.Println(common.T("scheduled.next", order.NextDate.Format(bank.DateFormat)))
+		}
+		if order.LastError != "" {
+			fmt.Println(common.T("scheduled.last_failed", order.LastError))
+		}
+	}
+}
+
+func describePeriod(order bank.ScheduledTransfer) string {
+	switch order.Period {
+	case bank.Monthly:
+		return common.T("scheduled.period.monthly", order.MonthDay)
+	case bank.EveryDays:
+		return common.T("scheduled.period.days", order.IntervalDays)
+	}
+	return common.T("scheduled.period." + string(order.Period))
+}
+
+func scheduleByAccount(login string, db *sql.DB) {
+	fmt.Println(common.Box(common.T("scheduled.title")))
+	logger.Debug("asking to enter target account id")
+	targetAccountId, err := common.GetIntegerInput(common.T("transfer.prompt.target"))
+	if err != nil {
+		logger.Warnf("unable to read target account id: %v", err)
+		return
+	}
+	logger.Debug("target account id entered")
+	scheduleTransfer(bank.ScheduledTransfer{Login: login, AccountId: targetAccountId}, 0, db)
+}
+
+func scheduleByPhoneNumber(phoneNumber int64, login string, db *sql.DB) {
+	fmt.Println(common.Box(common.T("scheduled.title")))
+	logger.Debug("asking to enter target phone number")
+	targetPhoneNumber, err := common.GetPhoneNumberInput(common.T("transfer.prompt.phone"))
+	if err != nil {
+		logger.Warnf("unable to read target phone number: %v", err)
+		return
+	}
+	logger.Debug("target phone number entered")
+	scheduleTransfer(bank.ScheduledTransfer{Login: login, PhoneNumber: targetPhoneNumber}, phoneNumber, db)
+}
+
+// scheduleTransfer asks for the rest of order, shows it to the client and saves it once confirmed.
+func scheduleTransfer(order bank.ScheduledTransfer, phoneNumber int64, db *sql.DB) {
+	var err error
+	logger.Debug("asking to enter amount")
+	order.Amount, err = common.GetAmountInput(common.T("transfer.prompt.amount"))
+	if err != nil {
+		logger.Warnf("unable to read amount: %v", err)
+		return
+	}
+	logger.Debug("amount entered")
+
+	// the balance is checked when the transfer runs
+	account, ok := chooseAccount(order.Login, 0, 0, db)
+	if !ok {
+		return
+	}
+	order.FromAccountId = account.Id
+
+	logger.Debug("asking to enter date of first transfer")
+	order.NextDate, err = common.GetDateInput(common.T("scheduled.prompt.date"))
+	if err != nil {
+		logger.Warnf("unable to read date: %v", err)
+		return
+	}
+	logger.Debug("date entered")
+
+	if !askPeriod(&order) {
+		return
+	}
+	common.ClearConsole()
+
+	year, month, day := time.Now().Date()
+	if order.NextDate.Before(time.Date(year, month, day, 0, 0, 0, 0, time.Local)) {
+		logger.Warn("date of scheduled transfer is in the past")
+		fmt.Println(common.T("scheduled.past_date"))
+		return
+	}
+	payee := bank.Payee{AccountId: order.AccountId, PhoneNumber: order.PhoneNumber}
+	if !checkTarget(payee, phoneNumber, order.FromAccountId) {
+		return
+	}
+	recipient, ok := findRecipient(payee, db)
+	if !ok {
+		return
+	}
+
+	fmt.Println(common.T("scheduled.summary", order.Amount.Format(account.Currency), order.FromAccountId, recipient))
+	fmt.Println(common.T("scheduled.first", order.NextDate.Format(bank.DateFormat), describePeriod(order)))
+	logger.Debug("asking to confirm scheduled transfer")
+	confirmed, err := common.GetConfirmInput(common.T("scheduled.prompt.confirm"))
+	if err != nil {
+		logger.Warnf("unable to read confirmation: %v", err)
+		return
+	}
+	if !confirmed {
+		logger.Info("scheduled transfer cancelled by client")
+		fmt.Println(common.T("transfer.cancelled"))
+		return
+	}
+
+	id, err := bank.AddScheduledTransfer(order, db)
+	if err != nil {
+		logger.Errorf("unable to schedule transfer: %v", err)
+		fmt.Println(common.T("scheduled.failed"))
+		return
+	}
+	logger.Infof("transfer %d scheduled", id)
+	fmt.Println(common.T("scheduled.done"))
+}
+
+func askPeriod(order *bank.ScheduledTransfer) bool {
+	choices := make([]string, len(bank.Periods))
+	for idx, period := range bank.Periods {
+		choices[idx] = strconv.Itoa(idx + 1)
+		fmt.Println(common.T("scheduled.choice."+string(period), idx+1))
+	}
+	logger.Debug("asking to choose period")
+	choice, err := common.GetChoiceInput(common.T("scheduled.prompt.period"), choices...)
+	if err != nil {
+		logger.Warnf("unable to read period: %v", err)
+		return false
+	}
+	index, _ := strconv.Atoi(choice)
+	order.Period = bank.Periods[index-1]
+	order.MonthDay = order.NextDate.Day()
+	logger.Debugf("period %s chosen", order.Period)
+	if order.Period != bank.EveryDays {
+		return true
+	}
+
+	logger.Debug("asking to enter interval in days")
+	for {
+		order.IntervalDays, err = common.GetIntegerInput(common.T("scheduled.prompt.days"))
+		if err != nil {
+			logger.Warnf("unable to read interval: %v", err)
+			return false
+		}
+		if order.IntervalDays > 0 {
+			break
+		}
+		fmt.Println(common.T("scheduled.invalid_days"))
+	}
+	logger.Debug("interval entered")
+	return true
+}
+
+func cancelScheduledTransfer(orders []bank.ScheduledTransfer, login string, db *sql.DB) {
+	logger.Debug("asking to enter scheduled transfer number")
+	number, err := common.GetIntegerInput(common.T("scheduled.prompt.number"))
+	if err != nil {
+		logger.Warnf("unable to read scheduled transfer number: %v", err)
+		return
+	}
+	common.ClearConsole()
+	if number < 1 || number > int64(len(orders)) {
+		logger.Warnf("invalid scheduled transfer number: %d", number)
+		fmt.Println(common.T("scheduled.invalid_number"))
+		return
+	}
+	order := orders[number-1]
+	err = bank.CancelScheduledTransfer(login, order.Id, db)
+	if err != nil {
+		if errors.Is(err, bank.ErrScheduledNotExist) {
+			logger.Warnf("scheduled transfer %d is not active", order.Id)
+			fmt.Println(common.T("scheduled.not_active"))
+			return
+		}
+		logger.Errorf("unable to cancel scheduled transfer: %v", err)
+		fmt.Println(common.T("scheduled.failed"))
+		return
+	}
+	logger.Infof("scheduled transfer %d cancelled", order.Id)
+	fmt.Println(common.T("scheduled.cancelled"))
+}
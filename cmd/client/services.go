@@ -0,0 +1,86 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"strings"
+)
+
+// chooseService lists the services under their categories and asks the client to pick one.
+func chooseService(db *sql.DB) (service bank.Service, ok bool) {
+	services, err := bank.GetServices(db)
+	if err != nil {
+		logger.Errorf("unable to get list of services: %v", err)
+		fmt.Println(common.T("pay.services_error"))
+		return service, false
+	}
+	if len(services) == 0 {
+		logger.Info("list of services is empty")
+		fmt.Println(common.T("pay.no_services"))
+		return service, false
+	}
+	for idx, service := range services {
+		if idx == 0 || service.Category != services[idx-1].Category {
+			category := service.Category
+			if category == "" {
+				category = common.T("pay.other")
+			}
+			fmt.Println(common.T("pay.category", category))
+		}
+		fmt.Println(common.T("pay.service_item", idx+1, service.Name))
+	}
+
+	logger.Debug("asking to enter service number")
+	number, err := common.GetIntegerInput(common.T("pay.prompt.number"))
+	if err != nil {
+		logger.Warnf("unable to read service number: %v", err)
+		return service, false
+	}
+	if number < 1 || number > int64(len(services)) {
+		logger.Warnf("invalid service number: %d", number)
+		fmt.Println(common.T("pay.invalid_number"))
+		return service, false
+	}
+	logger.Debug("service number entered")
+	return services[number-1], true
+}
+
+// askDetails asks for the fields of service until each of them has a valid value.
+func askDetails(service bank.Service) (details []bank.PaymentDetail, ok bool) {
+	for _, field := range service.Fields {
+		for {
+			logger.Debugf("asking to enter %s", field.Name)
+			value, err := common.GetStringInput(common.T("pay.prompt.field", field.Name, describeRule(field)))
+			if err != nil {
+				logger.Warnf("unable to read %s: %v", field.Name, err)
+				return nil, false
+			}
+			value, err = field.Validate(value)
+			if err == nil {
+				details = append(details, bank.PaymentDetail{Name: field.Name, Value: value})
+				break
+			}
+			logger.Warnf("invalid %s: %v", field.Name, err)
+			fmt.Println(common.T("pay.field_invalid", field.Name))
+		}
+	}
+	return details, true
+}
+
+func describeRule(field bank.ServiceField) string {
+	rule := []string{common.T("pay.rule." + field.Kind)}
+	switch {
+	case field.MinLength > 0 && field.MinLength == field.MaxLength:
+		rule = append(rule, common.T("pay.rule.exact", field.MinLength))
+	case field.MinLength > 0 && field.MaxLength > 0:
+		rule = append(rule, common.T("pay.rule.length", field.MinLength, field.MaxLength))
+	case field.MinLength > 0:
+		rule = append(rule, common.T("pay.rule.min", field.MinLength))
+	case field.MaxLength > 0:
+		rule = append(rule, common.T("pay.rule.max", field.MaxLength))
+	}
+	return strings.Join(rule, ", ")
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// exportPath places name in the configured export directory.
+func exportPath(name string) string {
+	return filepath.Join(settings.ExportDir, name)
+}
+
+func statementOperations(login string, db *sql.DB) {
+	fmt.Println(common.Box(common.T("statement.box")))
+	account, ok := chooseAccount(login, 0, 0, db)
+	if !ok {
+		return
+	}
+	accountId := account.Id
+
+	logger.Debug("asking to enter period")
+	from, err := common.GetDateInput(common.T("statement.prompt.from"))
+	if err != nil {
+		logger.Warnf("unable to read date: %v", err)
+		return
+	}
+	to, err := common.GetDateInput(common.T("statement.prompt.to"))
+	if err != nil {
+		logger.Warnf("unable to read date: %v", err)
+		return
+	}
+	logger.Debug("period entered")
+
+	logger.Debug("asking to choose format")
+	format, err := common.GetChoiceInput(common.T("statement.prompt.format", strings.Join(common.StatementFormats, ", ")), common.StatementFormats...)
+	if err != nil {
+		logger.Warnf("unable to read format: %v", err)
+		return
+	}
+	logger.Debug("format chosen")
+	common.ClearConsole()
+
+	if to.Before(from) {
+		logger.Warn("invalid period of statement")
+		fmt.Println(common.T("statement.invalid_period"))
+		return
+	}
+	statement, err := bank.GetStatement(accountId, from, to, db)
+	if err != nil {
+		logger.Errorf("unable to make statement: %v", err)
+		fmt.Println(common.T("statement.failed"))
+		return
+	}
+	data, err := common.RenderStatement(statement, format)
+	if err != nil {
+		logger.Errorf("unable to render statement: %v", err)
+		fmt.Println(common.T("statement.failed"))
+		return
+	}
+	fileName := exportPath(common.StatementFileName(statement, format))
+	err = common.WriteExport(fileName, data)
+	if err != nil {
+		logger.Errorf("can't write file %s: %v", fileName, err)
+		fmt.Println(common.T("statement.failed"))
+		return
+	}
+	logger.Infof("statement of account %d written to %s", accountId, fileName)
+	fmt.Println(common.T("statement.done", fileName))
+}
+
+// periodFlags registers --from and --to, the period defaults to the current month.
+func periodFlags(flags *flag.FlagSet) (from, to *string) {
+	now := time.Now()
+	firstDay := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	from = flags.String("from", firstDay.Format(bank.DateFormat), "first day of the period")
+	to = flags.String("to", now.Format(bank.DateFormat), "last day of the period")
+	return from, to
+}
+
+func statementCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("statement")
+	login := loginFlag(flags)
+	accountId := flags.Int64("account", 0, "account id")
+	fromFlag, toFlag := periodFlags(flags)
+	format := flags.String("format", "txt", strings.Join(common.StatementFormats, ", "))
+	out := flags.String("out", "", "output file, - for stdout (default statement-<account>-<from>-<to>.<format>)")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	from, fromErr := common.ParseDate(*fromFlag)
+	to, toErr := common.ParseDate(*toFlag)
+	if *accountId <= 0 || fromErr != nil || toErr != nil || to.Before(from) {
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.statement_usage"))
+		return exitUsage
+	}
+	if _, code := authenticate(*login, db); code != exitOk {
+		return code
+	}
+	if _, ok, err := checkAccountIfValid(*login, db, *accountId); !ok {
+		logger.Warnf("account %d of %s not found: %v", *accountId, *login, err)
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.invalid_account"))
+		return exitFailure
+	}
+
+	statement, err := bank.GetStatement(*accountId, from, to, db)
+	if err != nil {
+		logger.Errorf("unable to make statement: %v", err)
+		return exitFailure
+	}
+	data, err := common.RenderStatement(statement, *format)
+	if errors.Is(err, common.ErrUnknownFormat) {
+		_, _ = fmt.Fprintln(os.Stderr, common.T("command.statement_usage"))
+		return exitUsage
+	}
+	if err != nil {
+		logger.Errorf("unable to render statement: %v", err)
+		return exitFailure
+	}
+	fileName := *out
+	if fileName == "" {
+		fileName = exportPath(common.StatementFileName(statement, *format))
+	}
+	if fileName == "-" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = common.WriteExport(fileName, data)
+	}
+	if err != nil {
+		logger.Errorf("can't write statement: %v", err)
+		return exitFailure
+	}
+	logger.Infof("statement of account %d written to %s", *accountId, fileName)
+	return exitOk
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/auth"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"strings"
+)
+
+func twoFactorOperations(login string, db *sql.DB) {
+	fmt.Println(common.Box(common.T("twofactor.box")))
+	enabled, err := auth.TwoFactorEnabled(login, db)
+	if err != nil {
+		logger.Errorf("unable to get two-factor authentication of client: %v", err)
+		fmt.Println(common.T("twofactor.failed"))
+		return
+	}
+	if enabled {
+		left, err := auth.RecoveryCodesLeft(login, db)
+		if err != nil {
+			logger.Errorf("unable to count recovery codes: %v", err)
+			fmt.Println(common.T("twofactor.failed"))
+			return
+		}
+		logger.Info("two-factor authentication already enabled")
+		fmt.Println(common.T("twofactor.already", left))
+		return
+	}
+	enrolTwoFactor(login, db)
+}
+
+// enrolTwoFactor shows a new secret and enables it once the client enters a code made from it.
+func enrolTwoFactor(login string, db *sql.DB) {
+	secret, err := auth.NewSecret()
+	if err != nil {
+		logger.Errorf("unable to make secret: %v", err)
+		fmt.Println(common.T("twofactor.failed"))
+		return
+	}
+	code, err := common.QRCode(auth.KeyURI(login, secret))
+	if err != nil {
+		logger.Errorf("unable to draw QR code: %v", err)
+	} else {
+		fmt.Println(common.T("twofactor.scan"))
+		fmt.Print(code)
+	}
+	fmt.Println(common.T("twofactor.secret", groupSecret(secret)))
+
+	logger.Debug("asking to enter one-time code")
+	code, err = common.GetStringInput(common.T("twofactor.prompt.code", auth.CodeLength))
+	if err != nil {
+		logger.Warnf("unable to read one-time code: %v", err)
+		return
+	}
+	logger.Debug("one-time code entered")
+	common.ClearConsole()
+
+	recovery, err := auth.EnableTwoFactor(login, secret, code, db)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCode) {
+			logger.Warn("invalid one-time code")
+			fmt.Println(common.T("twofactor.invalid"))
+			return
+		}
+		logger.Errorf("unable to enable two-factor authentication: %v", err)
+		fmt.Println(common.T("twofactor.failed"))
+		return
+	}
+	logger.Info("two-factor authentication enabled")
+	fmt.Println(common.T("twofactor.enabled"))
+	fmt.Println(common.T("twofactor.recovery"))
+	for _, code := range recovery {
+		fmt.Println("  " + code)
+	}
+}
+
+// groupSecret splits secret in groups of four letters, as authenticators show it.
+func groupSecret(secret string) string {
+	var groups []string
+	for len(secret) > 4 {
+		groups = append(groups, secret[:4])
+		secret = secret[4:]
+	}
+	return strings.Join(append(groups, secret), " ")
+}
@@ -0,0 +1,127 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+type Language string
+
+const (
+	Russian Language = "ru"
+	English Language = "en"
+	Uzbek   Language = "uz"
+)
+
+var Languages = []Language{Russian, English, Uzbek}
+
+const defaultLanguage = Russian
+
+var (
+	language = defaultLanguage
+	catalog  = map[Language]map[string]string{}
+)
+
+// AddMessages registers translations for lang, existing ids are overwritten.
+func AddMessages(lang Language, messages map[string]string) {
+	if catalog[lang] == nil {
+		catalog[lang] = make(map[string]string, len(messages))
+	}
+	for id, message := range messages {
+		catalog[lang][id] = message
+	}
+}
+
+// ParseLanguage accepts a language code or a locale such as "uz_UZ.UTF-8".
+func ParseLanguage(value string) (Language, bool) {
+	code := strings.ToLower(value)
+	if index := strings.IndexAny(code, "_-.@"); index >= 0 {
+		code = code[:index]
+	}
+	for _, lang := range Languages {
+		if Language(code) == lang {
+			return lang, true
+		}
+	}
+	return defaultLanguage, false
+}
+
+// DetectLanguage returns the language given by the --lang flag value,
+// falling back to the LANG environment variable and then to Russian.
+func DetectLanguage(flagValue string) Language {
+	for _, value := range []string{flagValue, os.Getenv("LANG")} {
+		if lang, ok := ParseLanguage(value); ok {
+			return lang
+		}
+	}
+	return defaultLanguage
+}
+
+func SetLanguage(lang Language) {
+	language = lang
+}
+
+func CurrentLanguage() Language {
+	return language
+}
+
+func lookup(id string) (string, bool) {
+	if message, ok := catalog[language][id]; ok {
+		return message, true
+	}
+	message, ok := catalog[defaultLanguage][id]
+	return message, ok
+}
+
+// T returns the message with id in the current language, formatted with args.
+// Messages missing from the current language fall back to Russian, unknown ids are returned as is.
+func T(id string, args ...interface{}) string {
+	message, ok := lookup(id)
+	if !ok {
+		message = id
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// N returns the plural form of the message with id matching count.
+// Forms are stored under id.one, id.few, id.many and id.other.
+func N(id string, count int64, args ...interface{}) string {
+	for _, form := range pluralForms(count) {
+		if _, ok := lookup(id + "." + form); ok {
+			return T(id+"."+form, args...)
+		}
+	}
+	return T(id+".other", args...)
+}
+
+func pluralForms(count int64) []string {
+	if count < 0 {
+		count = -count
+	}
+	switch language {
+	case Russian:
+		switch {
+		case count%10 == 1 && count%100 != 11:
+			return []string{"one", "many"}
+		case count%10 >= 2 && count%10 <= 4 && (count%100 < 12 || count%100 > 14):
+			return []string{"few", "many"}
+		}
+		return []string{"many"}
+	case English:
+		if count == 1 {
+			return []string{"one"}
+		}
+	}
+	return []string{"other"}
+}
+
+// Box draws a frame around title sized to its length.
+func Box(title string) string {
+	border := "+" + strings.Repeat("-", utf8.RuneCountInString(title)+2) + "+"
+	return border + "\n| " + title + " |\n" + border
+}
@@ -0,0 +1,353 @@
+package common
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInputClosed = errors.New("input closed")
+
+type Input struct {
+	reader  *bufio.Reader
+	writer  io.Writer
+	file    *os.File
+	session *session
+}
+
+var input = NewInput(os.Stdin, os.Stdout)
+
+func NewInput(reader io.Reader, writer io.Writer) *Input {
+	newInput := &Input{reader: bufio.NewReader(reader), writer: writer}
+	if file, ok := reader.(*os.File); ok {
+		newInput.file = file
+	}
+	return newInput
+}
+
+// SetInput replaces the reader and writer used by the package level Get*Input functions.
+func SetInput(reader io.Reader, writer io.Writer) {
+	input = NewInput(reader, writer)
+}
+
+func (receiver *Input) ReadLine() (line string, err error) {
+	line, err = receiver.reader.ReadString('\n')
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		if line == "" {
+			_, _ = fmt.Fprintln(receiver.writer)
+			return "", ErrInputClosed
+		}
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// ask prints prompt and reads lines until validate accepts one.
+// validate returns an empty string for a valid line and an error message otherwise.
+func (receiver *Input) ask(prompt string, validate func(line string) string) (line string, err error) {
+	return receiver.askWith(prompt, receiver.ReadLine, validate)
+}
+
+func (receiver *Input) askWith(prompt string, read func() (string, error), validate func(line string) string) (line string, err error) {
+	if err = receiver.session.expired(); err != nil {
+		return "", err
+	}
+	for {
+		_, _ = fmt.Fprint(receiver.writer, prompt)
+		line, err = read()
+		if err != nil {
+			return "", err
+		}
+		if err = receiver.session.check(time.Now()); err != nil {
+			return "", err
+		}
+		message := validate(line)
+		if message == "" {
+			return line, nil
+		}
+		_, _ = fmt.Fprintln(receiver.writer, message)
+	}
+}
+
+func (receiver *Input) GetCommand(prompt string) (string, error) {
+	return receiver.ask(prompt, func(line string) string {
+		return ""
+	})
+}
+
+func (receiver *Input) GetStringInput(prompt string) (string, error) {
+	return receiver.ask(prompt, func(line string) string {
+		if line == "" {
+			return T("input.empty")
+		}
+		return ""
+	})
+}
+
+func (receiver *Input) GetIntegerInput(prompt string) (number int64, err error) {
+	_, err = receiver.ask(prompt, func(line string) string {
+		var parseErr error
+		number, parseErr = strconv.ParseInt(line, 10, 64)
+		if parseErr != nil {
+			return T("input.not_integer")
+		}
+		return ""
+	})
+	return number, err
+}
+
+func (receiver *Input) GetAmountInput(prompt string) (amount money.Money, err error) {
+	_, err = receiver.ask(prompt, func(line string) string {
+		var parseErr error
+		amount, parseErr = money.ParseAmount(line)
+		if parseErr != nil {
+			return T("input.invalid_amount")
+		}
+		return ""
+	})
+	return amount, err
+}
+
+// GetIntegerInputOr is GetIntegerInput that takes an empty line as value.
+func (receiver *Input) GetIntegerInputOr(prompt string, value int64) (number int64, err error) {
+	_, err = receiver.ask(withDefault(prompt, strconv.FormatInt(value, 10)), func(line string) string {
+		if line == "" {
+			number = value
+			return ""
+		}
+		var parseErr error
+		number, parseErr = strconv.ParseInt(line, 10, 64)
+		if parseErr != nil {
+			return T("input.not_integer")
+		}
+		return ""
+	})
+	return number, err
+}
+
+// GetAmountInputOr is GetAmountInput that takes an empty line as value.
+func (receiver *Input) GetAmountInputOr(prompt string, value money.Money) (amount money.Money, err error) {
+	_, err = receiver.ask(withDefault(prompt, value.Decimal()), func(line string) string {
+		if line == "" {
+			amount = value
+			return ""
+		}
+		var parseErr error
+		amount, parseErr = money.ParseAmount(line)
+		if parseErr != nil {
+			return T("input.invalid_amount")
+		}
+		return ""
+	})
+	return amount, err
+}
+
+// GetOptionalAmountInput is GetAmountInput that takes an empty line as zero.
+func (receiver *Input) GetOptionalAmountInput(prompt string) (amount money.Money, err error) {
+	_, err = receiver.ask(prompt, func(line string) string {
+		if line == "" {
+			amount = 0
+			return ""
+		}
+		var parseErr error
+		amount, parseErr = money.ParseAmount(line)
+		if parseErr != nil {
+			return T("input.invalid_amount")
+		}
+		return ""
+	})
+	return amount, err
+}
+
+// GetOptionalStringInput accepts any line, an empty one too.
+func (receiver *Input) GetOptionalStringInput(prompt string) (string, error) {
+	return receiver.ask(prompt, func(line string) string {
+		return ""
+	})
+}
+
+// withDefault shows value in brackets before the colon of prompt.
+func withDefault(prompt, value string) string {
+	trimmed := strings.TrimRight(prompt, " ")
+	if strings.HasSuffix(trimmed, ":") {
+		return strings.TrimSuffix(trimmed, ":") + " [" + value + "]: "
+	}
+	return prompt + "[" + value + "] "
+}
+
+func (receiver *Input) GetPhoneNumberInput(prompt string) (phoneNumber int64, err error) {
+	_, err = receiver.ask(prompt, func(line string) string {
+		var parseErr error
+		phoneNumber, parseErr = parsePhoneNumber(line)
+		if parseErr != nil {
+			return T("input.invalid_phone")
+		}
+		return ""
+	})
+	return phoneNumber, err
+}
+
+// GetChoiceInput accepts one of choices, ignoring case, and returns it as listed in choices.
+func (receiver *Input) GetChoiceInput(prompt string, choices ...string) (choice string, err error) {
+	_, err = receiver.ask(prompt, func(line string) string {
+		for _, candidate := range choices {
+			if strings.EqualFold(line, candidate) {
+				choice = candidate
+				return ""
+			}
+		}
+		return T("input.invalid_value", strings.Join(choices, ", "))
+	})
+	return choice, err
+}
+
+// DateLayouts are the accepted forms of dates, the first one is used to print them.
+var DateLayouts = []string{"2006-01-02", "02.01.2006"}
+
+func (receiver *Input) GetDateInput(prompt string) (date time.Time, err error) {
+	_, err = receiver.ask(prompt, func(line string) string {
+		var parseErr error
+		date, parseErr = ParseDate(line)
+		if parseErr != nil {
+			return T("input.invalid_date")
+		}
+		return ""
+	})
+	return date, err
+}
+
+// GetOptionalDateInput is GetDateInput that takes an empty line as the zero time.
+func (receiver *Input) GetOptionalDateInput(prompt string) (date time.Time, err error) {
+	_, err = receiver.ask(prompt, func(line string) string {
+		if line == "" {
+			date = time.Time{}
+			return ""
+		}
+		var parseErr error
+		date, parseErr = ParseDate(line)
+		if parseErr != nil {
+			return T("input.invalid_date")
+		}
+		return ""
+	})
+	return date, err
+}
+
+// ParseDate reads a date in one of DateLayouts in the local time zone.
+func ParseDate(value string) (date time.Time, err error) {
+	for _, layout := range DateLayouts {
+		date, err = time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return date, nil
+		}
+	}
+	return date, err
+}
+
+// GetConfirmInput asks a yes or no question, answered with the word of the current language or its first letter.
+func (receiver *Input) GetConfirmInput(prompt string) (confirmed bool, err error) {
+	yes, no := T("input.yes"), T("input.no")
+	_, err = receiver.ask(prompt, func(line string) string {
+		switch {
+		case answers(line, yes):
+			confirmed = true
+		case answers(line, no):
+			confirmed = false
+		default:
+			return T("input.invalid_value", yes+", "+no)
+		}
+		return ""
+	})
+	return confirmed, err
+}
+
+// answers tells if line is word or its first letter, an empty word is never answered.
+func answers(line, word string) bool {
+	if len(word) == 0 {
+		return false
+	}
+	first := []rune(word)[:1]
+	return strings.EqualFold(line, word) || strings.EqualFold(line, string(first))
+}
+
+func parsePhoneNumber(line string) (int64, error) {
+	digits := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(line)
+	digits = strings.TrimPrefix(digits, "+")
+	if len(digits) < 9 || len(digits) > 12 {
+		return 0, strconv.ErrRange
+	}
+	for _, char := range digits {
+		if char < '0' || char > '9' {
+			return 0, strconv.ErrSyntax
+		}
+	}
+	return strconv.ParseInt(digits, 10, 64)
+}
+
+func GetCommand(prompt string) (string, error) {
+	return input.GetCommand(prompt)
+}
+
+func GetStringInput(prompt string) (string, error) {
+	return input.GetStringInput(prompt)
+}
+
+func GetIntegerInput(prompt string) (int64, error) {
+	return input.GetIntegerInput(prompt)
+}
+
+func GetAmountInput(prompt string) (money.Money, error) {
+	return input.GetAmountInput(prompt)
+}
+
+func GetIntegerInputOr(prompt string, value int64) (int64, error) {
+	return input.GetIntegerInputOr(prompt, value)
+}
+
+func GetAmountInputOr(prompt string, value money.Money) (money.Money, error) {
+	return input.GetAmountInputOr(prompt, value)
+}
+
+func GetOptionalAmountInput(prompt string) (money.Money, error) {
+	return input.GetOptionalAmountInput(prompt)
+}
+
+func GetOptionalStringInput(prompt string) (string, error) {
+	return input.GetOptionalStringInput(prompt)
+}
+
+func GetPhoneNumberInput(prompt string) (int64, error) {
+	return input.GetPhoneNumberInput(prompt)
+}
+
+func GetChoiceInput(prompt string, choices ...string) (string, error) {
+	return input.GetChoiceInput(prompt, choices...)
+}
+
+func GetDateInput(prompt string) (time.Time, error) {
+	return input.GetDateInput(prompt)
+}
+
+func GetOptionalDateInput(prompt string) (time.Time, error) {
+	return input.GetOptionalDateInput(prompt)
+}
+
+func GetConfirmInput(prompt string) (bool, error) {
+	return input.GetConfirmInput(prompt)
+}
+
+func GetPasswordInput(prompt string) (string, error) {
+	return input.GetPasswordInput(prompt)
+}
+
+func GetNewPasswordInput(prompt, confirmPrompt string) (string, error) {
+	return input.GetNewPasswordInput(prompt, confirmPrompt)
+}
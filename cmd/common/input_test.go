@@ -0,0 +1,153 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAsk(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+		prompts int
+	}{
+		{"valid first line", "ok\n", "ok", nil, 1},
+		{"invalid lines asked again", "\nbad\n  ok  \n", "ok", nil, 3},
+		{"last line without newline", "ok", "ok", nil, 1},
+		{"input closed", "bad\n", "", ErrInputClosed, 2},
+	}
+	for _, test := range tests {
+		var output bytes.Buffer
+		in := NewInput(strings.NewReader(test.input), &output)
+		line, err := in.ask("> ", func(line string) string {
+			if line != "ok" {
+				return "invalid"
+			}
+			return ""
+		})
+		if line != test.want || err != test.wantErr {
+			t.Errorf("%s: ask() = %q, %v, want %q, %v", test.name, line, err, test.want, test.wantErr)
+		}
+		if prompts := strings.Count(output.String(), "> "); prompts != test.prompts {
+			t.Errorf("%s: ask() prompted %d times, want %d", test.name, prompts, test.prompts)
+		}
+		if invalid := strings.Count(output.String(), "invalid\n"); invalid != test.prompts-1 {
+			t.Errorf("%s: ask() printed %d error messages, want %d", test.name, invalid, test.prompts-1)
+		}
+	}
+}
+
+func TestAskWith(t *testing.T) {
+	readErr := errors.New("read failed")
+	tests := []struct {
+		name    string
+		lines   []string
+		err     error
+		want    string
+		wantErr error
+		prompts int
+	}{
+		{"valid first line", []string{"ok"}, nil, "ok", nil, 1},
+		{"invalid lines asked again", []string{"", "bad", "ok"}, nil, "ok", nil, 3},
+		{"input closed", []string{"bad"}, ErrInputClosed, "", ErrInputClosed, 2},
+		{"read error", nil, readErr, "", readErr, 1},
+	}
+	for _, test := range tests {
+		var output bytes.Buffer
+		in := NewInput(strings.NewReader(""), &output)
+		lines := test.lines
+		read := func() (string, error) {
+			if len(lines) == 0 {
+				return "", test.err
+			}
+			line := lines[0]
+			lines = lines[1:]
+			return line, nil
+		}
+		line, err := in.askWith("> ", read, func(line string) string {
+			if line != "ok" {
+				return "invalid"
+			}
+			return ""
+		})
+		if line != test.want || !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: askWith() = %q, %v, want %q, %v", test.name, line, err, test.want, test.wantErr)
+		}
+		if prompts := strings.Count(output.String(), "> "); prompts != test.prompts {
+			t.Errorf("%s: askWith() prompted %d times, want %d", test.name, prompts, test.prompts)
+		}
+		if invalid := strings.Count(output.String(), "invalid\n"); invalid != test.prompts-1 {
+			t.Errorf("%s: askWith() printed %d error messages, want %d", test.name, invalid, test.prompts-1)
+		}
+	}
+}
+
+func TestGetInput(t *testing.T) {
+	defer SetInput(os.Stdin, os.Stdout)
+	defer SetLanguage(CurrentLanguage())
+	SetLanguage(English)
+	tests := []struct {
+		name  string
+		input string
+		get   func() (interface{}, error)
+		want  interface{}
+	}{
+		{"command", "\n", func() (interface{}, error) { return GetCommand("") }, ""},
+		{"string", "\n  Ivan \n", func() (interface{}, error) { return GetStringInput("") }, "Ivan"},
+		{"optional string", "\n", func() (interface{}, error) { return GetOptionalStringInput("") }, ""},
+		{"integer", "one\n1.5\n-7\n", func() (interface{}, error) { return GetIntegerInput("") }, int64(-7)},
+		{"integer or default", "\n", func() (interface{}, error) { return GetIntegerInputOr("", 10) }, int64(10)},
+		{"integer or typed", "x\n3\n", func() (interface{}, error) { return GetIntegerInputOr("", 10) }, int64(3)},
+		{"amount", "0\n1.505\n150.50\n", func() (interface{}, error) { return GetAmountInput("") }, money.Money(15050)},
+		{"amount or default", "\n", func() (interface{}, error) { return GetAmountInputOr("", 100) }, money.Money(100)},
+		{"optional amount", "\n", func() (interface{}, error) { return GetOptionalAmountInput("") }, money.Money(0)},
+		{"phone number", "12345\n+998 (90) 123-45-67\n", func() (interface{}, error) { return GetPhoneNumberInput("") }, int64(998901234567)},
+		{"choice", "csv\nJSON\n", func() (interface{}, error) { return GetChoiceInput("", "json", "html") }, "json"},
+		{"date", "2020-13-01\n25.03.2020\n", func() (interface{}, error) { return GetDateInput("") },
+			time.Date(2020, 3, 25, 0, 0, 0, 0, time.Local)},
+		{"optional date", "\n", func() (interface{}, error) { return GetOptionalDateInput("") }, time.Time{}},
+		{"confirm word", "maybe\nYes\n", func() (interface{}, error) { return GetConfirmInput("") }, true},
+		{"confirm letter", "\nn\n", func() (interface{}, error) { return GetConfirmInput("") }, false},
+	}
+	for _, test := range tests {
+		SetInput(strings.NewReader(test.input), ioutil.Discard)
+		got, err := test.get()
+		if err != nil || got != test.want {
+			t.Errorf("%s: input %q = %v, %v, want %v", test.name, test.input, got, err, test.want)
+		}
+	}
+
+	SetInput(strings.NewReader("abc\n"), ioutil.Discard)
+	if number, err := GetIntegerInput(""); err != ErrInputClosed {
+		t.Errorf("GetIntegerInput() on closed input = %d, %v, want %v", number, err, ErrInputClosed)
+	}
+}
+
+func TestAnswers(t *testing.T) {
+	tests := []struct {
+		line, word string
+		want       bool
+	}{
+		{"yes", "yes", true},
+		{"Y", "yes", true},
+		{"ye", "yes", false},
+		{"Д", "да", true},
+		{"ДА", "да", true},
+		{"", "yes", false},
+		{"", "", false},
+		{"y", "", false},
+	}
+	for _, test := range tests {
+		if got := answers(test.line, test.word); got != test.want {
+			t.Errorf("answers(%q, %q) = %v, want %v", test.line, test.word, got, test.want)
+		}
+	}
+}
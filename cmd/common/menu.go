@@ -0,0 +1,158 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"strconv"
+	"strings"
+)
+
+// ErrMenuExit can be returned by a handler to close the menu it was selected from.
+var ErrMenuExit = errors.New("exit menu")
+
+const (
+	quitKey        = "q"
+	breadcrumbsSep = " › "
+)
+
+type MenuItem struct {
+	Label string
+	// Key selects the item, items without a key are numbered automatically.
+	Key string
+	// Log is written to the log when the item is selected.
+	Log     string
+	Handler func() error
+	Submenu *Menu
+	// Guard hides the item while it returns false.
+	Guard func() bool
+}
+
+type Menu struct {
+	// Title names the menu in breadcrumbs.
+	Title string
+	// Header is printed above the items, usually a boxed title.
+	Header string
+	// Before is called before the items are printed on every iteration.
+	Before func() error
+	Items  []MenuItem
+	// Once makes the menu return after the first selected item is handled.
+	Once   bool
+	Quit   string
+	Prompt string
+}
+
+var menuPath []string
+
+// Run shows the menu until the user goes back, a handler returns ErrMenuExit
+// or an error, or the input is closed.
+func (receiver *Menu) Run() error {
+	menuPath = append(menuPath, receiver.Title)
+	defer func() {
+		menuPath = menuPath[:len(menuPath)-1]
+	}()
+
+	for {
+		if receiver.Before != nil {
+			err := receiver.Before()
+			if errors.Is(err, ErrMenuExit) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		items := receiver.visibleItems()
+		cmd, err := GetCommand(receiver.render(items))
+		if err != nil {
+			logger.Warnf("unable to read command: %v", err)
+			return err
+		}
+		ClearConsole()
+
+		if cmd == quitKey {
+			logger.Info("exit operation selected")
+			return nil
+		}
+		item, ok := findItem(items, cmd)
+		if !ok {
+			logger.Warn("incorrect operation selected")
+			fmt.Println(T("menu.incorrect", cmd))
+			continue
+		}
+
+		if item.Log != "" {
+			logger.Info(item.Log)
+		} else {
+			logger.Infof("%s: %s selected", strings.Join(menuPath, breadcrumbsSep), item.Label)
+		}
+		if item.Submenu != nil {
+			err = item.Submenu.Run()
+		} else if item.Handler != nil {
+			err = item.Handler()
+		}
+		if errors.Is(err, ErrMenuExit) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if receiver.Once {
+			return nil
+		}
+	}
+}
+
+// visibleItems returns the items allowed by their guards with keys assigned.
+func (receiver *Menu) visibleItems() []MenuItem {
+	items := make([]MenuItem, 0, len(receiver.Items))
+	number := 0
+	for _, item := range receiver.Items {
+		if item.Guard != nil && !item.Guard() {
+			continue
+		}
+		if item.Key == "" {
+			number++
+			item.Key = strconv.Itoa(number)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func findItem(items []MenuItem, key string) (MenuItem, bool) {
+	for _, item := range items {
+		if item.Key == key {
+			return item, true
+		}
+	}
+	return MenuItem{}, false
+}
+
+func (receiver *Menu) render(items []MenuItem) string {
+	var builder strings.Builder
+	if len(menuPath) > 1 {
+		builder.WriteString(strings.Join(menuPath, breadcrumbsSep))
+		builder.WriteString("\n")
+	}
+	if receiver.Header != "" {
+		builder.WriteString(receiver.Header)
+		builder.WriteString("\n")
+	}
+	for _, item := range items {
+		builder.WriteString(fmt.Sprintf("%-4s%s\n", item.Key+".", item.Label))
+	}
+
+	quit := receiver.Quit
+	if quit == "" {
+		quit = T("menu.back")
+	}
+	builder.WriteString(fmt.Sprintf("%-4s%s\n\n", quitKey+".", quit))
+
+	prompt := receiver.Prompt
+	if prompt == "" {
+		prompt = T("menu.prompt")
+	}
+	builder.WriteString(prompt)
+	return builder.String()
+}
@@ -0,0 +1,163 @@
+package common
+
+func init() {
+	for lang, messages := range messages {
+		AddMessages(lang, messages)
+	}
+}
+
+var messages = map[Language]map[string]string{
+	Russian: {
+		"welcome":              "Добро пожаловать!",
+		"input.empty":          "Значение не может быть пустым.",
+		"input.not_integer":    "Введите целое число.",
+		"input.invalid_phone":  "Номер телефона должен содержать от 9 до 12 цифр.",
+		"input.invalid_amount": "Сумма должна быть больше нуля, не более двух знаков после точки, например 150.50",
+		"input.invalid_value":  "Допустимые значения: %s.",
+		"input.invalid_date":   "Введите дату в формате ГГГГ-ММ-ДД, например 2020-03-25.",
+		"input.yes":            "да",
+		"input.no":             "нет",
+		"password.prompt":      "Введите пароль: ",
+		"password.mismatch":    "Пароли не совпадают.",
+		"menu.main":            "Главное меню",
+		"menu.back":            "Назад",
+		"menu.exit":            "Выход",
+		"menu.prompt":          "Выберите операцию: ",
+		"menu.incorrect":       "Вы выбрали неверную команду: %s",
+		"list.empty":           "Пусто",
+		"pager.next":           "след >",
+		"pager.prev":           "< пред",
+		"pager.goto":           "Перейти к странице",
+		"pager.size":           "Размер страницы",
+		"pager.sort":           "Сортировка",
+		"pager.page":           "Страница %d из %d",
+		"pager.sorted":         "Сортировка: %s %s",
+		"pager.asc":            "↑",
+		"pager.desc":           "↓",
+		"pager.first":          "Это первая страница.",
+		"pager.last":           "Это последняя страница.",
+		"pager.prompt.page":    "Введите номер страницы (1–%d): ",
+		"pager.prompt.size":    "Введите число строк на странице: ",
+		"pager.prompt.sort":    "Выберите столбец: ",
+		"pager.invalid_page":   "Нет такой страницы.",
+		"pager.invalid_size":   "Число строк должно быть больше нуля.",
+		"statement.title":      "Выписка по счёту %d",
+		"statement.period":     "Период: %s — %s",
+		"statement.opening":    "Входящий остаток: %s",
+		"statement.closing":    "Исходящий остаток: %s",
+		"statement.total_in":   "Поступления: %s",
+		"statement.total_out":  "Списания: %s",
+		"statement.date":       "Дата",
+		"statement.type":       "Операция",
+		"statement.party":      "Контрагент",
+		"statement.amount":     "Сумма",
+		"statement.balance":    "Остаток",
+		"operation.transfer":   "Перевод",
+		"operation.incoming":   "Поступление",
+		"operation.service":    "Оплата услуги",
+		"operation.deposit":    "Пополнение",
+		"operation.exchange":   "%s (%s по курсу 1 %s = %s %s)",
+	},
+	English: {
+		"welcome":              "Welcome!",
+		"input.empty":          "The value can't be empty.",
+		"input.not_integer":    "Enter a whole number.",
+		"input.invalid_phone":  "A phone number must have 9 to 12 digits.",
+		"input.invalid_amount": "The amount must be greater than zero with at most two decimals, e.g. 150.50",
+		"input.invalid_value":  "Allowed values: %s.",
+		"input.invalid_date":   "Enter a date as YYYY-MM-DD, e.g. 2020-03-25.",
+		"input.yes":            "yes",
+		"input.no":             "no",
+		"password.prompt":      "Enter password: ",
+		"password.mismatch":    "Passwords don't match.",
+		"menu.main":            "Main menu",
+		"menu.back":            "Back",
+		"menu.exit":            "Exit",
+		"menu.prompt":          "Choose an operation: ",
+		"menu.incorrect":       "You chose an invalid command: %s",
+		"list.empty":           "Empty",
+		"pager.next":           "next >",
+		"pager.prev":           "< prev",
+		"pager.goto":           "Go to page",
+		"pager.size":           "Page size",
+		"pager.sort":           "Sort",
+		"pager.page":           "Page %d of %d",
+		"pager.sorted":         "Sorted by %s %s",
+		"pager.asc":            "↑",
+		"pager.desc":           "↓",
+		"pager.first":          "This is the first page.",
+		"pager.last":           "This is the last page.",
+		"pager.prompt.page":    "Enter the page number (1–%d): ",
+		"pager.prompt.size":    "Enter the number of rows on a page: ",
+		"pager.prompt.sort":    "Choose a column: ",
+		"pager.invalid_page":   "There is no such page.",
+		"pager.invalid_size":   "The number of rows must be greater than zero.",
+		"statement.title":      "Statement of account %d",
+		"statement.period":     "Period: %s — %s",
+		"statement.opening":    "Opening balance: %s",
+		"statement.closing":    "Closing balance: %s",
+		"statement.total_in":   "Total in: %s",
+		"statement.total_out":  "Total out: %s",
+		"statement.date":       "Date",
+		"statement.type":       "Operation",
+		"statement.party":      "Counterparty",
+		"statement.amount":     "Amount",
+		"statement.balance":    "Balance",
+		"operation.transfer":   "Transfer",
+		"operation.incoming":   "Incoming transfer",
+		"operation.service":    "Service payment",
+		"operation.deposit":    "Deposit",
+		"operation.exchange":   "%s (%s at 1 %s = %s %s)",
+	},
+	Uzbek: {
+		"welcome":              "Xush kelibsiz!",
+		"input.empty":          "Qiymat bo‘sh bo‘lishi mumkin emas.",
+		"input.not_integer":    "Butun son kiriting.",
+		"input.invalid_phone":  "Telefon raqami 9 tadan 12 tagacha raqamdan iborat bo‘lishi kerak.",
+		"input.invalid_amount": "Summa noldan katta, nuqtadan keyin ko‘pi bilan ikki raqam bo‘lishi kerak, masalan 150.50",
+		"input.invalid_value":  "Ruxsat etilgan qiymatlar: %s.",
+		"input.invalid_date":   "Sanani YYYY-OO-KK ko‘rinishida kiriting, masalan 2020-03-25.",
+		"input.yes":            "ha",
+		"input.no":             "yo‘q",
+		"password.prompt":      "Parolni kiriting: ",
+		"password.mismatch":    "Parollar mos kelmadi.",
+		"menu.main":            "Bosh menyu",
+		"menu.back":            "Orqaga",
+		"menu.exit":            "Chiqish",
+		"menu.prompt":          "Amalni tanlang: ",
+		"menu.incorrect":       "Noto‘g‘ri buyruq tanlandi: %s",
+		"list.empty":           "Bo‘sh",
+		"pager.next":           "keyingi >",
+		"pager.prev":           "< oldingi",
+		"pager.goto":           "Sahifaga o‘tish",
+		"pager.size":           "Sahifa hajmi",
+		"pager.sort":           "Saralash",
+		"pager.page":           "%[2]d sahifadan %[1]d-sahifa",
+		"pager.sorted":         "Saralash: %s %s",
+		"pager.asc":            "↑",
+		"pager.desc":           "↓",
+		"pager.first":          "Bu birinchi sahifa.",
+		"pager.last":           "Bu oxirgi sahifa.",
+		"pager.prompt.page":    "Sahifa raqamini kiriting (1–%d): ",
+		"pager.prompt.size":    "Sahifadagi qatorlar sonini kiriting: ",
+		"pager.prompt.sort":    "Ustunni tanlang: ",
+		"pager.invalid_page":   "Bunday sahifa yo‘q.",
+		"pager.invalid_size":   "Qatorlar soni noldan katta bo‘lishi kerak.",
+		"statement.title":      "%d hisobi bo‘yicha ko‘chirma",
+		"statement.period":     "Davr: %s — %s",
+		"statement.opening":    "Boshlang‘ich qoldiq: %s",
+		"statement.closing":    "Yakuniy qoldiq: %s",
+		"statement.total_in":   "Kirim: %s",
+		"statement.total_out":  "Chiqim: %s",
+		"statement.date":       "Sana",
+		"statement.type":       "Amal",
+		"statement.party":      "Kontragent",
+		"statement.amount":     "Summa",
+		"statement.balance":    "Qoldiq",
+		"operation.transfer":   "O‘tkazma",
+		"operation.incoming":   "Kirim o‘tkazmasi",
+		"operation.service":    "Xizmat to‘lovi",
+		"operation.deposit":    "To‘ldirish",
+		"operation.exchange":   "%s (%s, kurs 1 %s = %s %s)",
+	},
+}
@@ -0,0 +1,244 @@
+package common
+
+import (
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"strconv"
+)
+
+// Page is a part of a list returned by the fetch function of a Pager.
+type Page struct {
+	Rows []string
+	// Total is the number of rows in the whole list.
+	Total int64
+	// Footer is printed under the rows, for example the totals of the list.
+	Footer string
+}
+
+// Column is a column a Pager can sort its list by.
+type Column struct {
+	Key   string
+	Label string
+}
+
+// Columns labels keys with the messages named prefix + key.
+func Columns(prefix string, keys []string) []Column {
+	columns := make([]Column, len(keys))
+	for idx, key := range keys {
+		columns[idx] = Column{Key: key, Label: T(prefix + key)}
+	}
+	return columns
+}
+
+// Sort is the order of the list asked from the fetch function, Column is empty when the list isn't sortable.
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// Pager shows a list page by page in a menu.
+type Pager struct {
+	Title string
+	// Size is the number of rows on a page, the user can change it.
+	Size int64
+	// Columns are the columns the list can be sorted by, the first one is the default.
+	Columns []Column
+	// Fetch returns limit rows of the list in the sort order, skipping offset of them.
+	Fetch func(limit, offset int64, sort Sort) (Page, error)
+	// Before is called before a page is printed.
+	Before func()
+	// Items are added to the menu after the paging ones.
+	Items []MenuItem
+	// Error is printed when Fetch fails.
+	Error string
+
+	page  int64
+	pages int64
+	sort  Sort
+}
+
+// Reset moves the pager to the first page, for example after the list was filtered.
+func (receiver *Pager) Reset() {
+	receiver.page = 1
+}
+
+// Run shows the pages until the user goes back. A list without rows is left at once
+// unless the pager has Items, they may change the list.
+func (receiver *Pager) Run() error {
+	receiver.page, receiver.pages = 1, 1
+	if len(receiver.Columns) > 0 {
+		receiver.sort = Sort{Column: receiver.Columns[0].Key}
+	}
+	items := []MenuItem{
+		{Label: T("pager.next"), Log: "next operation selected", Handler: func() error {
+			receiver.turn(receiver.page + 1)
+			return nil
+		}},
+		{Label: T("pager.prev"), Log: "prev operation selected", Handler: func() error {
+			receiver.turn(receiver.page - 1)
+			return nil
+		}},
+		{Label: T("pager.goto"), Log: "go to page operation selected", Handler: func() error {
+			receiver.askPage()
+			return nil
+		}},
+		{Label: T("pager.size"), Log: "page size operation selected", Handler: func() error {
+			receiver.askSize()
+			return nil
+		}},
+	}
+	if len(receiver.Columns) > 0 {
+		items = append(items, MenuItem{Label: T("pager.sort"), Log: "sort operation selected", Handler: func() error {
+			receiver.askSort()
+			return nil
+		}})
+	}
+	menu := &Menu{
+		Title:  receiver.Title,
+		Before: receiver.show,
+		Items:  append(items, receiver.Items...),
+	}
+	return menu.Run()
+}
+
+func (receiver *Pager) show() error {
+	if receiver.Before != nil {
+		receiver.Before()
+	}
+	page, err := receiver.fetch()
+	if err != nil {
+		logger.Errorf("unable to get page %d of %s: %v", receiver.page, receiver.Title, err)
+		fmt.Println(receiver.Error)
+		return ErrMenuExit
+	}
+	logger.Debugf("page %d of %d received", receiver.page, receiver.pages)
+	if page.Total == 0 {
+		logger.Info("list is empty")
+		fmt.Println(T("list.empty"))
+		if len(receiver.Items) == 0 {
+			return ErrMenuExit
+		}
+	}
+
+	offset := receiver.offset()
+	for idx, row := range page.Rows {
+		fmt.Println(offset+int64(idx)+1, ") ", row)
+	}
+	if page.Footer != "" {
+		fmt.Println(page.Footer)
+	}
+	fmt.Println(T("pager.page", receiver.page, receiver.pages))
+	if receiver.sort.Column != "" {
+		fmt.Println(T("pager.sorted", receiver.columnLabel(receiver.sort.Column), direction(receiver.sort.Desc)))
+	}
+	fmt.Println()
+	return nil
+}
+
+// fetch gets the current page, moving to the last one if the list got shorter.
+func (receiver *Pager) fetch() (page Page, err error) {
+	page, err = receiver.Fetch(receiver.Size, receiver.offset(), receiver.sort)
+	if err != nil {
+		return page, err
+	}
+	receiver.pages = (page.Total + receiver.Size - 1) / receiver.Size
+	if receiver.pages == 0 {
+		receiver.pages = 1
+	}
+	if receiver.page <= receiver.pages {
+		return page, nil
+	}
+	receiver.page = receiver.pages
+	return receiver.Fetch(receiver.Size, receiver.offset(), receiver.sort)
+}
+
+func (receiver *Pager) offset() int64 {
+	return (receiver.page - 1) * receiver.Size
+}
+
+func (receiver *Pager) turn(page int64) {
+	switch {
+	case page < 1:
+		fmt.Println(T("pager.first"))
+	case page > receiver.pages:
+		fmt.Println(T("pager.last"))
+	default:
+		receiver.page = page
+	}
+}
+
+func (receiver *Pager) askPage() {
+	logger.Debug("asking to enter page number")
+	page, err := GetIntegerInput(T("pager.prompt.page", receiver.pages))
+	if err != nil {
+		logger.Warnf("unable to read page number: %v", err)
+		return
+	}
+	ClearConsole()
+	if page < 1 || page > receiver.pages {
+		logger.Warnf("invalid page number: %d", page)
+		fmt.Println(T("pager.invalid_page"))
+		return
+	}
+	receiver.page = page
+}
+
+// askSize changes the page size keeping the first row of the current page on the screen.
+func (receiver *Pager) askSize() {
+	logger.Debug("asking to enter page size")
+	size, err := GetIntegerInputOr(T("pager.prompt.size"), receiver.Size)
+	if err != nil {
+		logger.Warnf("unable to read page size: %v", err)
+		return
+	}
+	ClearConsole()
+	if size < 1 {
+		logger.Warnf("invalid page size: %d", size)
+		fmt.Println(T("pager.invalid_size"))
+		return
+	}
+	receiver.page = receiver.offset()/size + 1
+	receiver.Size = size
+	logger.Debugf("page size set to %d", size)
+}
+
+// askSort sorts the list by the chosen column, choosing the current column again reverses the order.
+func (receiver *Pager) askSort() {
+	choices := make([]string, len(receiver.Columns))
+	for idx, column := range receiver.Columns {
+		choices[idx] = strconv.Itoa(idx + 1)
+		fmt.Printf("%-4s%s\n", choices[idx]+".", column.Label)
+	}
+	logger.Debug("asking to choose column")
+	choice, err := GetChoiceInput(T("pager.prompt.sort"), choices...)
+	if err != nil {
+		logger.Warnf("unable to read column: %v", err)
+		return
+	}
+	ClearConsole()
+	index, _ := strconv.Atoi(choice)
+	column := receiver.Columns[index-1].Key
+	if column == receiver.sort.Column {
+		receiver.sort.Desc = !receiver.sort.Desc
+	} else {
+		receiver.sort = Sort{Column: column}
+	}
+	receiver.page = 1
+	logger.Debugf("sorted by %s, descending %t", column, receiver.sort.Desc)
+}
+
+func (receiver *Pager) columnLabel(key string) string {
+	for _, column := range receiver.Columns {
+		if column.Key == key {
+			return column.Label
+		}
+	}
+	return key
+}
+
+func direction(desc bool) string {
+	if desc {
+		return T("pager.desc")
+	}
+	return T("pager.asc")
+}
@@ -0,0 +1,141 @@
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// numbersPager pages through the numbers from 1 to total.
+func numbersPager(total, size int64) *Pager {
+	return &Pager{
+		Size: size,
+		Fetch: func(limit, offset int64, sort Sort) (Page, error) {
+			var rows []string
+			for number := offset + 1; number <= offset+limit && number <= total; number++ {
+				rows = append(rows, strconv.FormatInt(number, 10))
+			}
+			return Page{Rows: rows, Total: total}, nil
+		},
+		page:  1,
+		pages: 1,
+	}
+}
+
+func TestPagerFetch(t *testing.T) {
+	tests := []struct {
+		name        string
+		total, size int64
+		page        int64
+		wantPage    int64
+		wantPages   int64
+		wantRows    string
+	}{
+		{"empty list", 0, 10, 1, 1, 1, ""},
+		{"size larger than list", 3, 10, 1, 1, 1, "1 2 3"},
+		{"full pages", 20, 10, 2, 2, 2, "11 12 13 14 15 16 17 18 19 20"},
+		{"last page", 25, 10, 3, 3, 3, "21 22 23 24 25"},
+		{"middle page", 25, 5, 2, 2, 5, "6 7 8 9 10"},
+		{"list got shorter", 12, 5, 4, 3, 3, "11 12"},
+		{"list got empty", 0, 5, 4, 1, 1, ""},
+	}
+	for _, test := range tests {
+		pager := numbersPager(test.total, test.size)
+		pager.page = test.page
+		page, err := pager.fetch()
+		if err != nil {
+			t.Fatalf("%s: fetch() error = %v", test.name, err)
+		}
+		rows := strings.Join(page.Rows, " ")
+		if pager.page != test.wantPage || pager.pages != test.wantPages || rows != test.wantRows {
+			t.Errorf("%s: fetch() = page %d of %d with %q, want page %d of %d with %q",
+				test.name, pager.page, pager.pages, rows, test.wantPage, test.wantPages, test.wantRows)
+		}
+		if want := (test.wantPage - 1) * test.size; pager.offset() != want {
+			t.Errorf("%s: offset() = %d, want %d", test.name, pager.offset(), want)
+		}
+	}
+}
+
+func TestPagerTurn(t *testing.T) {
+	tests := []struct {
+		from, to int64
+		want     int64
+	}{
+		{1, 2, 2},
+		{3, 2, 2},
+		{1, 0, 1},
+		{3, 4, 3},
+		{2, 3, 3},
+	}
+	for _, test := range tests {
+		pager := numbersPager(25, 10)
+		pager.pages = 3
+		pager.page = test.from
+		pager.turn(test.to)
+		if pager.page != test.want {
+			t.Errorf("turn(%d) from page %d moved to page %d, want %d", test.to, test.from, pager.page, test.want)
+		}
+	}
+}
+
+func TestPagerAskPage(t *testing.T) {
+	defer SetInput(os.Stdin, os.Stdout)
+	tests := []struct {
+		input string
+		want  int64
+	}{
+		{"2\n", 2},
+		{"3\n", 3},
+		{"4\n", 1},
+		{"0\n", 1},
+	}
+	for _, test := range tests {
+		SetInput(strings.NewReader(test.input), ioutil.Discard)
+		pager := numbersPager(25, 10)
+		pager.pages = 3
+		pager.askPage()
+		if pager.page != test.want {
+			t.Errorf("askPage() with %q moved to page %d, want %d", test.input, pager.page, test.want)
+		}
+	}
+}
+
+func TestPagerAskSize(t *testing.T) {
+	defer SetInput(os.Stdin, os.Stdout)
+	tests := []struct {
+		name      string
+		total     int64
+		page      int64
+		input     string
+		wantSize  int64
+		wantPage  int64
+		wantPages int64
+	}{
+		{"smaller pages keep the first row", 25, 3, "7\n", 7, 3, 4},
+		{"larger pages keep the first row", 25, 3, "15\n", 15, 2, 2},
+		{"size larger than list", 25, 3, "100\n", 100, 1, 1},
+		{"empty input keeps the size", 25, 3, "\n", 10, 3, 3},
+		{"zero size is rejected", 25, 3, "0\n", 10, 3, 3},
+		{"negative size is rejected", 25, 2, "-5\n", 10, 2, 3},
+		{"empty list", 0, 1, "5\n", 5, 1, 1},
+	}
+	for _, test := range tests {
+		SetInput(strings.NewReader(test.input), ioutil.Discard)
+		pager := numbersPager(test.total, 10)
+		pager.page = test.page
+		if _, err := pager.fetch(); err != nil {
+			t.Fatal(err)
+		}
+		pager.askSize()
+		if _, err := pager.fetch(); err != nil {
+			t.Fatal(err)
+		}
+		if pager.Size != test.wantSize || pager.page != test.wantPage || pager.pages != test.wantPages {
+			t.Errorf("%s: askSize() = size %d, page %d of %d, want size %d, page %d of %d", test.name,
+				pager.Size, pager.page, pager.pages, test.wantSize, test.wantPage, test.wantPages)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+const (
+	keyInterrupt = 3
+	keyEndOfText = 4
+	keyBackspace = 8
+	keyDelete    = 127
+)
+
+func (receiver *Input) GetPasswordInput(prompt string) (string, error) {
+	return receiver.askWith(prompt, receiver.readPassword, func(line string) string {
+		if line == "" {
+			return T("input.empty")
+		}
+		return ""
+	})
+}
+
+// GetNewPasswordInput asks for a password twice and repeats until both entries match.
+func (receiver *Input) GetNewPasswordInput(prompt, confirmPrompt string) (string, error) {
+	for {
+		password, err := receiver.GetPasswordInput(prompt)
+		if err != nil {
+			return "", err
+		}
+		confirmation, err := receiver.askWith(confirmPrompt, receiver.readPassword, func(line string) string {
+			return ""
+		})
+		if err != nil {
+			return "", err
+		}
+		if password == confirmation {
+			return password, nil
+		}
+		_, _ = fmt.Fprintln(receiver.writer, T("password.mismatch"))
+	}
+}
+
+// readPassword reads a line without echoing it. When the input is not a terminal
+// the line is read as is, there is nobody to hide it from.
+func (receiver *Input) readPassword() (string, error) {
+	if receiver.file == nil {
+		return receiver.ReadLine()
+	}
+	restore, err := makeRaw(receiver.file.Fd())
+	if err != nil {
+		return receiver.ReadLine()
+	}
+	defer restore()
+	defer func() {
+		_, _ = fmt.Fprintln(receiver.writer)
+	}()
+
+	var password []byte
+	for {
+		char, err := receiver.reader.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", ErrInputClosed
+			}
+			return "", err
+		}
+		switch char {
+		case '\r', '\n':
+			return string(password), nil
+		case keyInterrupt:
+			return "", ErrInputClosed
+		case keyEndOfText:
+			if len(password) == 0 {
+				return "", ErrInputClosed
+			}
+		case keyBackspace, keyDelete:
+			if len(password) > 0 {
+				_, size := utf8.DecodeLastRune(password)
+				password = password[:len(password)-size]
+			}
+		default:
+			password = append(password, char)
+		}
+	}
+}
+
+// GetSecretInput returns the value of the environment variable env or,
+// when it is not set, reads the secret from the input without echo.
+func GetSecretInput(env, prompt string) (string, error) {
+	if secret, ok := os.LookupEnv(env); ok {
+		return secret, nil
+	}
+	return input.GetPasswordInput(prompt)
+}
@@ -0,0 +1,38 @@
+//go:build linux
+// +build linux
+
+package common
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// makeRaw switches the terminal behind fd to raw mode without echo and returns a function restoring it.
+func makeRaw(fd uintptr) (restore func(), err error) {
+	var old syscall.Termios
+	if err = ioctl(fd, syscall.TCGETS, &old); err != nil {
+		return nil, err
+	}
+
+	raw := old
+	raw.Iflag &^= syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err = ioctl(fd, syscall.TCSETS, &raw); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_ = ioctl(fd, syscall.TCSETS, &old)
+	}, nil
+}
+
+func ioctl(fd, request uintptr, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
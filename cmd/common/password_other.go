@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package common
+
+import "errors"
+
+var errRawModeUnsupported = errors.New("raw terminal mode is not supported on this platform")
+
+func makeRaw(fd uintptr) (restore func(), err error) {
+	return nil, errRawModeUnsupported
+}
@@ -0,0 +1,42 @@
+package common
+
+import (
+	"rsc.io/qr"
+	"strings"
+)
+
+// quietZone is the light margin around a QR code the scanners need, in modules.
+const quietZone = 2
+
+// QRCode draws text as a QR code in text, two rows of modules per line. The light modules are
+// drawn with blocks, so the code scans on the usual dark terminal background.
+func QRCode(text string) (string, error) {
+	code, err := qr.Encode(text, qr.L)
+	if err != nil {
+		return "", err
+	}
+	light := func(x, y int) bool {
+		if x < 0 || y < 0 || x >= code.Size || y >= code.Size {
+			return true
+		}
+		return !code.Black(x, y)
+	}
+	var builder strings.Builder
+	for y := -quietZone; y < code.Size+quietZone; y += 2 {
+		for x := -quietZone; x < code.Size+quietZone; x++ {
+			top, bottom := light(x, y), light(x, y+1)
+			switch {
+			case top && bottom:
+				builder.WriteString("█")
+			case top:
+				builder.WriteString("▀")
+			case bottom:
+				builder.WriteString("▄")
+			default:
+				builder.WriteString(" ")
+			}
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String(), nil
+}
@@ -0,0 +1,67 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSessionExpired is returned by every input of a session after it timed out, until the session ends.
+var ErrSessionExpired = errors.New("session expired")
+
+// session limits the time between inputs and the time since the start, zero durations don't limit it.
+type session struct {
+	idle    time.Duration
+	max     time.Duration
+	started time.Time
+	last    time.Time
+	// valid tells if the session may go on, for example if it wasn't revoked.
+	valid func() error
+	err   error
+}
+
+// StartSession makes the inputs fail with ErrSessionExpired once no line was entered for idle
+// or max passed since the start, and with the error of valid once it returns one.
+// The line entered after that is rejected.
+func (receiver *Input) StartSession(idle, max time.Duration, valid func() error) {
+	now := time.Now()
+	receiver.session = &session{idle: idle, max: max, started: now, last: now, valid: valid}
+}
+
+func (receiver *Input) EndSession() {
+	receiver.session = nil
+}
+
+// check is called for every entered line, the session stays expired once it has expired.
+func (receiver *session) check(now time.Time) error {
+	if receiver == nil {
+		return nil
+	}
+	switch {
+	case receiver.err != nil:
+	case receiver.idle > 0 && now.Sub(receiver.last) > receiver.idle:
+		receiver.err = fmt.Errorf("%w: no input for %s", ErrSessionExpired, now.Sub(receiver.last).Round(time.Second))
+	case receiver.max > 0 && now.Sub(receiver.started) > receiver.max:
+		receiver.err = fmt.Errorf("%w: longer than %s", ErrSessionExpired, receiver.max)
+	}
+	if receiver.err == nil && receiver.valid != nil {
+		receiver.err = receiver.valid()
+	}
+	receiver.last = now
+	return receiver.err
+}
+
+func (receiver *session) expired() error {
+	if receiver == nil {
+		return nil
+	}
+	return receiver.err
+}
+
+func StartSession(idle, max time.Duration, valid func() error) {
+	input.StartSession(idle, max, valid)
+}
+
+func EndSession() {
+	input.EndSession()
+}
@@ -0,0 +1,199 @@
+package common
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+var StatementFormats = []string{"csv", "json", "txt", "html"}
+
+var ErrUnknownFormat = errors.New("unknown format")
+
+const statementDateFormat = "2006-01-02"
+
+// RenderStatement encodes statement in one of StatementFormats. csv and json are meant for
+// machines and keep plain amounts, txt and html are documents in the current language.
+func RenderStatement(statement bank.Statement, format string) ([]byte, error) {
+	switch format {
+	case "csv":
+		return statementCSV(statement)
+	case "json":
+		return json.MarshalIndent(statement, "", "  ")
+	case "txt":
+		return statementText(statement)
+	case "html":
+		return statementHTML(statement)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// StatementFileName names the file of a statement by its account and period.
+func StatementFileName(statement bank.Statement, format string) string {
+	return fmt.Sprintf("statement-%d-%s-%s.%s", statement.AccountId,
+		statement.From.Format(statementDateFormat), statement.To.Format(statementDateFormat), format)
+}
+
+func OperationType(operation bank.Operation) string {
+	return T("operation." + operation.Kind())
+}
+
+// OperationAmount formats the amount of operation in the currency of its account, with the
+// other side of a transfer between currencies.
+func OperationAmount(operation bank.Operation) string {
+	amount := operation.Amount.Format(operation.Currency)
+	exchange := operation.Exchange
+	if exchange.Currency == "" {
+		return amount
+	}
+	from, to := operation.Currency, exchange.Currency
+	if operation.Incoming() {
+		from, to = to, from
+	}
+	return T("operation.exchange", amount, exchange.Amount.Format(exchange.Currency), from, exchange.Rate, to)
+}
+
+// OperationParty is the counterparty of operation followed by the details of a service payment.
+func OperationParty(operation bank.Operation) string {
+	if len(operation.Details) == 0 {
+		return operation.Counterparty
+	}
+	return operation.Counterparty + " (" + operationDetails(operation) + ")"
+}
+
+func operationDetails(operation bank.Operation) string {
+	details := make([]string, len(operation.Details))
+	for idx, detail := range operation.Details {
+		details[idx] = detail.Name + ": " + detail.Value
+	}
+	return strings.Join(details, ", ")
+}
+
+func statementCSV(statement bank.Statement) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	records := [][]string{
+		{"account", strconv.FormatInt(statement.AccountId, 10)},
+		{"currency", string(statement.Currency)},
+		{"from", statement.From.Format(statementDateFormat)},
+		{"to", statement.To.Format(statementDateFormat)},
+		{"opening", statement.Opening.Decimal()},
+		{"date", "type", "counterparty", "amount", "balance", "details"},
+	}
+	for _, operation := range statement.Operations {
+		records = append(records, []string{
+			operation.Date.Format(bank.OperationDateFormat),
+			operation.Type,
+			operation.Counterparty,
+			operation.Amount.Decimal(),
+			operation.Balance.Decimal(),
+			operationDetails(operation),
+		})
+	}
+	records = append(records,
+		[]string{"total_in", statement.TotalIn.Decimal()},
+		[]string{"total_out", statement.TotalOut.Decimal()},
+		[]string{"closing", statement.Closing.Decimal()},
+	)
+	err := writer.WriteAll(records)
+	return buffer.Bytes(), err
+}
+
+func statementText(statement bank.Statement) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(Box(T("statement.title", statement.AccountId)) + "\n")
+	buffer.WriteString(T("statement.period", statement.From.Format(statementDateFormat), statement.To.Format(statementDateFormat)) + "\n")
+	buffer.WriteString(T("statement.opening", statement.Opening.Format(statement.Currency)) + "\n\n")
+
+	writer := tabwriter.NewWriter(&buffer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+		T("statement.date"), T("statement.type"), T("statement.party"), T("statement.amount"), T("statement.balance"))
+	for _, operation := range statement.Operations {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", operation.Date.Format(bank.OperationDateFormat),
+			OperationType(operation), OperationParty(operation), OperationAmount(operation), operation.Balance.Format(operation.Currency))
+	}
+	if len(statement.Operations) == 0 {
+		_, _ = fmt.Fprintln(writer, T("list.empty"))
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+
+	buffer.WriteString("\n" + T("statement.total_in", statement.TotalIn.Format(statement.Currency)) + "\n")
+	buffer.WriteString(T("statement.total_out", statement.TotalOut.Format(statement.Currency)) + "\n")
+	buffer.WriteString(T("statement.closing", statement.Closing.Format(statement.Currency)) + "\n")
+	return buffer.Bytes(), nil
+}
+
+var statementTemplate = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"T":      T,
+	"type":   OperationType,
+	"amount": OperationAmount,
+	"party":  OperationParty,
+	"date": func(operation bank.Operation) string {
+		return operation.Date.Format(bank.OperationDateFormat)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="{{.Language}}">
+<head>
+<meta charset="utf-8">
+<title>{{T "statement.title" .Statement.AccountId}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #999; padding: 4px 8px; }
+td.amount { text-align: right; white-space: nowrap; }
+</style>
+</head>
+<body>
+<h1>{{T "statement.title" .Statement.AccountId}}</h1>
+<p>{{T "statement.period" .From .To}}</p>
+<p>{{T "statement.opening" (.Statement.Opening.Format .Statement.Currency)}}</p>
+<table>
+<tr><th>{{T "statement.date"}}</th><th>{{T "statement.type"}}</th><th>{{T "statement.party"}}</th><th>{{T "statement.amount"}}</th><th>{{T "statement.balance"}}</th></tr>
+{{range .Statement.Operations}}<tr><td>{{date .}}</td><td>{{type .}}</td><td>{{party .}}</td><td class="amount">{{amount .}}</td><td class="amount">{{.Balance.Format .Currency}}</td></tr>
+{{else}}<tr><td colspan="5">{{T "list.empty"}}</td></tr>
+{{end}}</table>
+<p>{{T "statement.total_in" (.Statement.TotalIn.Format .Statement.Currency)}}<br>
+{{T "statement.total_out" (.Statement.TotalOut.Format .Statement.Currency)}}<br>
+{{T "statement.closing" (.Statement.Closing.Format .Statement.Currency)}}</p>
+</body>
+</html>
+`))
+
+func statementHTML(statement bank.Statement) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := statementTemplate.Execute(&buffer, struct {
+		Language  Language
+		Statement bank.Statement
+		From, To  string
+	}{CurrentLanguage(), statement, statement.From.Format(statementDateFormat), statement.To.Format(statementDateFormat)})
+	return buffer.Bytes(), err
+}
+
+// exportFileMode keeps the statements and exports readable by their owner only.
+const exportFileMode = 0600
+
+// WriteExport writes data to fileName, creating its directory if needed.
+func WriteExport(fileName string, data []byte) error {
+	err := os.MkdirAll(filepath.Dir(fileName), 0755)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(fileName, data, exportFileMode)
+	if err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of a file written over
+	return os.Chmod(fileName, exportFileMode)
+}
@@ -0,0 +1,35 @@
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "exports", "statement.csv")
+	for _, data := range []string{"first", "second"} {
+		if data == "second" {
+			if err = os.Chmod(fileName, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err = WriteExport(fileName, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadFile(fileName)
+		if err != nil || string(content) != data {
+			t.Errorf("WriteExport(%q) wrote %q, %v", data, content, err)
+		}
+		info, err := os.Stat(fileName)
+		if err != nil || info.Mode().Perm() != exportFileMode {
+			t.Errorf("WriteExport(%q) mode = %v, %v, want %v", data, info.Mode().Perm(), err, os.FileMode(exportFileMode))
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+const ansiClearScreen = "\033[H\033[2J\033[3J"
+
+type Terminal struct {
+	OS    string
+	IsTTY bool
+	ANSI  bool
+	out   *os.File
+}
+
+var terminal = DetectTerminal(os.Stdout)
+
+// DetectTerminal reports whether out is an interactive terminal and how it can be controlled.
+func DetectTerminal(out *os.File) *Terminal {
+	term := &Terminal{OS: runtime.GOOS, out: out}
+	info, err := out.Stat()
+	if err == nil {
+		term.IsTTY = info.Mode()&os.ModeCharDevice != 0
+	}
+	term.ANSI = term.IsTTY && term.OS != "windows" && os.Getenv("TERM") != "dumb"
+	return term
+}
+
+// Clear clears the screen. Nothing is written when output is piped or redirected.
+func (receiver *Terminal) Clear() {
+	if !receiver.IsTTY {
+		return
+	}
+	if receiver.OS == "windows" {
+		cmd := exec.Command("cmd", "/c", "cls")
+		cmd.Stdout = receiver.out
+		_ = cmd.Run()
+		return
+	}
+	if receiver.ANSI {
+		_, _ = fmt.Fprint(receiver.out, ansiClearScreen)
+	}
+}
+
+func CurrentTerminal() *Terminal {
+	return terminal
+}
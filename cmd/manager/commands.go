@@ -0,0 +1,580 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/auth"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	exitOk = iota
+	exitFailure
+	exitUsage
+	exitAlreadyExists
+	exitNotFound
+)
+
+const passwordEnv = "IBANK_PASSWORD"
+
+type command func(args []string, db *sql.DB) int
+
+var commands = map[string]command{
+	"client": subcommands(map[string]command{
+		"add":    addClientCommand,
+		"list":   listClientsCommand,
+		"search": searchClientCommand,
+	}),
+	"account": subcommands(map[string]command{
+		"add": addAccountCommand,
+	}),
+	"service": subcommands(map[string]command{
+		"add": addServiceCommand,
+	}),
+	"atm": subcommands(map[string]command{
+		"add": addAtmCommand,
+	}),
+	"login": subcommands(map[string]command{
+		"failures": loginFailuresCommand,
+		"unlock":   unlockCommand,
+	}),
+	"2fa": subcommands(map[string]command{
+		"reset": resetTwoFactorCommand,
+	}),
+	"export":        exportCommand,
+	"import":        importCommand,
+	"status":        statusCommand,
+	"run-scheduled": runScheduledCommand,
+	"statement":     statementCommand,
+	"rates":         ratesCommand,
+}
+
+// runCommand executes a single non-interactive command and returns the process exit code.
+func runCommand(args []string, db *sql.DB) int {
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Print(common.T("command.usage"))
+		return exitOk
+	}
+	common.SetInput(os.Stdin, os.Stderr)
+	logger.Infof("command %s started", strings.Join(args, " "))
+	code := subcommands(commands)(args, db)
+	logger.Infof("command %s finished with code %d", args[0], code)
+	return code
+}
+
+func subcommands(commands map[string]command) command {
+	return func(args []string, db *sql.DB) int {
+		if len(args) == 0 {
+			_, _ = fmt.Fprint(os.Stderr, common.T("command.usage"))
+			return exitUsage
+		}
+		run, ok := commands[args[0]]
+		if !ok {
+			logger.Warnf("unknown command: %s", args[0])
+			_, _ = fmt.Fprint(os.Stderr, common.T("command.usage"))
+			return exitUsage
+		}
+		return run(args[1:], db)
+	}
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	return flags
+}
+
+func usageError(message string) int {
+	_, _ = fmt.Fprintln(os.Stderr, message)
+	return exitUsage
+}
+
+func failure(err error, message string) int {
+	logger.Errorf("%s: %v", message, err)
+	_, _ = fmt.Fprintf(os.Stderr, "%s: %v\n", message, err)
+	switch {
+	case errors.Is(err, core.ErrLoginExist), errors.Is(err, core.ErrPhoneNumberExist),
+		errors.Is(err, core.ErrServiceExist), errors.Is(err, core.ErrATMExist):
+		return exitAlreadyExists
+	case errors.Is(err, core.ErrPhoneNumberNotExist), errors.Is(err, auth.ErrLoginNotExist), errors.Is(err, sql.ErrNoRows),
+		errors.Is(err, auth.ErrIdentityMismatch), errors.Is(err, auth.ErrTwoFactorNotEnabled):
+		return exitNotFound
+	}
+	return exitFailure
+}
+
+// passwordPolicyMessage tells why the password policy rejected a password with err.
+func passwordPolicyMessage(err error) (string, bool) {
+	switch {
+	case errors.Is(err, auth.ErrPasswordTooShort):
+		return common.T("client.password_short", auth.MinPasswordLength), true
+	case errors.Is(err, auth.ErrPasswordTooWeak):
+		return common.T("client.password_weak"), true
+	case errors.Is(err, auth.ErrPasswordIsLogin):
+		return common.T("client.password_login"), true
+	}
+	return "", false
+}
+
+func printClients(clients []core.Client) {
+	for _, client := range clients {
+		fmt.Printf("%d\t%s\t%s\t%d\t%s\n", client.Id, client.Name, client.Login, client.PhoneNumber, client.Status)
+	}
+}
+
+func addClientCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("client add")
+	name := flags.String("name", "", "client name")
+	phoneNumber := flags.Int64("phone", 0, "client phone number")
+	login := flags.String("login", "", "client login")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *name == "" || *phoneNumber <= 0 || *login == "" {
+		return usageError(common.T("command.client_usage"))
+	}
+	password, err := common.GetSecretInput(passwordEnv, common.T("password.prompt"))
+	if err != nil || password == "" {
+		logger.Warnf("unable to read password: %v", err)
+		return usageError(common.T("command.no_password"))
+	}
+
+	err = auth.AddClient(*name, *login, password, *phoneNumber, db)
+	if message, ok := passwordPolicyMessage(err); ok {
+		logger.Warnf("password rejected: %v", err)
+		return usageError(message)
+	}
+	if err != nil {
+		return failure(err, "unable to add client")
+	}
+	logger.Info("client added to db")
+	return exitOk
+}
+
+func listClientsCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("client list")
+	limit := flags.Int64("limit", settings.PageSize, "number of clients")
+	offset := flags.Int64("offset", 0, "number of clients to skip")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	clients, _, err := bank.GetClients("name", false, *limit, *offset, db)
+	if err != nil {
+		return failure(err, "unable to get list of clients")
+	}
+	printClients(clients)
+	return exitOk
+}
+
+func searchClientCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("client search")
+	name := flags.String("name", "", "part of client name")
+	phoneNumber := flags.Int64("phone", 0, "part of client phone number")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	var clients []core.Client
+	var err error
+	switch {
+	case *name != "" && *phoneNumber == 0:
+		clients, err = bank.SearchClientsByName(*name, db)
+	case *name == "" && *phoneNumber > 0:
+		clients, err = bank.SearchClientsByPhoneNumber(*phoneNumber, db)
+	default:
+		return usageError(common.T("command.search_usage"))
+	}
+	if err != nil {
+		return failure(err, "unable to search client")
+	}
+	if clients == nil {
+		return exitNotFound
+	}
+	printClients(clients)
+	return exitOk
+}
+
+func addAccountCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("account add")
+	phoneNumber := flags.Int64("phone", 0, "client phone number")
+	balance := flags.String("balance", "0", "initial balance in the currency of the account, e.g. 150.50")
+	currencyFlag := flags.String("currency", string(money.DefaultCurrency), strings.Join(currencyNames(), ", "))
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	amount, err := money.Parse(*balance)
+	currency, currencyErr := money.ParseCurrency(*currencyFlag)
+	if *phoneNumber <= 0 || err != nil || currencyErr != nil {
+		return usageError(common.T("command.account_usage", strings.Join(currencyNames(), ", ")))
+	}
+	accountId, err := bank.AddAccount(*phoneNumber, amount, currency, db)
+	if err != nil {
+		return failure(err, "unable to add account to client")
+	}
+	logger.Infof("account %d added", accountId)
+	return exitOk
+}
+
+// ratesCommand prints the exchange rates loaded with import --entity rates.
+func ratesCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("rates")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	rates, err := bank.GetRates(db)
+	if err != nil {
+		return failure(err, "unable to get exchange rates")
+	}
+	for _, rate := range rates {
+		fmt.Printf("%s\t%s\t%s\t%s\n", rate.Currency, rate.Rate, money.BaseCurrency, rate.Date)
+	}
+	return exitOk
+}
+
+func currencyNames() []string {
+	names := make([]string, len(money.Currencies))
+	for idx, currency := range money.Currencies {
+		names[idx] = string(currency)
+	}
+	return names
+}
+
+// fieldsFlag collects the payment fields of a service given as name:kind[:min[:max[:pattern]]], one per flag.
+type fieldsFlag []bank.ServiceField
+
+func (receiver *fieldsFlag) String() string {
+	fields := make([]string, len(*receiver))
+	for idx, field := range *receiver {
+		fields[idx] = fmt.Sprintf("%s:%s:%d:%d:%s", field.Name, field.Kind, field.MinLength, field.MaxLength, field.Pattern)
+	}
+	return strings.Join(fields, " ")
+}
+
+func (receiver *fieldsFlag) Set(value string) error {
+	parts := strings.SplitN(value, ":", 5)
+	if len(parts) < 2 {
+		return errors.New("want name:kind[:min[:max[:pattern]]]")
+	}
+	field := bank.ServiceField{Name: strings.TrimSpace(parts[0]), Kind: strings.TrimSpace(parts[1])}
+	var err error
+	if len(parts) > 2 && parts[2] != "" {
+		if field.MinLength, err = strconv.Atoi(parts[2]); err != nil {
+			return err
+		}
+	}
+	if len(parts) > 3 && parts[3] != "" {
+		if field.MaxLength, err = strconv.Atoi(parts[3]); err != nil {
+			return err
+		}
+	}
+	if len(parts) > 4 {
+		field.Pattern = parts[4]
+	}
+	if err = field.Check(); err != nil {
+		return err
+	}
+	*receiver = append(*receiver, field)
+	return nil
+}
+
+func addServiceCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("service add")
+	name := flags.String("name", "", "name of service")
+	category := flags.String("category", "", "category the service is listed under")
+	var fields fieldsFlag
+	flags.Var(&fields, "field", "payment field as name:kind[:min[:max[:pattern]]], kind is one of "+strings.Join(bank.FieldKinds, ", "))
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *name == "" {
+		return usageError(common.T("command.service_usage"))
+	}
+	err := bank.AddService(bank.Service{Name: *name, Category: *category, Fields: fields}, db)
+	if err != nil {
+		return failure(err, "unable to add service to db")
+	}
+	logger.Info("service added to db")
+	return exitOk
+}
+
+func addAtmCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("atm add")
+	name := flags.String("name", "", "name of ATM")
+	location := flags.String("location", "", "location of ATM")
+	point := flags.String("point", "", "latitude,longitude of ATM")
+	opens := flags.String("opens", "", "time ATM opens at as HH:MM, around the clock without it")
+	closes := flags.String("closes", "", "time ATM closes at as HH:MM")
+	features := flags.String("features", "", "comma separated features of ATM: "+strings.Join(bank.ATMFeatures, ", "))
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	atm := bank.ATM{Name: *name, Location: *location, Opens: *opens, Closes: *closes}
+	if *features != "" {
+		for _, feature := range strings.Split(*features, ",") {
+			atm.Features = append(atm.Features, strings.TrimSpace(feature))
+		}
+	}
+	parsed, err := bank.ParsePoint(*point)
+	if *point != "" {
+		atm.Point = &parsed
+	}
+	if *name == "" || *location == "" || (*point != "" && err != nil) || atm.Check() != nil {
+		return usageError(common.T("command.atm_usage", strings.Join(bank.ATMFeatures, ", ")))
+	}
+	err = bank.AddATM(atm, db)
+	if err != nil {
+		return failure(err, "unable to add ATM to db")
+	}
+	logger.Info("ATM added to db")
+	return exitOk
+}
+
+func entityFlag(flags *flag.FlagSet) *string {
+	return flags.String("entity", "", "one of "+core.Clients+", "+core.Accounts+", "+core.ATMs+", "+ratesEntity+" (import only)")
+}
+
+func exportCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("export")
+	entity := entityFlag(flags)
+	format := flags.String("format", "json", "json or xml")
+	out := flags.String("out", "", "output file (default <entity>.<format>)")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	list, err := getListToExport(*entity, db)
+	if errors.Is(err, errUnknownEntity) {
+		return usageError(common.T("command.invalid_entity"))
+	}
+	if err != nil {
+		return failure(err, "unable to get list to export")
+	}
+	marshal, err := marshalList("."+*format, list)
+	if errors.Is(err, errInvalidFormat) {
+		return usageError(common.T("command.invalid_format"))
+	}
+	if err != nil {
+		return failure(err, "can't marshal list")
+	}
+	fileName := *out
+	if fileName == "" {
+		fileName = exportPath(*entity + "." + *format)
+	}
+	if fileName == "-" {
+		_, err = os.Stdout.Write(marshal)
+	} else {
+		err = common.WriteExport(fileName, marshal)
+	}
+	if err != nil {
+		return failure(err, "can't write export")
+	}
+	logger.Infof("%s exported to %s", *entity, fileName)
+	return exitOk
+}
+
+func importCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("import")
+	entity := entityFlag(flags)
+	fullPath := flags.String("file", "", "file to import (.json or .xml)")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *fullPath == "" {
+		return usageError(common.T("command.file_usage"))
+	}
+	count, err := importFromFile(*entity, *fullPath, db)
+	if errors.Is(err, errUnknownEntity) {
+		return usageError(common.T("command.invalid_entity"))
+	}
+	if err != nil {
+		return failure(err, "unable to import")
+	}
+	logger.Infof("%d %s imported", count, *entity)
+	return exitOk
+}
+
+func statusCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("status")
+	phoneNumber := flags.Int64("phone", 0, "client phone number")
+	status := flags.String("set", "", core.Locked+" or "+core.Active)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *phoneNumber <= 0 || (*status != core.Locked && *status != core.Active) {
+		return usageError(common.T("command.status_usage", core.Locked, core.Active))
+	}
+	err := core.ChangeClientStatus(*phoneNumber, *status, db)
+	if err == nil && *status == core.Active {
+		err = auth.ResetFailuresByPhoneNumber(*phoneNumber, db)
+	}
+	if err != nil {
+		return failure(err, "unable to change status")
+	}
+	logger.Info("client status changed")
+	return exitOk
+}
+
+func loginFailuresCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("login failures")
+	login := flags.String("login", "", "client login, all clients by default")
+	limit := flags.Int64("limit", settings.PageSize, "number of failed logins")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	failures, err := auth.GetFailures(*login, *limit, db)
+	if err != nil {
+		return failure(err, "unable to get failed logins")
+	}
+	for _, failed := range failures {
+		fmt.Printf("%s\t%s\n", failed.Date, failed.Login)
+	}
+	return exitOk
+}
+
+// unlockCommand activates a client locked by failed logins and resets their count.
+func unlockCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("login unlock")
+	login := flags.String("login", "", "client login")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *login == "" {
+		return usageError(common.T("command.unlock_usage"))
+	}
+	err := auth.Unlock(*login, db)
+	if err != nil {
+		return failure(err, "unable to unlock client")
+	}
+	logger.Infof("client %s unlocked", *login)
+	return exitOk
+}
+
+// resetTwoFactorCommand turns off two-factor authentication, the login and the phone number the
+// client gave must belong to the same client.
+func resetTwoFactorCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("2fa reset")
+	login := flags.String("login", "", "client login")
+	phoneNumber := flags.Int64("phone", 0, "client phone number")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *login == "" || *phoneNumber <= 0 {
+		return usageError(common.T("command.2fa_usage"))
+	}
+	err := auth.ResetTwoFactor(*login, *phoneNumber, db)
+	if err != nil {
+		return failure(err, "unable to reset two-factor authentication")
+	}
+	logger.Infof("two-factor authentication of %s reset", *login)
+	return exitOk
+}
+
+// runScheduledCommand executes the scheduled transfers due by --date and prints one line per run.
+// It is meant for cron, running it again the same day repeats nothing but the retries of failed transfers
+// once their --retry-delay has passed.
+func runScheduledCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("run-scheduled")
+	date := flags.String("date", "", "run the transfers due by this date, today by default")
+	maxAttempts := flags.Int64("max-attempts", 3, "attempts before a failed transfer is given up")
+	retryDelay := flags.Duration("retry-delay", time.Hour, "wait before the first retry of a failed transfer, doubled after every next one")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	today := time.Now()
+	if *date != "" {
+		var err error
+		today, err = common.ParseDate(*date)
+		if err != nil {
+			return usageError(common.T("command.schedule_usage"))
+		}
+	}
+	if *maxAttempts <= 0 || *retryDelay < 0 {
+		return usageError(common.T("command.schedule_usage"))
+	}
+	runs, err := bank.RunScheduled(today, *maxAttempts, *retryDelay, db)
+	for _, run := range runs {
+		fmt.Printf("%d\t%s\t%d\t%s\t%s\n", run.ScheduledTransferId, run.DueDate, run.Attempt, run.Status, run.Error)
+		if run.Status == bank.RunFailed {
+			logger.Warnf("scheduled transfer %d failed, attempt %d: %s", run.ScheduledTransferId, run.Attempt, run.Error)
+		}
+	}
+	if err != nil {
+		return failure(err, "unable to run scheduled transfers")
+	}
+	logger.Infof("%d scheduled transfers run", len(runs))
+	return exitOk
+}
+
+// periodFlags registers --from and --to, the period defaults to the current month.
+func periodFlags(flags *flag.FlagSet) (from, to *string) {
+	now := time.Now()
+	firstDay := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	from = flags.String("from", firstDay.Format(bank.DateFormat), "first day of the period")
+	to = flags.String("to", now.Format(bank.DateFormat), "last day of the period")
+	return from, to
+}
+
+// statementCommand writes the statement of any account, for the checks of the bank staff.
+func statementCommand(args []string, db *sql.DB) int {
+	flags := newFlagSet("statement")
+	accountId := flags.Int64("account", 0, "account id")
+	fromFlag, toFlag := periodFlags(flags)
+	format := flags.String("format", "csv", strings.Join(common.StatementFormats, ", "))
+	out := flags.String("out", "", "output file, - for stdout (default statement-<account>-<from>-<to>.<format>)")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	from, fromErr := common.ParseDate(*fromFlag)
+	to, toErr := common.ParseDate(*toFlag)
+	if *accountId <= 0 || fromErr != nil || toErr != nil || to.Before(from) {
+		return usageError(common.T("command.stmt_usage"))
+	}
+	statement, err := bank.GetStatement(*accountId, from, to, db)
+	if err != nil {
+		return failure(err, "unable to make statement")
+	}
+	data, err := common.RenderStatement(statement, *format)
+	if errors.Is(err, common.ErrUnknownFormat) {
+		return usageError(common.T("command.stmt_usage"))
+	}
+	if err != nil {
+		return failure(err, "unable to render statement")
+	}
+	fileName := *out
+	if fileName == "" {
+		fileName = exportPath(common.StatementFileName(statement, *format))
+	}
+	if fileName == "-" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = common.WriteExport(fileName, data)
+	}
+	if err != nil {
+		return failure(err, "can't write statement")
+	}
+	logger.Infof("statement of account %d written to %s", *accountId, fileName)
+	return exitOk
+}
+
+// configCommand prints the effective settings. It runs before the database is opened,
+// so the settings can be checked even when the dsn is wrong.
+func configCommand(args []string) int {
+	if len(args) != 1 || args[0] != "show" {
+		_, _ = fmt.Fprint(os.Stderr, common.T("command.usage"))
+		return exitUsage
+	}
+	err := settings.Write(os.Stdout)
+	if err != nil {
+		logger.Errorf("unable to print config: %v", err)
+		return exitFailure
+	}
+	return exitOk
+}
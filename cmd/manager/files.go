@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// ratesEntity names the exchange rates, they can be imported only.
+const ratesEntity = "rates"
+
+var (
+	errInvalidFormat = errors.New("invalid file format")
+	errUnknownEntity = errors.New("unknown entity")
+)
+
+func marshalList(format string, list interface{}) ([]byte, error) {
+	switch format {
+	case jsonFormat:
+		return json.Marshal(list)
+	case xmlFormat:
+		return xml.Marshal(list)
+	}
+	return nil, errInvalidFormat
+}
+
+// unmarshalFile decodes the file into importTo, which must be a pointer to a slice.
+// Exported xml files hold one root element per record, so they are decoded one by one.
+func unmarshalFile(fullPath string, importTo interface{}) error {
+	file, err := ioutil.ReadFile(fullPath)
+	if err != nil {
+		return err
+	}
+	switch filepath.Ext(fullPath) {
+	case jsonFormat:
+		return json.Unmarshal(file, importTo)
+	case xmlFormat:
+		decoder := xml.NewDecoder(bytes.NewReader(file))
+		for {
+			err = decoder.Decode(importTo)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return errInvalidFormat
+}
+
+func getListToExport(entity string, db *sql.DB) (list interface{}, err error) {
+	switch entity {
+	case core.Clients:
+		return bank.GetClientRecords(db)
+	case core.Accounts:
+		return bank.GetAccountRecords(db)
+	case core.ATMs:
+		return bank.GetATMs(db)
+	}
+	return nil, errUnknownEntity
+}
+
+func importFromFile(entity, fullPath string, db *sql.DB) (count int, err error) {
+	switch entity {
+	case core.Clients:
+		var clients []bank.ClientRecord
+		if err = unmarshalFile(fullPath, &clients); err != nil {
+			return 0, fmt.Errorf("can't unmarshal %s: %w", fullPath, err)
+		}
+		return len(clients), bank.ImportClients(clients, db)
+	case core.Accounts:
+		var accounts []bank.AccountRecord
+		if err = unmarshalFile(fullPath, &accounts); err != nil {
+			return 0, fmt.Errorf("can't unmarshal %s: %w", fullPath, err)
+		}
+		return len(accounts), bank.ImportAccounts(accounts, db)
+	case core.ATMs:
+		var atms []bank.ATM
+		if err = unmarshalFile(fullPath, &atms); err != nil {
+			return 0, fmt.Errorf("can't unmarshal %s: %w", fullPath, err)
+		}
+		return len(atms), bank.ImportATMs(atms, db)
+	case ratesEntity:
+		var rates []bank.ExchangeRate
+		if err = unmarshalFile(fullPath, &rates); err != nil {
+			return 0, fmt.Errorf("can't unmarshal %s: %w", fullPath, err)
+		}
+		return len(rates), bank.SetRates(rates, db)
+	}
+	return 0, errUnknownEntity
+}
+
+// exportPath places name in the configured export directory.
+func exportPath(name string) string {
+	return filepath.Join(settings.ExportDir, name)
+}
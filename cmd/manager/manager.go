@@ -2,19 +2,25 @@ package main
 
 import (
 	"database/sql"
-	"encoding/json"
-	"encoding/xml"
 	"errors"
+	"flag"
 	"fmt"
 	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/auth"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/bank"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/config"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/storage"
 	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
-	_ "github.com/mattn/go-sqlite3"
-	"io/ioutil"
-	"log"
 	"os"
 	"strings"
+	"time"
 )
 
+// settings holds the effective configuration loaded in main.
+var settings config.Config
+
 const (
 	jsonFormat    = ".json"
 	xmlFormat     = ".xml"
@@ -23,547 +29,759 @@ const (
 )
 
 func main() {
-	file, err := os.OpenFile("manager_log.txt", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+	flag.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, common.T("command.usage"))
+	}
+	config.Flags(flag.CommandLine)
+	flag.Parse()
+	defaults := config.Default()
+	defaults.LogPath = "manager_log.txt"
+	var err error
+	settings, err = config.Load(defaults, flag.CommandLine)
 	if err != nil {
-		log.Fatal(err)
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitUsage)
 	}
-	log.SetOutput(file)
-	log.Print("start application")
-	log.Print("start opening db")
-	db, err := sql.Open("sqlite3", "db.sqlite")
+	common.SetLanguage(common.DetectLanguage(settings.Language))
+
+	exitCode := exitOk
+	defer func() {
+		if exitCode != exitOk {
+			os.Exit(exitCode)
+		}
+	}()
+	err = logger.Open(settings.LoggerOptions())
 	if err != nil {
-		log.Fatalf("can't open db: %v", err)
+		_, _ = fmt.Fprintf(os.Stderr, "can't open log: %v\n", err)
+		exitCode = exitFailure
+		return
 	}
-	log.Print("db opened")
 	defer func() {
-		log.Print("start closing db")
-		if err := db.Close(); err != nil {
-			log.Fatalf("can't close db: %v", err)
+		if err := logger.Close(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "can't close log: %v\n", err)
+			exitCode = exitFailure
 		}
-		log.Println("db closed")
-
-		if err = file.Close(); err != nil {
-			log.Fatalf("error closing file: %v", err)
+	}()
+	logger.NewSession()
+	logger.Info("start application")
+	if flag.Arg(0) == "config" {
+		exitCode = configCommand(flag.Args()[1:])
+		logger.Info("finish application")
+		return
+	}
+	logger.Debugf("start opening %s db", settings.Driver)
+	db, err := storage.Open(settings.Driver, settings.DSN)
+	if err != nil {
+		logger.Errorf("can't open db: %v", err)
+		exitCode = exitFailure
+		return
+	}
+	logger.Debug("db opened")
+	defer func() {
+		logger.Debug("start closing db")
+		if err := db.Close(); err != nil {
+			logger.Errorf("can't close db: %v", err)
+			exitCode = exitFailure
+			return
 		}
+		logger.Debug("db closed")
 	}()
-	log.Println("initialising db")
+	logger.Info("initialising db")
 	err = core.Init(db)
+	if err == nil {
+		err = bank.Init(db)
+	}
+	if err == nil {
+		err = auth.Init(db)
+	}
 	if err != nil {
-		log.Fatalf("can't initialise db: %v", err)
+		logger.Errorf("can't initialise db: %v", err)
+		exitCode = exitFailure
+		return
+	}
+	logger.Debug("db initialised")
+
+	if flag.NArg() > 0 {
+		exitCode = runCommand(flag.Args(), db)
+		logger.Info("finish application")
+		return
 	}
-	log.Println("db initialised")
 
-	fmt.Println( welcomeTitle)
-	log.Println("start operations loop")
-	operationsLoop(db, managersCommands)
-	log.Println("finish operations loop")
-	log.Println("finish application")
+	fmt.Println(common.T("welcome"))
+	logger.Debug("start operations loop")
+	_ = managerMenu(db).Run()
+	logger.Info("finish operations loop")
+	logger.Info("finish application")
 }
 
-func operationsLoop(db *sql.DB, commands string) {
-	for {
-		fmt.Print( commands)
-		cmd := common.GetCommand()
-		log.Println("start of operation selection")
-		common.ClearConsole()
-		switch cmd {
-		case "1":
-			log.Println("add client operation selected")
-			addClientToDb(db)
-		case "2":
-			log.Println("add account to client operation selected")
-			addAccountToClient(db)
-		case "3":
-			log.Println("add service operation selected")
-			addServiceToDb(db)
-		case "4":
-			log.Println("add atm operation selected")
-			addAtmToDb(db)
-		case "5":
-			log.Println("export operation selected")
-			exportOperationsLoop(db, exportImportCommands)
-		case "6":
-			log.Println("import operation selected")
-			importOperations(db, exportImportCommands)
-		case "7":
-			log.Println("print list of clients by 10 operation selected")
-			printListOfClients(db)
-		case "8":
-			log.Println("lock/unlock operation selected")
-			changeClientStatus(db)
-		case "9":
-			log.Println("search client operation selected")
-			searchClientOperations(db)
-		case "q":
-			log.Println("exit operation selected")
-			return
-		default:
-			log.Println("incorrect operation selected")
-			fmt.Printf("Вы выбрали неверную команду: %s\n", cmd)
-		}
+func managerMenu(db *sql.DB) *common.Menu {
+	return &common.Menu{
+		Title: common.T("menu.main"),
+		Quit:  common.T("menu.exit"),
+		Items: []common.MenuItem{
+			{Label: common.T("menu.add_client"), Log: "add client operation selected", Handler: func() error {
+				addClientToDb(db)
+				return nil
+			}},
+			{Label: common.T("menu.add_account"), Log: "add account to client operation selected", Handler: func() error {
+				addAccountToClient(db)
+				return nil
+			}},
+			{Label: common.T("menu.add_service"), Log: "add service operation selected", Handler: func() error {
+				addServiceToDb(db)
+				return nil
+			}},
+			{Label: common.T("menu.add_atm"), Log: "add atm operation selected", Handler: func() error {
+				addAtmToDb(db)
+				return nil
+			}},
+			{Label: common.T("menu.export"), Log: "export operation selected", Submenu: exportMenu(db)},
+			{Label: common.T("menu.import"), Log: "import operation selected", Submenu: importMenu(db)},
+			{Label: common.T("menu.clients"), Log: "print list of clients operation selected", Handler: func() error {
+				printListOfClients(db)
+				return nil
+			}},
+			{Label: common.T("menu.status"), Log: "lock/unlock operation selected", Handler: func() error {
+				changeClientStatus(db)
+				return nil
+			}},
+			{Label: common.T("menu.failures"), Log: "failed logins operation selected", Handler: func() error {
+				printLoginFailures(db)
+				return nil
+			}},
+			{Label: common.T("menu.search"), Log: "search client operation selected", Submenu: searchClientMenu(db)},
+			{Label: common.T("menu.reset_2fa"), Log: "reset two-factor authentication operation selected", Handler: func() error {
+				resetTwoFactor(db)
+				return nil
+			}},
+		},
 	}
 }
 
-func searchClientOperations(db *sql.DB) bool {
-	fmt.Print( searchClientCommands)
-	cmd := common.GetCommand()
-	common.ClearConsole()
-	switch cmd {
-	case "1":
-		log.Println("search client by name operation selected")
-		searchClientBy(byName, db)
-	case "2":
-		log.Println("search client by phone number selected")
-		searchClientBy(byPhoneNumber, db)
-	case "q":
-		log.Println("exit operation selected")
-		return true
-	default:
-		log.Println("incorrect operation selected")
-		fmt.Printf("Вы выбрали неверную команду: %s\n", cmd)
-	}
-	return false
+func searchClientMenu(db *sql.DB) *common.Menu {
+	return &common.Menu{
+		Title: common.T("menu.search"),
+		Once:  true,
+		Items: []common.MenuItem{
+			{Label: common.T("search.by_name"), Log: "search client by name operation selected", Handler: func() error {
+				searchClientBy(byName, db)
+				return nil
+			}},
+			{Label: common.T("search.by_phone"), Log: "search client by phone number selected", Handler: func() error {
+				searchClientBy(byPhoneNumber, db)
+				return nil
+			}},
+		},
+	}
 }
 
 func searchClientBy(searchType string, db *sql.DB) {
 	var clients []core.Client
-	var err error
 	if searchType == byName {
-		log.Println("asking to enter client name")
-		fmt.Print( "Введите имя пользователя: ")
-		name := common.GetStringInput()
-		log.Println("client name entered")
+		logger.Debug("asking to enter client name")
+		name, err := common.GetStringInput(common.T("search.prompt.name"))
+		if err != nil {
+			logger.Warnf("unable to read name: %v", err)
+			return
+		}
+		logger.Debug("client name entered")
 
-		log.Println("trying to search clients by name")
-		clients, err = core.SearchClientByName(name, db)
+		logger.Debug("trying to search clients by name")
+		clients, err = bank.SearchClientsByName(name, db)
 		if err != nil {
-			log.Printf("unable to search client: %v", err)
-			fmt.Println( "Поиск не удался")
+			logger.Errorf("unable to search client: %v", err)
+			fmt.Println(common.T("search.failed"))
 			return
 		}
 	} else if searchType == byPhoneNumber {
-		log.Println("asking to enter clients' phone number")
-		fmt.Print( "Введите номер телефона пользователя: ")
-		phoneNumber := common.GetIntegerInput()
-		log.Println("clients' phone number entered")
+		logger.Debug("asking to enter clients' phone number")
+		phoneNumber, err := common.GetPhoneNumberInput(common.T("search.prompt.phone"))
+		if err != nil {
+			logger.Warnf("unable to read phone number: %v", err)
+			return
+		}
+		logger.Debug("clients' phone number entered")
 
-		log.Println("trying to search clients by phone number ")
-		clients, err = core.SearchClientByPhoneNumber(phoneNumber, db)
+		logger.Debug("trying to search clients by phone number ")
+		clients, err = bank.SearchClientsByPhoneNumber(phoneNumber, db)
 		if err != nil {
-			log.Printf("unable to search client: %v", err)
-			fmt.Println( "Поиск не удался")
+			logger.Errorf("unable to search client: %v", err)
+			fmt.Println(common.T("search.failed"))
 			return
 		}
 	}
-	log.Println("search completed")
+	logger.Info("search completed")
 	if clients == nil {
-		log.Println("nothing was found")
-		fmt.Println( "Ничего не найдено")
+		logger.Info("nothing was found")
+		fmt.Println(common.T("search.nothing"))
 		return
 	}
 
+	fmt.Println(common.N("search.found", int64(len(clients)), len(clients)))
 	for indx, client := range clients {
-		fmt.Println( indx+1, ") ", client.Name, client.PhoneNumber, client.Status)
+		fmt.Println(indx+1, ") ", client.Name, client.PhoneNumber, client.Status)
 	}
 }
 
 func changeClientStatus(db *sql.DB) {
-	log.Println("asking to enter client phone number")
-	fmt.Print( "Введите номер телефона пользователя: ")
-	phoneNumber := common.GetIntegerInput()
-	log.Println("clients' phone number entered")
-
-	log.Println("asking to set status to client")
-	fmt.Print( "Выберите статус пользователю (locked/active): ")
-	status := common.GetCommand()
-	status = strings.ToLower(status)
-
-	if status == core.Active || status == core.Locked {
-		log.Println("status set")
-		log.Println("start changing client status")
-		err := core.ChangeClientStatus(phoneNumber, status, db)
-		if err != nil {
-			if errors.Is(err, core.ErrPhoneNumberNotExist) {
-				log.Println("phone number does not exist")
-				fmt.Println( "Номер телефона не существует!")
-			}
-			log.Println("unable to change status")
-			fmt.Println( "Не удалось изменить статус.")
-			return
-		}
-		fmt.Println( "Статус изменён")
+	logger.Debug("asking to enter client phone number")
+	phoneNumber, err := common.GetPhoneNumberInput(common.T("status.prompt.phone"))
+	if err != nil {
+		logger.Warnf("unable to read phone number: %v", err)
 		return
 	}
+	logger.Debug("clients' phone number entered")
 
-	log.Println("invalid status")
-	fmt.Println( "Неверный статус")
-}
+	logger.Debug("asking to set status to client")
+	status, err := common.GetChoiceInput(common.T("status.prompt.status"), core.Locked, core.Active)
+	if err != nil {
+		logger.Warnf("unable to read status: %v", err)
+		return
+	}
+	logger.Debug("status set")
 
-func printListOfClients(db *sql.DB) {
-	log.Println("start paging")
-	var page int64
-	page = 1
-	for {
-		offset := page * 10
-		if page == 1 {
-			offset = 0
-		}
-		log.Println("start getting list of clients")
-		clients, err := core.GetListOfClientsFormatted(10, offset, db)
-		if err != nil {
-			log.Printf("unable to get list of clients: %v", err)
-			fmt.Println( "Не удалось получить список пользователей!")
-			return
-		}
-		log.Println("list of clients received")
-		if clients == nil {
-			log.Println("list of clients is empty")
-			fmt.Println( "Пусто")
-			return
+	logger.Debug("start changing client status")
+	err = core.ChangeClientStatus(phoneNumber, status, db)
+	if err == nil && status == core.Active {
+		err = auth.ResetFailuresByPhoneNumber(phoneNumber, db)
+	}
+	if err != nil {
+		if errors.Is(err, core.ErrPhoneNumberNotExist) {
+			logger.Warn("phone number does not exist")
+			fmt.Println(common.T("status.phone_not_exist"))
 		}
+		logger.Error("unable to change status")
+		fmt.Println(common.T("status.failed"))
+		return
+	}
+	fmt.Println(common.T("status.done"))
+}
 
-		for indx, client := range clients {
-			fmt.Println( indx+1, ") ", client.Name, client.Login, client.PhoneNumber, client.Status)
-		}
+// resetTwoFactor turns off the two-factor authentication of a client who lost the authenticator,
+// after the manager confirms the identity of the client.
+func resetTwoFactor(db *sql.DB) {
+	logger.Debug("asking to enter client phone number")
+	phoneNumber, err := common.GetPhoneNumberInput(common.T("status.prompt.phone"))
+	if err != nil {
+		logger.Warnf("unable to read phone number: %v", err)
+		return
+	}
+	logger.Debug("clients' phone number entered")
 
-		fmt.Println( )
-		fmt.Print( pagingOperations)
-		cmd := common.GetCommand()
-		common.ClearConsole()
-		switch cmd {
-		case "1":
-			log.Println("next operation selected")
-			page++
-		case "2":
-			log.Println("prev operation selected")
-			if page != 0 {
-				page--
-			}
-		case "q":
-			log.Println("exit operation selected")
+	client, err := bank.GetClientByPhoneNumber(phoneNumber, db)
+	if err != nil {
+		if errors.Is(err, core.ErrPhoneNumberNotExist) {
+			logger.Warn("phone number does not exist")
+			fmt.Println(common.T("status.phone_not_exist"))
 			return
-		default:
-			log.Println("incorrect operation selected")
-			fmt.Printf("Вы выбрали неверную команду: %s\n", cmd)
 		}
+		logger.Errorf("unable to get client: %v", err)
+		fmt.Println(common.T("reset_2fa.failed"))
+		return
 	}
-}
 
-func importOperations(db *sql.DB, commands string) {
-	fmt.Println( importTitle)
-	fmt.Print( commands)
-	cmd := common.GetCommand()
+	logger.Debug("asking to confirm identity of client")
+	confirmed, err := common.GetConfirmInput(common.T("reset_2fa.prompt", client.Name, client.Login))
+	if err != nil {
+		logger.Warnf("unable to read confirmation: %v", err)
+		return
+	}
 	common.ClearConsole()
-	switch cmd {
-	case "1":
-		log.Println("import list of clients selected")
-		var clients []core.Client
-		unmarshaler(clients)
-
-		log.Println("start importing list of clients to db")
-		err := core.ImportListOfClients(clients, db)
-		if err != nil {
-			log.Printf("unable to import list of clients: %v", err)
-			return
-		}
-		log.Println("list of clients imported to db")
-		fmt.Println( "Список пользователей импортирован!")
-	case "2":
-		log.Println("import list of accounts with client ids selected")
-		var accountWithClientIds []core.AccountWithClientId
-		unmarshaler(accountWithClientIds)
-
-		log.Println("start importing list of accountWithClientIds to db")
-		err := core.ImportListOfAccounts(accountWithClientIds, db)
-		if err != nil {
-			log.Printf("unable to import list of accountWithClientIds: %v", err)
-			return
-		}
-		log.Println("list of accountWithClientIds imported to db")
-		fmt.Println( "Список аккаунтов с пользователями импортирован!")
-	case "3":
-		log.Println("import list of ATMs selected")
-		var atms []core.ATM
-		unmarshaler(atms)
-
-		log.Println("start importing list of atms to db")
-		err := core.ImportListOfATMs(atms, db)
-		if err != nil {
-			log.Printf("unable to import list of atms: %v", err)
+	if !confirmed {
+		logger.Info("identity of client not confirmed")
+		fmt.Println(common.T("reset_2fa.cancelled"))
+		return
+	}
+
+	err = auth.ResetTwoFactor(client.Login, phoneNumber, db)
+	if err != nil {
+		if errors.Is(err, auth.ErrTwoFactorNotEnabled) {
+			logger.Warnf("two-factor authentication of %s is not enabled", client.Login)
+			fmt.Println(common.T("reset_2fa.not_enabled"))
 			return
 		}
-		log.Println("list of atms imported to db")
-		fmt.Println( "Список банкоматов импортирован!")
-	case "q":
-		log.Println("exit operation selected")
+		logger.Errorf("unable to reset two-factor authentication: %v", err)
+		fmt.Println(common.T("reset_2fa.failed"))
 		return
-	default:
-		log.Println("incorrect operation selected")
-		fmt.Printf("Вы выбрали неверную команду: %s\n", cmd)
 	}
+	logger.Infof("two-factor authentication of %s reset", client.Login)
+	fmt.Println(common.T("reset_2fa.done", client.Login))
 }
 
-func unmarshaler(importTo interface{}) {
-	fmt.Println( importTitle)
-	log.Println("asking for full file path")
-	fmt.Print( "Введите полный путь к файлу: ")
-	fullPath := common.GetStringInput()
-	log.Println("full file path entered")
-
-	log.Println("start reading from file")
-	file, err := ioutil.ReadFile(fullPath)
+// printLoginFailures shows the last failed logins of a client, or of everyone, to decide on unlocking them.
+func printLoginFailures(db *sql.DB) {
+	logger.Debug("asking to enter client login")
+	login, err := common.GetOptionalStringInput(common.T("failures.prompt"))
 	if err != nil {
-		log.Printf("can't read from file: %v", err)
+		logger.Warnf("unable to read login: %v", err)
 		return
 	}
-	log.Println("end of reading from file")
-	log.Println("detecting file format")
-	if strings.HasSuffix(fullPath, jsonFormat) {
-		log.Println("start unmarshal")
-		err = json.Unmarshal(file, &importTo)
+	logger.Debug("client login entered")
 
-	} else if strings.HasSuffix(fullPath, xmlFormat) {
-		log.Println("start unmarshal")
-		err = xml.Unmarshal(file, &importTo)
-	} else {
-		log.Println("invalid file format")
-		fmt.Println( "Неверный формат файла!")
+	failures, err := auth.GetFailures(login, settings.PageSize, db)
+	if err != nil {
+		logger.Errorf("unable to get failed logins: %v", err)
+		fmt.Println(common.T("failures.failed"))
 		return
 	}
-
-	if err != nil {
-		log.Printf("can't unmarshal: %v", err)
+	if len(failures) == 0 {
+		fmt.Println(common.T("failures.none"))
 		return
 	}
-	log.Println("end of unmarshal")
-	common.ClearConsole()
+	for _, failed := range failures {
+		fmt.Printf("%s\t%s\n", failed.Date, failed.Login)
+	}
 }
 
-func exportOperationsLoop(db *sql.DB, commands string) {
-	for {
-		fmt.Println( exportTitle)
-		fmt.Print( commands)
-		cmd := common.GetCommand()
-		common.ClearConsole()
-		switch cmd {
-		case "1":
-			log.Println("export list of clients selected")
-
-			log.Println("start getting list of clients")
-			clients, err := core.GetListOfClients(db)
+func printListOfClients(db *sql.DB) {
+	logger.Debug("start paging")
+	pager := &common.Pager{
+		Title:   common.T("clients.title"),
+		Size:    settings.PageSize,
+		Columns: common.Columns("clients.sort.", bank.ClientColumns),
+		Error:   common.T("clients.error"),
+		Fetch: func(limit, offset int64, sort common.Sort) (page common.Page, err error) {
+			clients, total, err := bank.GetClients(sort.Column, sort.Desc, limit, offset, db)
 			if err != nil {
-				log.Printf("unable to get list of clients: %v", err)
-				fmt.Println( "Не удалось получить список пользователей")
-				return
+				return page, err
 			}
-			log.Println("list of clients received")
-			if clients == nil {
-				log.Println("list of clients is empty. No need for export.")
-				fmt.Println( "Список пользователей пуст. Нечего экспортировать!")
-				return
+			for _, client := range clients {
+				page.Rows = append(page.Rows, fmt.Sprintf("%s %s %d %s", client.Name, client.Login, client.PhoneNumber, client.Status))
 			}
-			fileFormatOperations(fileFormats, core.Clients, clients)
-		case "2":
-			log.Println("export list of accounts with client ids selected")
+			page.Total = total
+			return page, nil
+		},
+	}
+	_ = pager.Run()
+}
 
-			log.Println("start getting list of accounts with client ids")
-			accountsWithClientIds, err := core.GetListOfAccountsWithClients(db)
-			if err != nil {
-				log.Printf("unable to get list of accounts with client ids: %v", err)
-				fmt.Println( "Не удалось получить список аккаунтов с пользователями")
-				return
-			}
-			log.Println("list of accounts with client ids received")
-			if accountsWithClientIds == nil {
-				log.Println("list of accounts with client ids is empty. No need for export.")
-				fmt.Println( "Список аккаунтов с пользователями пуст. Нечего экспортировать!")
-				return
-			}
-			fileFormatOperations(fileFormats, core.Accounts, accountsWithClientIds)
-		case "3":
-			log.Println("export list of ATMs selected")
+func importMenu(db *sql.DB) *common.Menu {
+	return &common.Menu{
+		Title:  common.T("import.title"),
+		Header: boxTitle("import.box"),
+		Once:   true,
+		Items: []common.MenuItem{
+			{Label: common.T("entity.clients"), Log: "import list of clients selected", Handler: func() error {
+				importOperations(core.Clients, db)
+				return nil
+			}},
+			{Label: common.T("entity.accounts"), Log: "import list of accounts with client ids selected", Handler: func() error {
+				importOperations(core.Accounts, db)
+				return nil
+			}},
+			{Label: common.T("entity.atms"), Log: "import list of ATMs selected", Handler: func() error {
+				importOperations(core.ATMs, db)
+				return nil
+			}},
+			{Label: common.T("entity.rates"), Log: "import exchange rates selected", Handler: func() error {
+				importOperations(ratesEntity, db)
+				return nil
+			}},
+		},
+	}
+}
 
-			log.Println("start getting list of ATMs")
-			listOfATMs, err := core.GetListOfATMs(db)
-			if err != nil {
-				log.Printf("unable to get list of ATMs: %v", err)
-				fmt.Println( "Не удалось получить список банкоматов")
-				return
-			}
-			log.Println("list of ATMs received")
-			if listOfATMs == nil {
-				log.Println("list of ATMs is empty. No need for export.")
-				fmt.Println( "Список банкоматов пуст. Нечего экспортировать!")
-				return
-			}
-			fileFormatOperations(fileFormats, core.ATMs, listOfATMs)
-		case "q":
-			log.Println("exit operation selected")
-			return
-		default:
-			log.Println("incorrect operation selected")
-			fmt.Printf("Вы выбрали неверную команду: %s\n", cmd)
+func importOperations(entity string, db *sql.DB) {
+	fmt.Println(boxTitle("import.box"))
+	logger.Debug("asking for full file path")
+	fullPath, err := common.GetStringInput(common.T("import.prompt.path"))
+	if err != nil {
+		logger.Warnf("unable to read file path: %v", err)
+		return
+	}
+	logger.Debug("full file path entered")
+
+	logger.Debugf("start importing list of %s to db", entity)
+	count, err := importFromFile(entity, fullPath, db)
+	common.ClearConsole()
+	if err != nil {
+		logger.Errorf("unable to import list of %s: %v", entity, err)
+		if errors.Is(err, errInvalidFormat) {
+			fmt.Println(common.T("import.invalid_format"))
 		}
+		fmt.Println(common.T("import.failed"))
+		return
 	}
+	logger.Infof("list of %s imported to db", entity)
+	fmt.Println(common.T("import.done." + entity))
+	fmt.Println(common.N("import.count", int64(count), count))
 }
 
-func fileFormatOperations(formats string, title string, toExport interface{}) {
-	fmt.Println( formatsTitle)
-	fmt.Print( formats)
-	cmd := common.GetCommand()
-	common.ClearConsole()
-	switch cmd {
-	case "1":
-		exportTo(jsonFormat, title, toExport)
-	case "2":
-		exportTo(xmlFormat, title, toExport)
-	case "q":
-		log.Println("exit operation selected")
-		return
-	default:
-		log.Println("incorrect operation selected")
-		fmt.Printf("Вы выбрали неверную команду: %s\n", cmd)
+func exportMenu(db *sql.DB) *common.Menu {
+	return &common.Menu{
+		Title:  common.T("export.title"),
+		Header: boxTitle("export.box"),
+		Items: []common.MenuItem{
+			{Label: common.T("entity.clients"), Log: "export list of clients selected", Handler: func() error {
+				logger.Debug("start getting list of clients")
+				clients, err := bank.GetClientRecords(db)
+				if err != nil {
+					logger.Errorf("unable to get list of clients: %v", err)
+					fmt.Println(common.T("export.error.clients"))
+					return common.ErrMenuExit
+				}
+				logger.Debug("list of clients received")
+				if clients == nil {
+					logger.Info("list of clients is empty. No need for export.")
+					fmt.Println(common.T("export.empty.clients"))
+					return common.ErrMenuExit
+				}
+				return fileFormatMenu(core.Clients, clients).Run()
+			}},
+			{Label: common.T("entity.accounts"), Log: "export list of accounts with client ids selected", Handler: func() error {
+				logger.Debug("start getting list of accounts with client ids")
+				accounts, err := bank.GetAccountRecords(db)
+				if err != nil {
+					logger.Errorf("unable to get list of accounts with client ids: %v", err)
+					fmt.Println(common.T("export.error.accounts"))
+					return common.ErrMenuExit
+				}
+				logger.Debug("list of accounts with client ids received")
+				if accounts == nil {
+					logger.Info("list of accounts with client ids is empty. No need for export.")
+					fmt.Println(common.T("export.empty.accounts"))
+					return common.ErrMenuExit
+				}
+				return fileFormatMenu(core.Accounts, accounts).Run()
+			}},
+			{Label: common.T("entity.atms"), Log: "export list of ATMs selected", Handler: func() error {
+				logger.Debug("start getting list of ATMs")
+				listOfATMs, err := bank.GetATMs(db)
+				if err != nil {
+					logger.Errorf("unable to get list of ATMs: %v", err)
+					fmt.Println(common.T("export.error.atms"))
+					return common.ErrMenuExit
+				}
+				logger.Debug("list of ATMs received")
+				if listOfATMs == nil {
+					logger.Info("list of ATMs is empty. No need for export.")
+					fmt.Println(common.T("export.empty.atms"))
+					return common.ErrMenuExit
+				}
+				return fileFormatMenu(core.ATMs, listOfATMs).Run()
+			}},
+		},
+	}
+}
+
+func fileFormatMenu(title string, toExport interface{}) *common.Menu {
+	return &common.Menu{
+		Title:  common.T("formats.title"),
+		Header: boxTitle("formats.title"),
+		Once:   true,
+		Items: []common.MenuItem{
+			{Label: "json", Handler: func() error {
+				exportTo(jsonFormat, title, toExport)
+				return nil
+			}},
+			{Label: "xml", Handler: func() error {
+				exportTo(xmlFormat, title, toExport)
+				return nil
+			}},
+		},
 	}
 }
 
 func exportTo(format string, title string, export interface{}) {
-	log.Println("export started")
-	fileName := title + format
-	var err error = nil
-	var marshal []byte
-	switch format {
-	case jsonFormat:
-		marshal, err = json.Marshal(export)
-	case xmlFormat:
-		marshal, err = xml.Marshal(export)
-	default:
-		fmt.Println( "Неверный формат.")
-		return
-	}
-	if err != nil {
-		log.Fatal(err)
-	}
-	log.Printf("exporting clients to \"%s\" format", format)
-	err = ioutil.WriteFile(fileName, marshal, 0666)
-	log.Println("file exported")
-	fmt.Println( "Файл экспортирован.")
+	logger.Info("export started")
+	fileName := exportPath(title + format)
+	marshal, err := marshalList(format, export)
+	if err != nil {
+		logger.Errorf("can't marshal list of %s: %v", title, err)
+		fmt.Println(common.T("export.invalid_format"))
+		return
+	}
+	logger.Infof("exporting %s to \"%s\" format", title, format)
+	err = common.WriteExport(fileName, marshal)
+	if err != nil {
+		logger.Errorf("can't write file %s: %v", fileName, err)
+		fmt.Println(common.T("export.write_failed"))
+		return
+	}
+	logger.Infof("file %s exported", fileName)
+	fmt.Println(common.T("export.done", fileName))
 }
 
 func addAtmToDb(db *sql.DB) {
-	fmt.Println( addingAtmTitle)
-	log.Println("asking to enter byName of ATM")
-	fmt.Print( "Введите название банкомата: ")
-	nameOfAtm := common.GetStringInput()
-	log.Println("byName of ATM entered")
+	fmt.Println(boxTitle("atm.box"))
+	logger.Debug("asking to enter byName of ATM")
+	nameOfAtm, err := common.GetStringInput(common.T("atm.prompt.name"))
+	if err != nil {
+		logger.Warnf("unable to read name of ATM: %v", err)
+		return
+	}
+	logger.Debug("byName of ATM entered")
 
-	log.Println("asking to enter location of ATM")
-	fmt.Print( "Введите расположение банкомата: ")
-	locationOfAtm := common.GetStringInput()
-	log.Println("location of ATM entered")
+	logger.Debug("asking to enter location of ATM")
+	locationOfAtm, err := common.GetStringInput(common.T("atm.prompt.location"))
+	if err != nil {
+		logger.Warnf("unable to read location of ATM: %v", err)
+		return
+	}
+	logger.Debug("location of ATM entered")
 
-	log.Println("start adding ATM to db")
-	err := core.AddAtm(nameOfAtm, locationOfAtm, db)
+	atm, ok := askAtmDetails()
+	if !ok {
+		return
+	}
+	atm.Name, atm.Location = nameOfAtm, locationOfAtm
+
+	logger.Debug("start adding ATM to db")
+	err = bank.AddATM(atm, db)
 	if err != nil {
-		log.Printf("unable to add ATM to db: %v", err)
+		logger.Errorf("unable to add ATM to db: %v", err)
 		common.ClearConsole()
-		fmt.Println( "Не удалось добавить банкомат.")
+		fmt.Println(common.T("atm.failed"))
 		if errors.Is(err, core.ErrATMExist) {
-			fmt.Printf("Банкомат в \"%s\"-е уже еcть\n", locationOfAtm)
+			fmt.Println(common.T("atm.exists", locationOfAtm))
 		}
 		return
 	}
 	common.ClearConsole()
-	log.Println("ATM added to db")
-	fmt.Printf("Банкомат %s добавлен!\n", nameOfAtm)
+	logger.Info("ATM added to db")
+	fmt.Println(common.T("atm.done", nameOfAtm))
+}
+
+// askAtmDetails asks where an ATM stands, its hours and features, the coordinates and the hours may be skipped.
+func askAtmDetails() (atm bank.ATM, ok bool) {
+	logger.Debug("asking to enter coordinates of ATM")
+	point, ok := askValid(common.T("atm.prompt.point"), func(value string) bool {
+		_, err := bank.ParsePoint(value)
+		return err == nil
+	})
+	if !ok {
+		return atm, false
+	}
+	if point != "" {
+		parsed, _ := bank.ParsePoint(point)
+		atm.Point = &parsed
+	}
+	logger.Debug("coordinates of ATM entered")
+
+	validHours := func(value string) bool {
+		_, err := time.Parse(bank.HoursFormat, value)
+		return err == nil
+	}
+	logger.Debug("asking to enter hours of ATM")
+	atm.Opens, ok = askValid(common.T("atm.prompt.opens"), validHours)
+	if !ok {
+		return atm, false
+	}
+	for ok && atm.Opens != "" && atm.Closes == "" {
+		atm.Closes, ok = askValid(common.T("atm.prompt.closes"), validHours)
+	}
+	if !ok {
+		return atm, false
+	}
+	logger.Debug("hours of ATM entered")
+
+	for _, feature := range bank.ATMFeatures {
+		has, err := common.GetConfirmInput(common.T("atm.prompt.feature", common.T("atm.feature."+feature)))
+		if err != nil {
+			logger.Warnf("unable to read feature of ATM: %v", err)
+			return atm, false
+		}
+		if has {
+			atm.Features = append(atm.Features, feature)
+		}
+	}
+	logger.Debug("features of ATM entered")
+	return atm, true
+}
+
+// askValid asks an optional value until it is empty or valid.
+func askValid(prompt string, valid func(value string) bool) (string, bool) {
+	for {
+		value, err := common.GetOptionalStringInput(prompt)
+		if err != nil {
+			logger.Warnf("unable to read value: %v", err)
+			return "", false
+		}
+		if value == "" || valid(value) {
+			return value, true
+		}
+		logger.Warnf("invalid value %q", value)
+		fmt.Println(common.T("atm.invalid_value"))
+	}
 }
 
 func addServiceToDb(db *sql.DB) {
-	fmt.Println( addingServiceTitle)
-	log.Println("asking to enter byName of service")
-	fmt.Print( "Введите название услуги: ")
-	nameOfService := common.GetStringInput()
-	log.Println("byName of service entered")
-	log.Println("start adding service to db")
-	err := core.AddService(nameOfService, db)
-	if err != nil {
-		log.Printf("unable to add service to db: %v", err)
+	fmt.Println(boxTitle("service.box"))
+	logger.Debug("asking to enter byName of service")
+	nameOfService, err := common.GetStringInput(common.T("service.prompt.name"))
+	if err != nil {
+		logger.Warnf("unable to read name of service: %v", err)
+		return
+	}
+	logger.Debug("byName of service entered")
+
+	logger.Debug("asking to enter category of service")
+	category, err := common.GetOptionalStringInput(common.T("service.category"))
+	if err != nil {
+		logger.Warnf("unable to read category of service: %v", err)
+		return
+	}
+	logger.Debug("category of service entered")
+
+	fields, ok := askServiceFields()
+	if !ok {
+		return
+	}
+
+	logger.Debug("start adding service to db")
+	err = bank.AddService(bank.Service{Name: nameOfService, Category: category, Fields: fields}, db)
+	if err != nil {
+		logger.Errorf("unable to add service to db: %v", err)
 		common.ClearConsole()
-		fmt.Println( "Не удалось добавить новую услугу")
+		fmt.Println(common.T("service.failed"))
 		if errors.Is(err, core.ErrServiceExist) {
-			fmt.Printf("Услуга \"%s\" существует\n", nameOfService)
+			fmt.Println(common.T("service.exists", nameOfService))
 		}
 		return
 	}
 	common.ClearConsole()
-	log.Println("service added to db")
-	fmt.Printf("Услуга \"%s\" добавлена!\n", nameOfService)
+	logger.Info("service added to db")
+	fmt.Println(common.T("service.done", nameOfService))
+}
+
+// askServiceFields asks the payment fields of a service until an empty name is entered.
+func askServiceFields() (fields []bank.ServiceField, ok bool) {
+	kinds := strings.Join(bank.FieldKinds, ", ")
+	for {
+		logger.Debug("asking to enter name of payment field")
+		name, err := common.GetOptionalStringInput(common.T("service.field.name", len(fields)+1))
+		if err != nil {
+			logger.Warnf("unable to read name of payment field: %v", err)
+			return nil, false
+		}
+		if name == "" {
+			return fields, true
+		}
+		field := bank.ServiceField{Name: name}
+		field.Kind, err = common.GetChoiceInput(common.T("service.field.kind", kinds), bank.FieldKinds...)
+		if err != nil {
+			logger.Warnf("unable to read kind of payment field: %v", err)
+			return nil, false
+		}
+		minLength, err := common.GetIntegerInputOr(common.T("service.field.min"), 0)
+		if err != nil {
+			logger.Warnf("unable to read minimum length of payment field: %v", err)
+			return nil, false
+		}
+		maxLength, err := common.GetIntegerInputOr(common.T("service.field.max"), 0)
+		if err != nil {
+			logger.Warnf("unable to read maximum length of payment field: %v", err)
+			return nil, false
+		}
+		field.MinLength, field.MaxLength = int(minLength), int(maxLength)
+		field.Pattern, err = common.GetOptionalStringInput(common.T("service.field.pattern"))
+		if err != nil {
+			logger.Warnf("unable to read pattern of payment field: %v", err)
+			return nil, false
+		}
+		if err = field.Check(); err != nil {
+			logger.Warnf("invalid payment field: %v", err)
+			fmt.Println(common.T("service.field.invalid", name))
+			continue
+		}
+		logger.Debug("payment field entered")
+		fields = append(fields, field)
+	}
 }
 
 func addAccountToClient(db *sql.DB) {
-	fmt.Println( addingAccountToClientTitle)
-	log.Println("asking to enter phone number")
-	fmt.Print( "Введите номер телефона: ")
-	phoneNumber := common.GetIntegerInput()
-	log.Println("phone number entered")
+	fmt.Println(boxTitle("account.box"))
+	logger.Debug("asking to enter phone number")
+	phoneNumber, err := common.GetPhoneNumberInput(common.T("account.prompt.phone"))
+	if err != nil {
+		logger.Warnf("unable to read phone number: %v", err)
+		return
+	}
+	logger.Debug("phone number entered")
 
-	log.Println("asking to enter cash amount to add to account")
-	fmt.Print( "Введите сумму в рублях: ")
-	balance := common.GetIntegerInput()
-	log.Println("cash amount entered")
+	logger.Debug("asking to choose currency")
+	choice, err := common.GetChoiceInput(common.T("account.currency", strings.Join(currencyNames(), ", ")), currencyNames()...)
+	if err != nil {
+		logger.Warnf("unable to read currency: %v", err)
+		return
+	}
+	currency := money.Currency(choice)
+	logger.Debugf("currency %s chosen", currency)
 
-	log.Println("start adding account to client")
-	err := core.AddAccount(phoneNumber, balance, db)
+	logger.Debug("asking to enter cash amount to add to account")
+	balance, err := common.GetAmountInput(common.T("account.prompt.balance", currency))
 	if err != nil {
-		log.Printf("unable to add account to client: %v", err)
+		logger.Warnf("unable to read cash amount: %v", err)
+		return
+	}
+	logger.Debug("cash amount entered")
+
+	logger.Debug("start adding account to client")
+	accountId, err := bank.AddAccount(phoneNumber, balance, currency, db)
+	if err != nil {
+		logger.Errorf("unable to add account to client: %v", err)
 		common.ClearConsole()
-		fmt.Printf("Не удалось добавить счёт на номер \"%d\"\n", phoneNumber)
+		fmt.Println(common.T("account.failed", phoneNumber))
 		return
 	}
 	common.ClearConsole()
-	log.Println("account added")
-	fmt.Printf("Добавлен счёт на номер \"%d\"\n", phoneNumber)
+	logger.Infof("account %d added", accountId)
+	fmt.Println(common.T("account.done", phoneNumber, balance.Format(currency)))
 }
 
 func addClientToDb(db *sql.DB) {
-	fmt.Println( addingClientTitle)
-	fmt.Print( "Введите имя: ")
-	log.Println("asking for byName")
-	name := common.GetStringInput()
-	log.Println("byName entered")
-
-	fmt.Print( "Введите номер телефона: ")
-	log.Println("asking for phone number")
-	phoneNumber := common.GetIntegerInput()
-	log.Println("phone number entered")
-
-	fmt.Print( "Придумайте логин: ")
-	log.Println("asking to create login")
-	login := common.GetStringInput()
-	log.Println("login created")
+	fmt.Println(boxTitle("client.box"))
+	logger.Debug("asking for byName")
+	name, err := common.GetStringInput(common.T("client.prompt.name"))
+	if err != nil {
+		logger.Warnf("unable to read name: %v", err)
+		return
+	}
+	logger.Debug("byName entered")
 
-	fmt.Print( "Придумайте надёжный пароль: ")
-	log.Println("asking to create password")
-	password := common.GetStringInput()
-	log.Println("password entered")
+	logger.Debug("asking for phone number")
+	phoneNumber, err := common.GetPhoneNumberInput(common.T("client.prompt.phone"))
+	if err != nil {
+		logger.Warnf("unable to read phone number: %v", err)
+		return
+	}
+	logger.Debug("phone number entered")
 
-	log.Println("adding client to db")
+	logger.Debug("asking to create login")
+	login, err := common.GetStringInput(common.T("client.prompt.login"))
+	if err != nil {
+		logger.Warnf("unable to read login: %v", err)
+		return
+	}
+	logger.Debug("login created")
 
+	logger.Debug("asking to create password")
+	password, err := common.GetNewPasswordInput(common.T("client.prompt.password"), common.T("client.prompt.confirm"))
+	if err != nil {
+		logger.Warnf("unable to read password: %v", err)
+		return
+	}
+	logger.Debug("password entered")
 
+	logger.Info("adding client to db")
 
-	err := core.AddClient(name, login, password, phoneNumber, db)
+	err = auth.AddClient(name, login, password, phoneNumber, db)
 	if err != nil {
-		log.Printf("unable to add client: %v", err)
+		logger.Errorf("unable to add client: %v", err)
 		common.ClearConsole()
-		fmt.Println( "Не удалось добавить нового пользователя")
+		fmt.Println(common.T("client.failed"))
 		if errors.Is(err, core.ErrLoginExist) {
-			fmt.Println( "Пользователь с таким логином существует.")
+			fmt.Println(common.T("client.login_exists"))
 		}
 		if errors.Is(err, core.ErrPhoneNumberExist) {
-			fmt.Println( "Пользователь с таким номером существует")
+			fmt.Println(common.T("client.phone_exists"))
+		}
+		if message, ok := passwordPolicyMessage(err); ok {
+			fmt.Println(message)
 		}
 		return
 	}
 	common.ClearConsole()
-	log.Println("client added to db")
-	fmt.Printf("Пользователь \"%s\" добавлен!\n", name)
-}
\ No newline at end of file
+	logger.Info("client added to db")
+	fmt.Println(common.T("client.done", name))
+}
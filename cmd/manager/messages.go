@@ -0,0 +1,545 @@
+package main
+
+import (
+	"github.com/JAbduvohidov/apm-ibank-cli/cmd/common"
+	"strings"
+)
+
+func init() {
+	for lang, messages := range messages {
+		common.AddMessages(lang, messages)
+	}
+}
+
+// boxTitle returns the boxed message with id, indented like the rest of the manager screens.
+func boxTitle(id string) string {
+	return "\t" + strings.ReplaceAll(common.Box(common.T(id)), "\n", "\n\t")
+}
+
+var messages = map[common.Language]map[string]string{
+	common.Russian: {
+		"menu.add_client":        "Добавить пользователя",
+		"menu.add_account":       "Добавить счёт пользователю",
+		"menu.add_service":       "Добавить услугу",
+		"menu.add_atm":           "Добавить банкомат",
+		"menu.export":            "Экспорт (форматы json и xml)",
+		"menu.import":            "Импорт (форматы json и xml)",
+		"menu.clients":           "Вывод списка пользователей",
+		"menu.status":            "Блокировать/разблокировать пользователя",
+		"menu.failures":          "Неудачные входы пользователей",
+		"menu.search":            "Поиск пользователя",
+		"menu.reset_2fa":         "Сбросить двухфакторную аутентификацию",
+		"search.by_name":         "Поиск по имени",
+		"search.by_phone":        "Поиск по номеру",
+		"search.prompt.name":     "Введите имя пользователя: ",
+		"search.prompt.phone":    "Введите номер телефона пользователя: ",
+		"search.failed":          "Поиск не удался",
+		"search.nothing":         "Ничего не найдено",
+		"search.found.one":       "Найден %d пользователь",
+		"search.found.few":       "Найдено %d пользователя",
+		"search.found.many":      "Найдено %d пользователей",
+		"status.prompt.phone":    "Введите номер телефона пользователя: ",
+		"status.prompt.status":   "Выберите статус пользователю (locked/active): ",
+		"status.phone_not_exist": "Номер телефона не существует!",
+		"status.failed":          "Не удалось изменить статус.",
+		"status.done":            "Статус изменён",
+		"reset_2fa.prompt":       "Личность клиента %s (логин %s) подтверждена документом? (yes/no): ",
+		"reset_2fa.cancelled":    "Сброс отменён: личность клиента не подтверждена.",
+		"reset_2fa.not_enabled":  "У клиента не включена двухфакторная аутентификация.",
+		"reset_2fa.failed":       "Не удалось сбросить двухфакторную аутентификацию.",
+		"reset_2fa.done":         "Двухфакторная аутентификация клиента %s сброшена.",
+		"failures.prompt":        "Введите логин пользователя (пусто для всех): ",
+		"failures.failed":        "Не удалось получить неудачные входы.",
+		"failures.none":          "Неудачных входов нет.",
+		"clients.title":          "Список пользователей",
+		"clients.error":          "Не удалось получить список пользователей!",
+		"clients.sort.name":      "Имя",
+		"clients.sort.login":     "Логин",
+		"clients.sort.phone":     "Телефон",
+		"clients.sort.status":    "Статус",
+		"entity.clients":         "Список пользователей",
+		"entity.accounts":        "Список счетов (с пользователями)",
+		"entity.atms":            "Список банкоматов",
+		"entity.rates":           "Курсы валют",
+		"import.title":           "Импорт",
+		"import.box":             "Импортирование",
+		"import.prompt.path":     "Введите полный путь к файлу: ",
+		"import.invalid_format":  "Неверный формат файла!",
+		"import.failed":          "Импорт не удался.",
+		"import.done.clients":    "Список пользователей импортирован!",
+		"import.done.accounts":   "Список аккаунтов с пользователями импортирован!",
+		"import.done.atms":       "Список банкоматов импортирован!",
+		"import.done.rates":      "Курсы валют загружены!",
+		"import.count.one":       "Импортирована %d запись.",
+		"import.count.few":       "Импортировано %d записи.",
+		"import.count.many":      "Импортировано %d записей.",
+		"export.title":           "Экспорт",
+		"export.box":             "Экспортирование",
+		"export.error.clients":   "Не удалось получить список пользователей",
+		"export.error.accounts":  "Не удалось получить список аккаунтов с пользователями",
+		"export.error.atms":      "Не удалось получить список банкоматов",
+		"export.empty.clients":   "Список пользователей пуст. Нечего экспортировать!",
+		"export.empty.accounts":  "Список аккаунтов с пользователями пуст. Нечего экспортировать!",
+		"export.empty.atms":      "Список банкоматов пуст. Нечего экспортировать!",
+		"export.invalid_format":  "Неверный формат.",
+		"export.write_failed":    "Не удалось записать файл.",
+		"export.done":            "Файл %s экспортирован.",
+		"formats.title":          "Форматы",
+		"atm.box":                "Добавление банкомата",
+		"atm.prompt.name":        "Введите название банкомата: ",
+		"atm.prompt.location":    "Введите расположение банкомата: ",
+		"atm.prompt.point":       "Широта и долгота, например 41.311, 69.279 (необязательно): ",
+		"atm.prompt.opens":       "Время открытия ЧЧ:ММ (пусто — круглосуточно): ",
+		"atm.prompt.closes":      "Время закрытия ЧЧ:ММ: ",
+		"atm.prompt.feature":     "Есть %s? ",
+		"atm.feature.cash-in":    "приём наличных",
+		"atm.feature.currency":   "выдача валюты",
+		"atm.invalid_value":      "Значение задано неверно, введите его ещё раз",
+		"atm.failed":             "Не удалось добавить банкомат.",
+		"atm.exists":             "Банкомат по адресу \"%s\" уже есть",
+		"atm.done":               "Банкомат %s добавлен!",
+		"service.box":            "Добавление услуги",
+		"service.prompt.name":    "Введите название услуги: ",
+		"service.category":       "Категория услуги (необязательно): ",
+		"service.field.name":     "Название поля %d (пусто, чтобы закончить): ",
+		"service.field.kind":     "Тип поля (%s): ",
+		"service.field.min":      "Минимальная длина (0 без ограничения): ",
+		"service.field.max":      "Максимальная длина (0 без ограничения): ",
+		"service.field.pattern":  "Шаблон значения, регулярное выражение (необязательно): ",
+		"service.field.invalid":  "Поле «%s» задано неверно, введите его ещё раз",
+		"service.failed":         "Не удалось добавить новую услугу",
+		"service.exists":         "Услуга \"%s\" существует",
+		"service.done":           "Услуга \"%s\" добавлена!",
+		"account.box":            "Добавление счета пользователю",
+		"account.prompt.phone":   "Введите номер телефона: ",
+		"account.prompt.balance": "Введите сумму в %s: ",
+		"account.currency":       "Выберите валюту счёта (%s): ",
+		"account.failed":         "Не удалось добавить счёт на номер \"%d\"",
+		"account.done":           "Добавлен счёт на номер \"%d\" с балансом %s",
+		"client.box":             "Добавление пользователя",
+		"client.prompt.name":     "Введите имя: ",
+		"client.prompt.phone":    "Введите номер телефона: ",
+		"client.prompt.login":    "Придумайте логин: ",
+		"client.prompt.password": "Придумайте надёжный пароль: ",
+		"client.prompt.confirm":  "Повторите пароль: ",
+		"client.failed":          "Не удалось добавить нового пользователя",
+		"client.login_exists":    "Пользователь с таким логином существует.",
+		"client.phone_exists":    "Пользователь с таким номером существует",
+		"client.password_short":  "Пароль должен содержать не менее %d символов.",
+		"client.password_weak":   "Пароль должен содержать буквы и цифры.",
+		"client.password_login":  "Пароль не должен содержать логин.",
+		"client.done":            "Пользователь \"%s\" добавлен!",
+		"command.client_usage":   "Укажите --name, --phone и --login.",
+		"command.no_password":    "Пароль не задан.",
+		"command.search_usage":   "Укажите --name или --phone.",
+		"command.account_usage":  "Укажите --phone, неотрицательный --balance и --currency: %s.",
+		"command.schedule_usage": "Укажите --date в формате ГГГГ-ММ-ДД, положительный --max-attempts и неотрицательный --retry-delay.",
+		"command.stmt_usage":     "Укажите --account, даты --from и --to в формате ГГГГ-ММ-ДД и --format: csv, json, txt, html.",
+		"command.service_usage":  "Укажите --name, поля услуги задаются флагом --field имя:тип[:мин[:макс[:шаблон]]].",
+		"command.atm_usage":      "Укажите --name и --location, время --opens и --closes — ЧЧ:ММ, --point — широта,долгота, --features — из: %s.",
+		"command.invalid_entity": "Неверное значение --entity.",
+		"command.invalid_format": "Неверное значение --format.",
+		"command.file_usage":     "Укажите --file.",
+		"command.status_usage":   "Укажите --phone и --set %s|%s.",
+		"command.unlock_usage":   "Укажите --login.",
+		"command.2fa_usage":      "Укажите --login и --phone клиента.",
+		"command.usage": `Использование: manager [флаги] [команда] [флаги команды]
+
+Без команды запускается интерактивное меню.
+
+Флаги:
+  --config                                   файл настроек (.toml, .json, .yaml)
+  --driver                                   драйвер базы: sqlite3, postgres
+  --dsn                                      строка подключения к базе
+  --log-path                                 файл журнала
+  --log-level                                уровень журнала: debug, info, warn, error
+  --log-format                               формат журнала: text, json
+  --log-max-size                             размер журнала в МБ до ротации (0 без ограничения)
+  --log-max-age                              возраст журнала в днях до ротации (0 без ограничения)
+  --log-max-backups                          сколько старых журналов хранить
+  --page-size                                строк на странице
+  --lang                                     язык: ru, en, uz (по умолчанию из LANG)
+  --export-dir                               папка для экспорта
+
+Команды:
+  client add     --name --phone --login      добавить пользователя
+  client list    --limit --offset            список пользователей
+  client search  --name | --phone            поиск пользователя
+  account add    --phone --balance
+                 --currency                  добавить счёт
+  service add    --name --category
+                 --field                     добавить услугу
+  atm add        --name --location --point
+                 --opens --closes
+                 --features                  добавить банкомат
+  export         --entity --format --out     экспорт (clients, accounts, atms)
+  import         --entity --file             импорт из .json или .xml (clients, accounts, atms, rates)
+  rates                                      курсы валют к UZS
+  status         --phone --set               заблокировать/разблокировать
+  login failures --login --limit             неудачные входы
+  login unlock   --login                     разблокировать клиента и сбросить счётчик
+  2fa reset      --login --phone             сбросить двухфакторную аутентификацию
+  run-scheduled  --date --max-attempts
+                 --retry-delay               выполнить запланированные переводы (для cron)
+  statement      --account --from --to
+                 --format --out              выписка по счёту
+  config show                                вывести действующие настройки
+
+Каждый флаг можно задать переменной IBANK_<ФЛАГ>, например IBANK_PAGE_SIZE,
+или в файле ibank.toml, ibank.yaml или ibank.json в текущей папке.
+
+Пароль нового пользователя берётся из IBANK_PASSWORD или читается из stdin.
+
+Коды выхода: 0 успех, 1 ошибка, 2 неверные аргументы,
+3 запись уже существует, 4 запись не найдена.
+`,
+	},
+	common.English: {
+		"menu.add_client":        "Add client",
+		"menu.add_account":       "Add account to client",
+		"menu.add_service":       "Add service",
+		"menu.add_atm":           "Add ATM",
+		"menu.export":            "Export (json and xml formats)",
+		"menu.import":            "Import (json and xml formats)",
+		"menu.clients":           "List clients",
+		"menu.status":            "Lock/unlock client",
+		"menu.failures":          "Failed logins",
+		"menu.search":            "Search clients",
+		"menu.reset_2fa":         "Reset two-factor authentication",
+		"search.by_name":         "Search by name",
+		"search.by_phone":        "Search by phone number",
+		"search.prompt.name":     "Enter client name: ",
+		"search.prompt.phone":    "Enter client phone number: ",
+		"search.failed":          "The search failed",
+		"search.nothing":         "Nothing found",
+		"search.found.one":       "Found %d client",
+		"search.found.other":     "Found %d clients",
+		"status.prompt.phone":    "Enter client phone number: ",
+		"status.prompt.status":   "Choose client status (locked/active): ",
+		"status.phone_not_exist": "The phone number doesn't exist!",
+		"status.failed":          "Couldn't change the status.",
+		"status.done":            "Status changed",
+		"reset_2fa.prompt":       "Is the identity of %s (login %s) confirmed with a document? (yes/no): ",
+		"reset_2fa.cancelled":    "Reset cancelled: the identity of the client is not confirmed.",
+		"reset_2fa.not_enabled":  "The client has no two-factor authentication.",
+		"reset_2fa.failed":       "Couldn't reset two-factor authentication.",
+		"reset_2fa.done":         "Two-factor authentication of %s has been reset.",
+		"failures.prompt":        "Enter client login (empty for everyone): ",
+		"failures.failed":        "Couldn't get the failed logins.",
+		"failures.none":          "No failed logins.",
+		"clients.title":          "Clients",
+		"clients.error":          "Couldn't get the list of clients!",
+		"clients.sort.name":      "Name",
+		"clients.sort.login":     "Login",
+		"clients.sort.phone":     "Phone",
+		"clients.sort.status":    "Status",
+		"entity.clients":         "Clients",
+		"entity.accounts":        "Accounts (with clients)",
+		"entity.atms":            "ATMs",
+		"entity.rates":           "Exchange rates",
+		"import.title":           "Import",
+		"import.box":             "Import",
+		"import.prompt.path":     "Enter full path to the file: ",
+		"import.invalid_format":  "Invalid file format!",
+		"import.failed":          "The import failed.",
+		"import.done.clients":    "Clients imported!",
+		"import.done.accounts":   "Accounts with clients imported!",
+		"import.done.atms":       "ATMs imported!",
+		"import.done.rates":      "Exchange rates loaded!",
+		"import.count.one":       "%d record imported.",
+		"import.count.other":     "%d records imported.",
+		"export.title":           "Export",
+		"export.box":             "Export",
+		"export.error.clients":   "Couldn't get the list of clients",
+		"export.error.accounts":  "Couldn't get the list of accounts with clients",
+		"export.error.atms":      "Couldn't get the list of ATMs",
+		"export.empty.clients":   "There are no clients. Nothing to export!",
+		"export.empty.accounts":  "There are no accounts. Nothing to export!",
+		"export.empty.atms":      "There are no ATMs. Nothing to export!",
+		"export.invalid_format":  "Invalid format.",
+		"export.write_failed":    "Couldn't write the file.",
+		"export.done":            "File %s exported.",
+		"formats.title":          "Formats",
+		"atm.box":                "New ATM",
+		"atm.prompt.name":        "Enter ATM name: ",
+		"atm.prompt.location":    "Enter ATM location: ",
+		"atm.prompt.point":       "Latitude and longitude, e.g. 41.311, 69.279 (optional): ",
+		"atm.prompt.opens":       "Opening time as HH:MM (empty for around the clock): ",
+		"atm.prompt.closes":      "Closing time as HH:MM: ",
+		"atm.prompt.feature":     "Has %s? ",
+		"atm.feature.cash-in":    "cash-in",
+		"atm.feature.currency":   "currency",
+		"atm.invalid_value":      "The value is invalid, enter it again",
+		"atm.failed":             "Couldn't add the ATM.",
+		"atm.exists":             "There is already an ATM at \"%s\"",
+		"atm.done":               "ATM %s added!",
+		"service.box":            "New service",
+		"service.prompt.name":    "Enter service name: ",
+		"service.category":       "Service category (optional): ",
+		"service.field.name":     "Name of field %d (empty to finish): ",
+		"service.field.kind":     "Kind of field (%s): ",
+		"service.field.min":      "Minimum length (0 for no limit): ",
+		"service.field.max":      "Maximum length (0 for no limit): ",
+		"service.field.pattern":  "Pattern of the value, a regular expression (optional): ",
+		"service.field.invalid":  "The field \"%s\" is invalid, enter it again",
+		"service.failed":         "Couldn't add the service",
+		"service.exists":         "Service \"%s\" already exists",
+		"service.done":           "Service \"%s\" added!",
+		"account.box":            "New client account",
+		"account.prompt.phone":   "Enter phone number: ",
+		"account.prompt.balance": "Enter amount in %s: ",
+		"account.currency":       "Choose the currency of the account (%s): ",
+		"account.failed":         "Couldn't add an account for \"%d\"",
+		"account.done":           "Account with %[2]s added for \"%[1]d\"",
+		"client.box":             "New client",
+		"client.prompt.name":     "Enter name: ",
+		"client.prompt.phone":    "Enter phone number: ",
+		"client.prompt.login":    "Choose a login: ",
+		"client.prompt.password": "Choose a strong password: ",
+		"client.prompt.confirm":  "Repeat the password: ",
+		"client.failed":          "Couldn't add the client",
+		"client.login_exists":    "A client with this login already exists.",
+		"client.phone_exists":    "A client with this phone number already exists",
+		"client.password_short":  "The password must be at least %d characters long.",
+		"client.password_weak":   "The password must contain letters and digits.",
+		"client.password_login":  "The password must not contain the login.",
+		"client.done":            "Client \"%s\" added!",
+		"command.client_usage":   "Set --name, --phone and --login.",
+		"command.no_password":    "Password is not set.",
+		"command.search_usage":   "Set --name or --phone.",
+		"command.account_usage":  "Set --phone, a non-negative --balance and --currency: %s.",
+		"command.schedule_usage": "Set --date as YYYY-MM-DD, a positive --max-attempts and a non-negative --retry-delay.",
+		"command.stmt_usage":     "Set --account, the dates --from and --to as YYYY-MM-DD and --format: csv, json, txt, html.",
+		"command.service_usage":  "Set --name, give the fields of the service with --field name:kind[:min[:max[:pattern]]].",
+		"command.atm_usage":      "Set --name and --location, the times --opens and --closes as HH:MM, --point as latitude,longitude and --features from: %s.",
+		"command.invalid_entity": "Invalid --entity value.",
+		"command.invalid_format": "Invalid --format value.",
+		"command.file_usage":     "Set --file.",
+		"command.status_usage":   "Set --phone and --set %s|%s.",
+		"command.unlock_usage":   "Set --login.",
+		"command.2fa_usage":      "Set --login and --phone of the client.",
+		"command.usage": `Usage: manager [flags] [command] [command flags]
+
+Without a command the interactive menu is started.
+
+Flags:
+  --config                                   config file (.toml, .json, .yaml)
+  --driver                                   database driver: sqlite3, postgres
+  --dsn                                      database connection string
+  --log-path                                 log file
+  --log-level                                log level: debug, info, warn, error
+  --log-format                               log format: text, json
+  --log-max-size                             log size in MB before rotation (0 for no limit)
+  --log-max-age                              log age in days before rotation (0 for no limit)
+  --log-max-backups                          number of old logs to keep
+  --page-size                                rows on a page
+  --lang                                     language: ru, en, uz (default from LANG)
+  --export-dir                               export directory
+
+Commands:
+  client add     --name --phone --login      add a client
+  client list    --limit --offset            list clients
+  client search  --name | --phone            search clients
+  account add    --phone --balance
+                 --currency                  add an account
+  service add    --name --category
+                 --field                     add a service
+  atm add        --name --location --point
+                 --opens --closes
+                 --features                  add an ATM
+  export         --entity --format --out     export (clients, accounts, atms)
+  import         --entity --file             import from .json or .xml (clients, accounts, atms, rates)
+  rates                                      exchange rates to UZS
+  status         --phone --set               lock/unlock a client
+  login failures --login --limit             failed logins
+  login unlock   --login                     unlock a client and reset failed logins
+  2fa reset      --login --phone             reset two-factor authentication
+  run-scheduled  --date --max-attempts
+                 --retry-delay               run due scheduled transfers (for cron)
+  statement      --account --from --to
+                 --format --out              account statement
+  config show                                print the effective settings
+
+Every flag can also be set with IBANK_<FLAG>, for example IBANK_PAGE_SIZE,
+or in ibank.toml, ibank.yaml or ibank.json in the working directory.
+
+The new client's password is taken from IBANK_PASSWORD or read from stdin.
+
+Exit codes: 0 success, 1 error, 2 invalid arguments,
+3 record already exists, 4 record not found.
+`,
+	},
+	common.Uzbek: {
+		"menu.add_client":        "Foydalanuvchi qo‘shish",
+		"menu.add_account":       "Foydalanuvchiga hisob qo‘shish",
+		"menu.add_service":       "Xizmat qo‘shish",
+		"menu.add_atm":           "Bankomat qo‘shish",
+		"menu.export":            "Eksport (json va xml formatlari)",
+		"menu.import":            "Import (json va xml formatlari)",
+		"menu.clients":           "Foydalanuvchilar ro‘yxati",
+		"menu.status":            "Foydalanuvchini bloklash/blokdan chiqarish",
+		"menu.failures":          "Foydalanuvchilarning muvaffaqiyatsiz kirishlari",
+		"menu.search":            "Foydalanuvchini qidirish",
+		"menu.reset_2fa":         "Ikki bosqichli autentifikatsiyani bekor qilish",
+		"search.by_name":         "Ism bo‘yicha qidirish",
+		"search.by_phone":        "Raqam bo‘yicha qidirish",
+		"search.prompt.name":     "Foydalanuvchi ismini kiriting: ",
+		"search.prompt.phone":    "Foydalanuvchi telefon raqamini kiriting: ",
+		"search.failed":          "Qidiruv amalga oshmadi",
+		"search.nothing":         "Hech narsa topilmadi",
+		"search.found.other":     "%d ta foydalanuvchi topildi",
+		"status.prompt.phone":    "Foydalanuvchi telefon raqamini kiriting: ",
+		"status.prompt.status":   "Foydalanuvchi holatini tanlang (locked/active): ",
+		"status.phone_not_exist": "Bunday telefon raqami mavjud emas!",
+		"status.failed":          "Holatni o‘zgartirib bo‘lmadi.",
+		"status.done":            "Holat o‘zgartirildi",
+		"reset_2fa.prompt":       "%s (login %s) shaxsi hujjat bilan tasdiqlandimi? (yes/no): ",
+		"reset_2fa.cancelled":    "Bekor qilindi: mijoz shaxsi tasdiqlanmadi.",
+		"reset_2fa.not_enabled":  "Mijozda ikki bosqichli autentifikatsiya yoqilmagan.",
+		"reset_2fa.failed":       "Ikki bosqichli autentifikatsiyani bekor qilib bo‘lmadi.",
+		"reset_2fa.done":         "%s mijozining ikki bosqichli autentifikatsiyasi bekor qilindi.",
+		"failures.prompt":        "Foydalanuvchi loginini kiriting (hammasi uchun bo‘sh): ",
+		"failures.failed":        "Muvaffaqiyatsiz kirishlarni olib bo‘lmadi.",
+		"failures.none":          "Muvaffaqiyatsiz kirishlar yo‘q.",
+		"clients.title":          "Foydalanuvchilar ro‘yxati",
+		"clients.error":          "Foydalanuvchilar ro‘yxatini olib bo‘lmadi!",
+		"clients.sort.name":      "Ism",
+		"clients.sort.login":     "Login",
+		"clients.sort.phone":     "Telefon",
+		"clients.sort.status":    "Holat",
+		"entity.clients":         "Foydalanuvchilar ro‘yxati",
+		"entity.accounts":        "Hisoblar ro‘yxati (foydalanuvchilar bilan)",
+		"entity.atms":            "Bankomatlar ro‘yxati",
+		"entity.rates":           "Valyuta kurslari",
+		"import.title":           "Import",
+		"import.box":             "Import qilish",
+		"import.prompt.path":     "Faylning to‘liq yo‘lini kiriting: ",
+		"import.invalid_format":  "Fayl formati noto‘g‘ri!",
+		"import.failed":          "Import amalga oshmadi.",
+		"import.done.clients":    "Foydalanuvchilar ro‘yxati import qilindi!",
+		"import.done.accounts":   "Hisoblar ro‘yxati import qilindi!",
+		"import.done.atms":       "Bankomatlar ro‘yxati import qilindi!",
+		"import.done.rates":      "Valyuta kurslari yuklandi!",
+		"import.count.other":     "%d ta yozuv import qilindi.",
+		"export.title":           "Eksport",
+		"export.box":             "Eksport qilish",
+		"export.error.clients":   "Foydalanuvchilar ro‘yxatini olib bo‘lmadi",
+		"export.error.accounts":  "Hisoblar ro‘yxatini olib bo‘lmadi",
+		"export.error.atms":      "Bankomatlar ro‘yxatini olib bo‘lmadi",
+		"export.empty.clients":   "Foydalanuvchilar ro‘yxati bo‘sh. Eksport qilinadigan narsa yo‘q!",
+		"export.empty.accounts":  "Hisoblar ro‘yxati bo‘sh. Eksport qilinadigan narsa yo‘q!",
+		"export.empty.atms":      "Bankomatlar ro‘yxati bo‘sh. Eksport qilinadigan narsa yo‘q!",
+		"export.invalid_format":  "Format noto‘g‘ri.",
+		"export.write_failed":    "Faylni yozib bo‘lmadi.",
+		"export.done":            "%s fayli eksport qilindi.",
+		"formats.title":          "Formatlar",
+		"atm.box":                "Bankomat qo‘shish",
+		"atm.prompt.name":        "Bankomat nomini kiriting: ",
+		"atm.prompt.location":    "Bankomat manzilini kiriting: ",
+		"atm.prompt.point":       "Kenglik va uzunlik, masalan 41.311, 69.279 (ixtiyoriy): ",
+		"atm.prompt.opens":       "Ochilish vaqti SS:DD (bo‘sh — kecha-kunduz): ",
+		"atm.prompt.closes":      "Yopilish vaqti SS:DD: ",
+		"atm.prompt.feature":     "%s bormi? ",
+		"atm.feature.cash-in":    "Naqd pul qabul qilish",
+		"atm.feature.currency":   "Valyuta berish",
+		"atm.invalid_value":      "Qiymat noto‘g‘ri, uni qaytadan kiriting",
+		"atm.failed":             "Bankomatni qo‘shib bo‘lmadi.",
+		"atm.exists":             "\"%s\" manzilida bankomat allaqachon bor",
+		"atm.done":               "%s bankomati qo‘shildi!",
+		"service.box":            "Xizmat qo‘shish",
+		"service.prompt.name":    "Xizmat nomini kiriting: ",
+		"service.category":       "Xizmat toifasi (ixtiyoriy): ",
+		"service.field.name":     "%d-maydon nomi (tugatish uchun bo‘sh qoldiring): ",
+		"service.field.kind":     "Maydon turi (%s): ",
+		"service.field.min":      "Eng kam uzunlik (0 — cheklovsiz): ",
+		"service.field.max":      "Eng ko‘p uzunlik (0 — cheklovsiz): ",
+		"service.field.pattern":  "Qiymat shabloni, muntazam ifoda (ixtiyoriy): ",
+		"service.field.invalid":  "\"%s\" maydoni noto‘g‘ri, uni qaytadan kiriting",
+		"service.failed":         "Yangi xizmatni qo‘shib bo‘lmadi",
+		"service.exists":         "\"%s\" xizmati mavjud",
+		"service.done":           "\"%s\" xizmati qo‘shildi!",
+		"account.box":            "Foydalanuvchiga hisob qo‘shish",
+		"account.prompt.phone":   "Telefon raqamini kiriting: ",
+		"account.prompt.balance": "Summani %s da kiriting: ",
+		"account.currency":       "Hisob valyutasini tanlang (%s): ",
+		"account.failed":         "\"%d\" raqamiga hisob qo‘shib bo‘lmadi",
+		"account.done":           "\"%d\" raqamiga %s balansli hisob qo‘shildi",
+		"client.box":             "Foydalanuvchi qo‘shish",
+		"client.prompt.name":     "Ismni kiriting: ",
+		"client.prompt.phone":    "Telefon raqamini kiriting: ",
+		"client.prompt.login":    "Login o‘ylab toping: ",
+		"client.prompt.password": "Ishonchli parol o‘ylab toping: ",
+		"client.prompt.confirm":  "Parolni takrorlang: ",
+		"client.failed":          "Yangi foydalanuvchini qo‘shib bo‘lmadi",
+		"client.login_exists":    "Bunday loginli foydalanuvchi mavjud.",
+		"client.phone_exists":    "Bunday raqamli foydalanuvchi mavjud",
+		"client.password_short":  "Parol kamida %d ta belgidan iborat bo‘lishi kerak.",
+		"client.password_weak":   "Parolda harflar va raqamlar bo‘lishi kerak.",
+		"client.password_login":  "Parolda login bo‘lmasligi kerak.",
+		"client.done":            "\"%s\" foydalanuvchisi qo‘shildi!",
+		"command.client_usage":   "--name, --phone va --login ni ko‘rsating.",
+		"command.no_password":    "Parol berilmagan.",
+		"command.search_usage":   "--name yoki --phone ni ko‘rsating.",
+		"command.account_usage":  "--phone, manfiy bo‘lmagan --balance va --currency ni ko‘rsating: %s.",
+		"command.schedule_usage": "--date ni YYYY-OO-KK ko‘rinishida, musbat --max-attempts va manfiy bo‘lmagan --retry-delay ni ko‘rsating.",
+		"command.stmt_usage":     "--account, --from va --to sanalarini YYYY-OO-KK ko‘rinishida va --format ni ko‘rsating: csv, json, txt, html.",
+		"command.service_usage":  "--name ni ko‘rsating, xizmat maydonlari --field nom:tur[:min[:maks[:shablon]]] bilan beriladi.",
+		"command.atm_usage":      "--name va --location ni ko‘rsating, --opens va --closes vaqtlari SS:DD, --point kenglik,uzunlik, --features esa quyidagilardan: %s.",
+		"command.invalid_entity": "--entity qiymati noto‘g‘ri.",
+		"command.invalid_format": "--format qiymati noto‘g‘ri.",
+		"command.file_usage":     "--file ni ko‘rsating.",
+		"command.status_usage":   "--phone va --set %s|%s ni ko‘rsating.",
+		"command.unlock_usage":   "--login ni ko‘rsating.",
+		"command.2fa_usage":      "Mijozning --login va --phone ini ko‘rsating.",
+		"command.usage": `Foydalanish: manager [bayroqlar] [buyruq] [buyruq bayroqlari]
+
+Buyruqsiz interaktiv menyu ishga tushadi.
+
+Bayroqlar:
+  --config                                   sozlamalar fayli (.toml, .json, .yaml)
+  --driver                                   baza drayveri: sqlite3, postgres
+  --dsn                                      bazaga ulanish satri
+  --log-path                                 jurnal fayli
+  --log-level                                jurnal darajasi: debug, info, warn, error
+  --log-format                               jurnal formati: text, json
+  --log-max-size                             aylantirishgacha jurnal hajmi MB da (0 cheklovsiz)
+  --log-max-age                              aylantirishgacha jurnal yoshi kunlarda (0 cheklovsiz)
+  --log-max-backups                          saqlanadigan eski jurnallar soni
+  --page-size                                sahifadagi qatorlar soni
+  --lang                                     til: ru, en, uz (standart qiymat LANG dan)
+  --export-dir                               eksport papkasi
+
+Buyruqlar:
+  client add     --name --phone --login      foydalanuvchi qo‘shish
+  client list    --limit --offset            foydalanuvchilar ro‘yxati
+  client search  --name | --phone            foydalanuvchini qidirish
+  account add    --phone --balance
+                 --currency                  hisob qo‘shish
+  service add    --name --category
+                 --field                     xizmat qo‘shish
+  atm add        --name --location --point
+                 --opens --closes
+                 --features                  bankomat qo‘shish
+  export         --entity --format --out     eksport (clients, accounts, atms)
+  import         --entity --file             .json yoki .xml dan import (clients, accounts, atms, rates)
+  rates                                      UZS ga nisbatan valyuta kurslari
+  status         --phone --set               bloklash/blokdan chiqarish
+  login failures --login --limit             muvaffaqiyatsiz kirishlar
+  login unlock   --login                     mijozni blokdan chiqarish va hisoblagichni tozalash
+  2fa reset      --login --phone             ikki bosqichli autentifikatsiyani bekor qilish
+  run-scheduled  --date --max-attempts
+                 --retry-delay               rejalashtirilgan o‘tkazmalarni bajarish (cron uchun)
+  statement      --account --from --to
+                 --format --out              hisob ko‘chirmasi
+  config show                                amaldagi sozlamalarni chiqarish
+
+Har bir bayroqni IBANK_<BAYROQ> o‘zgaruvchisi, masalan IBANK_PAGE_SIZE, yoki
+joriy papkadagi ibank.toml, ibank.yaml yoki ibank.json fayli orqali berish mumkin.
+
+Yangi foydalanuvchi paroli IBANK_PASSWORD dan olinadi yoki stdin dan o‘qiladi.
+
+Chiqish kodlari: 0 muvaffaqiyat, 1 xato, 2 noto‘g‘ri argumentlar,
+3 yozuv allaqachon mavjud, 4 yozuv topilmadi.
+`,
+	},
+}
@@ -3,8 +3,13 @@ module github.com/JAbduvohidov/apm-ibank-cli
 go 1.13
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/JAbduvohidov/apm-ibank-core v0.0.0-20200213202533-fa8cd8e8517c
+	github.com/lib/pq v1.3.0
 	github.com/mattn/go-sqlite3 v2.0.3+incompatible
+	golang.org/x/crypto v0.0.0-20200208060501-ecb85df21340
+	gopkg.in/yaml.v2 v2.2.8
+	rsc.io/qr v0.2.0
 )
 
 replace github.com/JAbduvohidov/apm-ibank-core v0.0.0-20200213202533-fa8cd8e8517c => ../apm-ibank-core
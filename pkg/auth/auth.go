@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"time"
+)
+
+// Login wraps core.Login with the count of failed attempts: every failure makes the client wait
+// longer before the next attempt, and too many of them in a row lock the client.
+
+// DateFormat is the format of the dates of failed logins, it keeps them ordered as text. The
+// milliseconds keep the delays after failures exact.
+const DateFormat = "2006-01-02 15:04:05.000"
+
+// maxDelay caps the wait after a failed login.
+const maxDelay = 5 * time.Minute
+
+var (
+	ErrLockedOut     = errors.New("client locked after failed logins")
+	ErrLoginNotExist = errors.New("login not found")
+)
+
+// Init creates the tables kept by the package.
+func Init(db *sql.DB) (err error) {
+	ddls := []string{loginFailuresDDL, clientPasswordsDDL, twoFactorDDL, recoveryCodesDDL}
+	for _, ddl := range ddls {
+		_, err = db.Exec(ddl)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Policy limits the failed logins, zero values turn the limits off.
+type Policy struct {
+	// MaxFailures failures within Window lock the client.
+	MaxFailures int64
+	Window      time.Duration
+	// Delay is the wait after the first failure, it doubles with every next one.
+	Delay time.Duration
+}
+
+// DelayError is returned by Login when the client has to wait after the last failure.
+type DelayError struct {
+	Wait time.Duration
+}
+
+func (receiver *DelayError) Error() string {
+	return fmt.Sprintf("next login allowed in %s", receiver.Wait)
+}
+
+type Failure struct {
+	Id    int64
+	Login string
+	Date  string
+}
+
+// Login checks the password like core.Login, it returns -1 and no error for an unknown login.
+// Wrong passwords and unknown logins are recorded, a successful login forgets the failures.
+// A client with two-factor authentication gets the phone number and ErrCodeRequired, the
+// login is complete once VerifyCode accepts a code.
+func Login(login, password string, policy Policy, db *sql.DB) (phoneNumber int64, err error) {
+	phoneNumber, err = policy.checkPassword(login, password, time.Now(), db)
+	if err != nil || phoneNumber == -1 {
+		return phoneNumber, err
+	}
+	enabled, err := TwoFactorEnabled(login, db)
+	if err != nil {
+		return -1, err
+	}
+	if enabled {
+		return phoneNumber, ErrCodeRequired
+	}
+	_, err = db.Exec(resetLoginFailuresSQL, login)
+	return phoneNumber, err
+}
+
+// checkPassword compares password with the one of login like core.Login, after waiting for the
+// delay of the previous failures, and records a failure when they differ or the login is unknown.
+func (receiver Policy) checkPassword(login, password string, now time.Time, db *sql.DB) (phoneNumber int64, err error) {
+	failures, err := receiver.wait(login, now, db)
+	if err != nil {
+		return -1, err
+	}
+
+	stored, err := storedPassword(login, password, db)
+	if err != nil {
+		return -1, err
+	}
+	phoneNumber, err = core.Login(login, stored, db)
+	if err == nil && phoneNumber != -1 {
+		return phoneNumber, nil
+	}
+	if err != nil && !errors.Is(err, core.ErrInvalidPass) {
+		return phoneNumber, err
+	}
+
+	locked, recordErr := receiver.recordFailure(login, now, failures, db)
+	if recordErr != nil {
+		return -1, recordErr
+	}
+	if locked {
+		return -1, ErrLockedOut
+	}
+	return phoneNumber, err
+}
+
+// wait returns a DelayError when the last failure of login was too recent, and the number of
+// failures within the window otherwise.
+func (receiver Policy) wait(login string, now time.Time, db *sql.DB) (failures int64, err error) {
+	failures, last, err := countFailures(login, now.Add(-receiver.Window), db)
+	if err != nil {
+		return 0, err
+	}
+	if wait := last.Add(receiver.delay(failures)).Sub(now); failures > 0 && wait > 0 {
+		return failures, &DelayError{Wait: (wait + time.Second - 1).Truncate(time.Second)}
+	}
+	return failures, nil
+}
+
+// recordFailure adds a failure to the previous ones and locks the client when there are too many.
+func (receiver Policy) recordFailure(login string, now time.Time, failures int64, db *sql.DB) (locked bool, err error) {
+	_, err = db.Exec(addLoginFailureSQL, login, now.Format(DateFormat))
+	if err != nil {
+		return false, err
+	}
+	if receiver.MaxFailures <= 0 || failures+1 < receiver.MaxFailures {
+		return false, nil
+	}
+	return setStatus(db, login, core.Locked)
+}
+
+// delay is the wait after failures failed logins.
+func (receiver Policy) delay(failures int64) time.Duration {
+	if failures <= 0 || receiver.Delay <= 0 {
+		return 0
+	}
+	delay := receiver.Delay
+	for idx := int64(1); idx < failures && delay < maxDelay; idx++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+func countFailures(login string, since time.Time, db *sql.DB) (count int64, last time.Time, err error) {
+	var date string
+	err = db.QueryRow(countLoginFailuresSQL, login, since.Format(DateFormat)).Scan(&count, &date)
+	if err != nil || count == 0 {
+		return count, last, err
+	}
+	last, err = time.ParseInLocation(DateFormat, date, time.Local)
+	return count, last, err
+}
+
+// GetFailures returns the last limit failed logins of login, or of everyone when login is empty.
+func GetFailures(login string, limit int64, db *sql.DB) (failures []Failure, err error) {
+	rows, err := db.Query(getLoginFailuresSQL, sql.Named("login", login), sql.Named("limit", limit))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var failure Failure
+		err = rows.Scan(&failure.Id, &failure.Login, &failure.Date)
+		if err != nil {
+			return nil, err
+		}
+		failures = append(failures, failure)
+	}
+	return failures, rows.Err()
+}
+
+// Unlock makes the client active again and forgets its failed logins.
+func Unlock(login string, db *sql.DB) error {
+	found, err := setStatus(db, login, core.Active)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrLoginNotExist
+	}
+	_, err = db.Exec(resetLoginFailuresSQL, login)
+	return err
+}
+
+// ResetFailuresByPhoneNumber forgets the failed logins of the client with phoneNumber.
+func ResetFailuresByPhoneNumber(phoneNumber int64, db *sql.DB) error {
+	_, err := db.Exec(resetLoginFailuresByPhoneNumberSQL, phoneNumber)
+	return err
+}
+
+func setStatus(db *sql.DB, login, status string) (found bool, err error) {
+	result, err := db.Exec(
+		setClientStatusSQL,
+		sql.Named("status", status),
+		sql.Named("login", login),
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
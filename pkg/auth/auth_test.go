@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	_ "github.com/mattn/go-sqlite3"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// openTestDB returns a new database with the core tables and the ones of the package and
+// a client ivan with the password secret123, cleanup removes it.
+func openTestDB(t *testing.T) (db *sql.DB, cleanup func()) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err = sql.Open("sqlite3", filepath.Join(dir, "test.sqlite"))
+	if err == nil {
+		err = core.Init(db)
+	}
+	if err == nil {
+		err = Init(db)
+	}
+	if err == nil {
+		err = core.AddClient("Ivan", "ivan", "", 998901234567, db)
+	}
+	if err == nil {
+		err = SetPassword("ivan", "secret123", db)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestPolicyDelay(t *testing.T) {
+	tests := []struct {
+		policy   Policy
+		failures int64
+		want     time.Duration
+	}{
+		{Policy{Delay: time.Second}, 0, 0},
+		{Policy{Delay: time.Second}, 1, time.Second},
+		{Policy{Delay: time.Second}, 2, 2 * time.Second},
+		{Policy{Delay: time.Second}, 4, 8 * time.Second},
+		{Policy{Delay: time.Second}, 9, 256 * time.Second},
+		{Policy{Delay: time.Second}, 10, maxDelay},
+		{Policy{Delay: time.Second}, 1000, maxDelay},
+		{Policy{Delay: 10 * time.Minute}, 1, maxDelay},
+		{Policy{}, 3, 0},
+	}
+	for _, test := range tests {
+		if got := test.policy.delay(test.failures); got != test.want {
+			t.Errorf("delay(%d) with %s = %s, want %s", test.failures, test.policy.Delay, got, test.want)
+		}
+	}
+}
+
+func TestCheckPasswordDelay(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	policy := Policy{Window: time.Hour, Delay: time.Minute}
+	now := time.Now()
+
+	if _, err := policy.checkPassword("ivan", "wrong123", now, db); !errors.Is(err, core.ErrInvalidPass) {
+		t.Fatalf("checkPassword() = %v, want %v", err, core.ErrInvalidPass)
+	}
+	if _, err := policy.checkPassword("ivan", "wrong123", now.Add(time.Minute), db); !errors.Is(err, core.ErrInvalidPass) {
+		t.Fatalf("checkPassword() after the delay = %v, want %v", err, core.ErrInvalidPass)
+	}
+	tests := []struct {
+		after time.Duration
+		wait  time.Duration
+	}{
+		{time.Minute, 2 * time.Minute},
+		// the wait is rounded up to whole seconds
+		{2*time.Minute + 500*time.Millisecond, time.Minute},
+		{3 * time.Minute, 0},
+		// the failures out of the window no longer count
+		{2 * time.Hour, 0},
+	}
+	for _, test := range tests {
+		_, err := policy.wait("ivan", now.Add(test.after), db)
+		var delay *DelayError
+		if errors.As(err, &delay) != (test.wait > 0) || (delay != nil && delay.Wait != test.wait) {
+			t.Errorf("wait() %s after the first failure = %v, want a wait of %s", test.after, err, test.wait)
+		}
+	}
+	if phoneNumber, err := policy.checkPassword("ivan", "secret123", now.Add(3*time.Minute), db); err != nil || phoneNumber != 998901234567 {
+		t.Errorf("checkPassword() after the wait = %d, %v", phoneNumber, err)
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	policy := Policy{MaxFailures: 3, Window: time.Hour}
+
+	if phoneNumber, err := Login("nobody", "secret123", policy, db); err != nil || phoneNumber != -1 {
+		t.Errorf("Login() of an unknown login = %d, %v, want -1, nil", phoneNumber, err)
+	}
+	for attempt := 1; attempt < 3; attempt++ {
+		if _, err := Login("ivan", "wrong123", policy, db); !errors.Is(err, core.ErrInvalidPass) {
+			t.Fatalf("Login() attempt %d = %v, want %v", attempt, err, core.ErrInvalidPass)
+		}
+	}
+	if _, err := Login("ivan", "wrong123", policy, db); err != ErrLockedOut {
+		t.Fatalf("Login() attempt 3 = %v, want %v", err, ErrLockedOut)
+	}
+	if _, err := Login("ivan", "secret123", policy, db); !errors.Is(err, core.ErrClientIsLocked) {
+		t.Errorf("Login() of the locked client = %v, want %v", err, core.ErrClientIsLocked)
+	}
+
+	failures, err := GetFailures("ivan", 2, db)
+	if err != nil || len(failures) != 2 || failures[0].Id < failures[1].Id {
+		t.Errorf("GetFailures(ivan, 2) = %+v, %v, want the last 2", failures, err)
+	}
+	if failures, err = GetFailures("", 10, db); err != nil || len(failures) != 4 {
+		t.Errorf("GetFailures() of everyone = %d failures, %v, want 4", len(failures), err)
+	}
+
+	if err = Unlock("nobody", db); err != ErrLoginNotExist {
+		t.Errorf("Unlock() of an unknown login = %v, want %v", err, ErrLoginNotExist)
+	}
+	if err = Unlock("ivan", db); err != nil {
+		t.Fatalf("Unlock() = %v", err)
+	}
+	if failures, err = GetFailures("ivan", 10, db); err != nil || len(failures) != 0 {
+		t.Errorf("GetFailures() after Unlock() = %+v, %v, want none", failures, err)
+	}
+	if _, err = Login("ivan", "secret123", policy, db); err != nil {
+		t.Errorf("Login() after Unlock() = %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/queries"
+	"golang.org/x/crypto/scrypt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// core.Login compares passwords as they are, so clients.password keeps the scrypt key of the
+// password instead, and Login passes the key of the entered password to core.Login. Passwords
+// set before client_passwords got a salt for the login are still kept and checked as they are.
+
+// MinPasswordLength is the shortest password a client can choose.
+const MinPasswordLength = 8
+
+const saltLength = 16
+
+var (
+	ErrPasswordTooShort = errors.New("password is too short")
+	ErrPasswordTooWeak  = errors.New("password must have letters and digits")
+	ErrPasswordIsLogin  = errors.New("password contains the login")
+	ErrSamePassword     = errors.New("new password is the current one")
+	ErrSessionRevoked   = errors.New("session revoked")
+)
+
+// ValidatePassword checks password against the password policy.
+func ValidatePassword(login, password string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+	var letter, digit bool
+	for _, char := range password {
+		letter = letter || unicode.IsLetter(char)
+		digit = digit || unicode.IsDigit(char)
+	}
+	if !letter || !digit {
+		return ErrPasswordTooWeak
+	}
+	if login != "" && strings.Contains(strings.ToLower(password), strings.ToLower(login)) {
+		return ErrPasswordIsLogin
+	}
+	return nil
+}
+
+// ChangePassword replaces the password of login after checking the current one, and revokes
+// all the sessions of the client, the current one too. A wrong current password counts as
+// a failed login of policy, so it can make the client wait or lock it like Login does.
+func ChangePassword(login, current, password string, policy Policy, db *sql.DB) error {
+	phoneNumber, err := policy.checkPassword(login, current, time.Now(), db)
+	if err != nil {
+		return err
+	}
+	if phoneNumber == -1 {
+		return core.ErrInvalidPass
+	}
+	_, err = db.Exec(resetLoginFailuresSQL, login)
+	if err != nil {
+		return err
+	}
+	if current == password {
+		return ErrSamePassword
+	}
+	err = ValidatePassword(login, password)
+	if err != nil {
+		return err
+	}
+	return SetPassword(login, password, db)
+}
+
+// AddClient adds a client like core.AddClient with the key of password stored in the same
+// transaction, so the password as it was entered is never kept. The initial password has to
+// pass the password policy too.
+func AddClient(name, login, password string, phoneNumber int64, db *sql.DB) error {
+	err := ValidatePassword(login, password)
+	if err != nil {
+		return err
+	}
+	key, salt, err := newPasswordKey(password)
+	if err != nil {
+		return err
+	}
+	return inTx(db, func(tx *sql.Tx) error {
+		var existing string
+		err := tx.QueryRow(queries.LoginExistSQL, login).Scan(&existing)
+		if err == nil {
+			return core.ErrLoginExist
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		err = tx.QueryRow(queries.PhoneNumberExistSQL, phoneNumber).Scan(&existing)
+		if err == nil {
+			return core.ErrPhoneNumberExist
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		_, err = tx.Exec(
+			queries.AddClientSQL,
+			sql.Named("name", name),
+			sql.Named("login", login),
+			sql.Named("password", key),
+			sql.Named("phone_number", phoneNumber),
+		)
+		if err != nil {
+			return err
+		}
+		return storeSalt(tx, login, salt)
+	})
+}
+
+// SetPassword stores the key of password for login, it doesn't check the password policy.
+func SetPassword(login, password string, db *sql.DB) error {
+	key, salt, err := newPasswordKey(password)
+	if err != nil {
+		return err
+	}
+	return inTx(db, func(tx *sql.Tx) error {
+		err := storeSalt(tx, login, salt)
+		if err != nil {
+			return err
+		}
+		result, err := tx.Exec(
+			setClientPasswordSQL,
+			sql.Named("password", key),
+			sql.Named("login", login),
+		)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err == nil && affected == 0 {
+			return ErrLoginNotExist
+		}
+		return err
+	})
+}
+
+// newPasswordKey returns the key of password made with a new random salt.
+func newPasswordKey(password string) (key, salt string, err error) {
+	random := make([]byte, saltLength)
+	_, err = rand.Read(random)
+	if err != nil {
+		return "", "", err
+	}
+	salt = hex.EncodeToString(random)
+	key, err = passwordKey(password, salt)
+	return key, salt, err
+}
+
+// storeSalt replaces the salt of the password of login, revoking the sessions started before.
+func storeSalt(tx *sql.Tx, login, salt string) error {
+	_, err := tx.Exec(deleteClientPasswordSQL, login)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		addClientPasswordSQL,
+		sql.Named("login", login),
+		sql.Named("salt", salt),
+		sql.Named("revoked_before", time.Now().UnixNano()),
+	)
+	return err
+}
+
+// CheckSession returns ErrSessionRevoked when the password of login changed after started.
+func CheckSession(login string, started time.Time, db *sql.DB) error {
+	var revokedBefore int64
+	err := db.QueryRow(getRevokedBeforeSQL, login).Scan(&revokedBefore)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if started.UnixNano() < revokedBefore {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+// storedPassword returns what clients.password keeps for password.
+func storedPassword(login, password string, db *sql.DB) (string, error) {
+	var salt string
+	err := db.QueryRow(getPasswordSaltSQL, login).Scan(&salt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return password, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return passwordKey(password, salt)
+}
+
+func passwordKey(password, salt string) (string, error) {
+	key, err := scrypt.Key([]byte(password), []byte(salt), 1<<15, 8, 1, 32)
+	if err != nil {
+		return "", err
+	}
+	return "scrypt:" + hex.EncodeToString(key), nil
+}
+
+func inTx(db *sql.DB, do func(tx *sql.Tx) error) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+	return do(tx)
+}
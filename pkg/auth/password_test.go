@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"errors"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"testing"
+	"time"
+)
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		login    string
+		password string
+		err      error
+	}{
+		{"ivan", "secret123", nil},
+		{"ivan", "пароль2020", nil},
+		{"", "ivan2020x", nil},
+		{"ivan", "short1", ErrPasswordTooShort},
+		{"ivan", "пароль1", ErrPasswordTooShort},
+		{"ivan", "secretsecret", ErrPasswordTooWeak},
+		{"ivan", "1234567890", ErrPasswordTooWeak},
+		{"ivan", "my-IVAN-2020", ErrPasswordIsLogin},
+	}
+	for _, test := range tests {
+		if err := ValidatePassword(test.login, test.password); err != test.err {
+			t.Errorf("ValidatePassword(%q, %q) = %v, want %v", test.login, test.password, err, test.err)
+		}
+	}
+}
+
+func TestPasswordKey(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	var stored string
+	if err := db.QueryRow(`SELECT password FROM clients WHERE login = 'ivan'`).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored == "secret123" || len(stored) != len("scrypt:")+64 {
+		t.Errorf("clients.password = %q, want the scrypt key", stored)
+	}
+	if phoneNumber, err := Login("ivan", "secret123", Policy{}, db); err != nil || phoneNumber != 998901234567 {
+		t.Errorf("Login() = %d, %v", phoneNumber, err)
+	}
+	if phoneNumber, err := Login("ivan", stored, Policy{}, db); err == nil && phoneNumber != -1 {
+		t.Errorf("Login() with the stored key = %d, want a failure", phoneNumber)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	policy := Policy{MaxFailures: 3, Window: time.Hour}
+
+	if err := ChangePassword("ivan", "secret123", "secret123", policy, db); err != ErrSamePassword {
+		t.Errorf("ChangePassword() to the same password = %v, want %v", err, ErrSamePassword)
+	}
+	if err := ChangePassword("ivan", "secret123", "short", policy, db); err != ErrPasswordTooShort {
+		t.Errorf("ChangePassword() to a short password = %v, want %v", err, ErrPasswordTooShort)
+	}
+	if err := ChangePassword("ivan", "secret123", "newpass123", policy, db); err != nil {
+		t.Fatalf("ChangePassword() = %v", err)
+	}
+	if _, err := Login("ivan", "newpass123", policy, db); err != nil {
+		t.Errorf("Login() with the new password = %v", err)
+	}
+	if phoneNumber, err := Login("ivan", "secret123", policy, db); err == nil && phoneNumber != -1 {
+		t.Errorf("Login() with the old password = %d, want a failure", phoneNumber)
+	}
+	if err := CheckSession("ivan", time.Now().Add(-time.Second), db); err != ErrSessionRevoked {
+		t.Errorf("CheckSession() of a session started before the change = %v, want %v", err, ErrSessionRevoked)
+	}
+}
+
+func TestChangePasswordCountsFailures(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	policy := Policy{MaxFailures: 3, Window: time.Hour}
+
+	for attempt := 1; attempt < 3; attempt++ {
+		if err := ChangePassword("ivan", "wrong123", "newpass123", policy, db); !errors.Is(err, core.ErrInvalidPass) {
+			t.Fatalf("ChangePassword() attempt %d = %v, want %v", attempt, err, core.ErrInvalidPass)
+		}
+	}
+	if err := ChangePassword("ivan", "wrong123", "newpass123", policy, db); err != ErrLockedOut {
+		t.Fatalf("ChangePassword() attempt 3 = %v, want %v", err, ErrLockedOut)
+	}
+	if _, err := Login("ivan", "secret123", Policy{}, db); !errors.Is(err, core.ErrClientIsLocked) {
+		t.Errorf("Login() of the locked client = %v, want %v", err, core.ErrClientIsLocked)
+	}
+}
+
+func TestChangePasswordWaits(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	policy := Policy{Window: time.Hour, Delay: time.Minute}
+
+	if err := ChangePassword("ivan", "wrong123", "newpass123", policy, db); !errors.Is(err, core.ErrInvalidPass) {
+		t.Fatalf("ChangePassword() = %v, want %v", err, core.ErrInvalidPass)
+	}
+	var delay *DelayError
+	if err := ChangePassword("ivan", "secret123", "newpass123", policy, db); !errors.As(err, &delay) || delay.Wait != time.Minute {
+		t.Errorf("ChangePassword() right after a failure = %v, want a wait of %s", err, time.Minute)
+	}
+}
+
+func TestAddClient(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+
+	if err := AddClient("Anna", "anna", "blossom42", 998907654321, db); err != nil {
+		t.Fatalf("AddClient() = %v", err)
+	}
+	var stored string
+	if err := db.QueryRow(`SELECT password FROM clients WHERE login = 'anna'`).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored == "blossom42" {
+		t.Errorf("clients.password keeps the password as it was entered")
+	}
+	if phoneNumber, err := Login("anna", "blossom42", Policy{}, db); err != nil || phoneNumber != 998907654321 {
+		t.Errorf("Login() of the added client = %d, %v", phoneNumber, err)
+	}
+
+	if err := AddClient("Anna", "anna", "blossom42", 998900000000, db); err != core.ErrLoginExist {
+		t.Errorf("AddClient() with a taken login = %v, want %v", err, core.ErrLoginExist)
+	}
+	if err := AddClient("Anna", "anna2", "blossom42", 998907654321, db); err != core.ErrPhoneNumberExist {
+		t.Errorf("AddClient() with a taken phone number = %v, want %v", err, core.ErrPhoneNumberExist)
+	}
+	if err := AddClient("Anna", "anna2", "short1", 998900000000, db); err != ErrPasswordTooShort {
+		t.Errorf("AddClient() with a weak password = %v, want %v", err, ErrPasswordTooShort)
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM client_passwords WHERE login = 'anna2'`).Scan(&count); err != nil || count != 0 {
+		t.Errorf("salts of the clients not added = %d, %v, want none", count, err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM clients WHERE login = 'anna2'`).Scan(&count); err != nil || count != 0 {
+		t.Errorf("clients added with a weak password = %d, %v, want none", count, err)
+	}
+}
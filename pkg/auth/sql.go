@@ -0,0 +1,116 @@
+package auth
+
+const loginFailuresDDL = `CREATE TABLE IF NOT EXISTS login_failures
+(
+    id    INTEGER PRIMARY KEY AUTOINCREMENT,
+    login TEXT NOT NULL,
+    date  TEXT NOT NULL
+);`
+
+const addLoginFailureSQL = `INSERT INTO login_failures(login, date)
+VALUES (?, ?);`
+
+const countLoginFailuresSQL = `SELECT COUNT(*), COALESCE(MAX(date), '')
+FROM login_failures
+WHERE login = ?
+  AND date >= ?;`
+
+const getLoginFailuresSQL = `SELECT id, login, date
+FROM login_failures
+WHERE :login = '' OR login = :login
+ORDER BY date DESC, id DESC
+LIMIT :limit;`
+
+const resetLoginFailuresSQL = `DELETE
+FROM login_failures
+WHERE login = ?;`
+
+const resetLoginFailuresByPhoneNumberSQL = `DELETE
+FROM login_failures
+WHERE login IN (SELECT login FROM clients WHERE phone_number = ?);`
+
+const setClientStatusSQL = `UPDATE clients
+SET status = :status
+WHERE login = :login;`
+
+// clientPasswordsDDL keeps the salts of the passwords stored hashed in clients.password
+// and the time before which the sessions of the client are revoked, in unix nanoseconds.
+const clientPasswordsDDL = `CREATE TABLE IF NOT EXISTS client_passwords
+(
+    login          TEXT PRIMARY KEY,
+    salt           TEXT    NOT NULL,
+    revoked_before INTEGER NOT NULL DEFAULT 0
+);`
+
+const getPasswordSaltSQL = `SELECT salt
+FROM client_passwords
+WHERE login = ?;`
+
+const getRevokedBeforeSQL = `SELECT revoked_before
+FROM client_passwords
+WHERE login = ?;`
+
+const deleteClientPasswordSQL = `DELETE
+FROM client_passwords
+WHERE login = ?;`
+
+const addClientPasswordSQL = `INSERT INTO client_passwords(login, salt, revoked_before)
+VALUES (:login, :salt, :revoked_before);`
+
+const setClientPasswordSQL = `UPDATE clients
+SET password = :password
+WHERE login = :login;`
+
+// twoFactorDDL keeps the TOTP secrets of the clients who enabled two-factor authentication,
+// last_step is the time step of the last accepted code, so a code is accepted only once.
+const twoFactorDDL = `CREATE TABLE IF NOT EXISTS two_factor
+(
+    login     TEXT PRIMARY KEY,
+    secret    TEXT    NOT NULL,
+    last_step INTEGER NOT NULL DEFAULT 0
+);`
+
+// recoveryCodesDDL keeps the SHA-256 of the unused recovery codes.
+const recoveryCodesDDL = `CREATE TABLE IF NOT EXISTS recovery_codes
+(
+    id    INTEGER PRIMARY KEY AUTOINCREMENT,
+    login TEXT NOT NULL,
+    code  TEXT NOT NULL
+);`
+
+const getTwoFactorSQL = `SELECT secret, last_step
+FROM two_factor
+WHERE login = ?;`
+
+const addTwoFactorSQL = `INSERT INTO two_factor(login, secret, last_step)
+VALUES (:login, :secret, :last_step);`
+
+const setLastStepSQL = `UPDATE two_factor
+SET last_step = :step
+WHERE login = :login
+  AND last_step < :step;`
+
+const deleteTwoFactorSQL = `DELETE
+FROM two_factor
+WHERE login = ?;`
+
+const addRecoveryCodeSQL = `INSERT INTO recovery_codes(login, code)
+VALUES (?, ?);`
+
+const useRecoveryCodeSQL = `DELETE
+FROM recovery_codes
+WHERE login = ?
+  AND code = ?;`
+
+const countRecoveryCodesSQL = `SELECT COUNT(*)
+FROM recovery_codes
+WHERE login = ?;`
+
+const deleteRecoveryCodesSQL = `DELETE
+FROM recovery_codes
+WHERE login = ?;`
+
+const checkClientIdentitySQL = `SELECT COUNT(*)
+FROM clients
+WHERE login = ?
+  AND phone_number = ?;`
@@ -0,0 +1,282 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Two-factor authentication uses the time-based one-time passwords of RFC 6238 with the
+// defaults of the authenticator apps: HMAC-SHA1, 6 digits and a 30 seconds step. Recovery
+// codes let the client in once each when the authenticator is lost.
+
+// Issuer names the bank in the authenticator apps.
+const Issuer = "IBank"
+
+// CodeLength is the number of digits of a one-time code.
+const CodeLength = 6
+
+// RecoveryCodes is the number of recovery codes issued on enrolment.
+const RecoveryCodes = 10
+
+const (
+	codeStep     = 30 * time.Second
+	secretLength = 20
+	// codeDrift is the number of steps a code may be late or early, for clocks out of sync.
+	codeDrift = 1
+)
+
+var (
+	ErrCodeRequired        = errors.New("one-time code required")
+	ErrInvalidCode         = errors.New("invalid one-time code")
+	ErrTwoFactorEnabled    = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication not enabled")
+	ErrIdentityMismatch    = errors.New("login and phone number belong to different clients")
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewSecret returns a random secret for an authenticator, encoded in base32.
+func NewSecret() (string, error) {
+	secret := make([]byte, secretLength)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+	return secretEncoding.EncodeToString(secret), nil
+}
+
+// KeyURI returns the otpauth URI of secret that authenticator apps read from a QR code.
+func KeyURI(login, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", Issuer)
+	query.Set("digits", fmt.Sprint(CodeLength))
+	query.Set("period", fmt.Sprint(int64(codeStep.Seconds())))
+	return fmt.Sprintf("otpauth://totp/%s:%s?%s", url.PathEscape(Issuer), url.PathEscape(login), query.Encode())
+}
+
+// Code returns the one-time code of secret at date.
+func Code(secret string, date time.Time) (string, error) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+	return codeAt(key, step(date)), nil
+}
+
+func step(date time.Time) int64 {
+	return date.Unix() / int64(codeStep.Seconds())
+}
+
+// codeAt is the HOTP value of RFC 4226 for the counter step.
+func codeAt(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulo := uint32(1)
+	for idx := 0; idx < CodeLength; idx++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", CodeLength, value%modulo)
+}
+
+// matchCode returns the step code was made for, only steps after last are accepted.
+func matchCode(secret, code string, now time.Time, last int64) (matched int64, ok bool, err error) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false, err
+	}
+	current := step(now)
+	for drift := int64(-codeDrift); drift <= codeDrift; drift++ {
+		candidate := current + drift
+		if candidate > last && hmac.Equal([]byte(codeAt(key, candidate)), []byte(code)) {
+			return candidate, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// EnableTwoFactor turns on two-factor authentication for login once code shows that the
+// authenticator has secret, and returns the recovery codes. They are kept hashed, so they
+// can't be shown again.
+func EnableTwoFactor(login, secret, code string, db *sql.DB) (recovery []string, err error) {
+	enabled, err := TwoFactorEnabled(login, db)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	matched, ok, err := matchCode(secret, strings.TrimSpace(code), time.Now(), 0)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidCode
+	}
+	recovery, err = newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = inTx(db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(
+			addTwoFactorSQL,
+			sql.Named("login", login),
+			sql.Named("secret", secret),
+			sql.Named("last_step", matched),
+		)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(deleteRecoveryCodesSQL, login)
+		if err != nil {
+			return err
+		}
+		for _, code := range recovery {
+			_, err = tx.Exec(addRecoveryCodeSQL, login, recoveryHash(code))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recovery, nil
+}
+
+func newRecoveryCodes() ([]string, error) {
+	codes := make([]string, RecoveryCodes)
+	for idx := range codes {
+		random := make([]byte, 5)
+		_, err := rand.Read(random)
+		if err != nil {
+			return nil, err
+		}
+		code := hex.EncodeToString(random)
+		codes[idx] = code[:5] + "-" + code[5:]
+	}
+	return codes, nil
+}
+
+// recoveryHash ignores the case and the dashes and spaces the client may type in a code.
+func recoveryHash(code string) string {
+	code = strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func TwoFactorEnabled(login string, db *sql.DB) (bool, error) {
+	var secret string
+	var last int64
+	err := db.QueryRow(getTwoFactorSQL, login).Scan(&secret, &last)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// VerifyCode completes the login that returned ErrCodeRequired with a one-time or a recovery
+// code. Wrong codes count as failed logins of policy, a right one forgets the failures.
+func VerifyCode(login, code string, policy Policy, db *sql.DB) error {
+	now := time.Now()
+	failures, err := policy.wait(login, now, db)
+	if err != nil {
+		return err
+	}
+	var secret string
+	var last int64
+	err = db.QueryRow(getTwoFactorSQL, login).Scan(&secret, &last)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return err
+	}
+
+	code = strings.TrimSpace(code)
+	ok, err := useCode(login, secret, code, now, last, db)
+	if err != nil {
+		return err
+	}
+	if ok {
+		_, err = db.Exec(resetLoginFailuresSQL, login)
+		return err
+	}
+	locked, err := policy.recordFailure(login, now, failures, db)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrLockedOut
+	}
+	return ErrInvalidCode
+}
+
+// useCode accepts a one-time code newer than the last accepted one or an unused recovery code.
+func useCode(login, secret, code string, now time.Time, last int64, db *sql.DB) (bool, error) {
+	matched, ok, err := matchCode(secret, code, now, last)
+	if err != nil {
+		return false, err
+	}
+	query, args := useRecoveryCodeSQL, []interface{}{login, recoveryHash(code)}
+	if ok {
+		query, args = setLastStepSQL, []interface{}{sql.Named("step", matched), sql.Named("login", login)}
+	}
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// RecoveryCodesLeft returns the number of unused recovery codes of login.
+func RecoveryCodesLeft(login string, db *sql.DB) (count int64, err error) {
+	err = db.QueryRow(countRecoveryCodesSQL, login).Scan(&count)
+	return count, err
+}
+
+// ResetTwoFactor turns off two-factor authentication of the client with login and phoneNumber,
+// so the client can log in with the password and enrol again.
+func ResetTwoFactor(login string, phoneNumber int64, db *sql.DB) error {
+	var count int64
+	err := db.QueryRow(checkClientIdentitySQL, login, phoneNumber).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrIdentityMismatch
+	}
+	return inTx(db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(deleteTwoFactorSQL, login)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrTwoFactorNotEnabled
+		}
+		_, err = tx.Exec(deleteRecoveryCodesSQL, login)
+		return err
+	})
+}
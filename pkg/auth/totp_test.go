@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the test vectors of RFC 6238.
+var rfcSecret = secretEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeRFC6238(t *testing.T) {
+	// The RFC gives 8 digit codes, the 6 digit ones are their last digits.
+	tests := []struct {
+		seconds int64
+		want    string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, test := range tests {
+		code, err := Code(rfcSecret, time.Unix(test.seconds, 0))
+		if err != nil {
+			t.Fatalf("Code() error = %v", err)
+		}
+		if want := test.want[len(test.want)-CodeLength:]; code != want {
+			t.Errorf("Code() at %d = %s, want %s", test.seconds, code, want)
+		}
+	}
+}
+
+func TestCodeIgnoresCase(t *testing.T) {
+	upper, err := Code(rfcSecret, time.Unix(59, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lower, err := Code("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", time.Unix(59, 0))
+	if err != nil || lower != upper {
+		t.Errorf("Code() of the lower case secret = %s, %v, want %s", lower, err, upper)
+	}
+	if _, err = Code("not base32!", time.Unix(59, 0)); err == nil {
+		t.Error("Code() accepted an invalid secret")
+	}
+}
+
+func TestMatchCodeWindow(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := step(now)
+	codeOf := func(step int64) string {
+		code, err := Code(rfcSecret, time.Unix(step*int64(codeStep.Seconds()), 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+	tests := []struct {
+		name string
+		step int64
+		last int64
+		ok   bool
+	}{
+		{"current step", current, 0, true},
+		{"one step late", current - 1, 0, true},
+		{"one step early", current + 1, 0, true},
+		{"two steps late", current - 2, 0, false},
+		{"two steps early", current + 2, 0, false},
+		{"used step", current, current, false},
+		{"step before the used one", current - 1, current, false},
+		{"step after the used one", current + 1, current, true},
+	}
+	for _, test := range tests {
+		matched, ok, err := matchCode(rfcSecret, codeOf(test.step), now, test.last)
+		if err != nil {
+			t.Fatalf("%s: matchCode() error = %v", test.name, err)
+		}
+		if ok != test.ok || (ok && matched != test.step) {
+			t.Errorf("%s: matchCode() = %d, %t, want %d, %t", test.name, matched, ok, test.step, test.ok)
+		}
+	}
+	if _, ok, _ := matchCode(rfcSecret, "000000", now, 0); ok && codeOf(current) != "000000" {
+		t.Error("matchCode() accepted a wrong code")
+	}
+}
+
+func TestKeyURI(t *testing.T) {
+	want := "otpauth://totp/IBank:ivan%20petrov?digits=6&issuer=IBank&period=30&secret=" + rfcSecret
+	if got := KeyURI("ivan petrov", rfcSecret); got != want {
+		t.Errorf("KeyURI() = %s, want %s", got, want)
+	}
+}
+
+func TestTwoFactor(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := Code(secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	if _, err = EnableTwoFactor("ivan", secret, wrong, db); err != ErrInvalidCode {
+		t.Fatalf("EnableTwoFactor() with a wrong code = %v, want %v", err, ErrInvalidCode)
+	}
+	recovery, err := EnableTwoFactor("ivan", secret, code, db)
+	if err != nil || len(recovery) != RecoveryCodes {
+		t.Fatalf("EnableTwoFactor() = %d recovery codes, %v", len(recovery), err)
+	}
+	if _, err = EnableTwoFactor("ivan", secret, code, db); err != ErrTwoFactorEnabled {
+		t.Errorf("EnableTwoFactor() again = %v, want %v", err, ErrTwoFactorEnabled)
+	}
+
+	if _, err = Login("ivan", "secret123", Policy{}, db); err != ErrCodeRequired {
+		t.Fatalf("Login() = %v, want %v", err, ErrCodeRequired)
+	}
+	if err = VerifyCode("ivan", code, Policy{}, db); err != ErrInvalidCode {
+		t.Errorf("VerifyCode() with the code used on enrolment = %v, want %v", err, ErrInvalidCode)
+	}
+	if err = VerifyCode("ivan", recovery[0], Policy{}, db); err != nil {
+		t.Errorf("VerifyCode() with a recovery code = %v", err)
+	}
+	if err = VerifyCode("ivan", recovery[0], Policy{}, db); err != ErrInvalidCode {
+		t.Errorf("VerifyCode() with a used recovery code = %v, want %v", err, ErrInvalidCode)
+	}
+	if left, err := RecoveryCodesLeft("ivan", db); err != nil || left != RecoveryCodes-1 {
+		t.Errorf("RecoveryCodesLeft() = %d, %v, want %d", left, err, RecoveryCodes-1)
+	}
+
+	if err = ResetTwoFactor("ivan", 998900000000, db); !errors.Is(err, ErrIdentityMismatch) {
+		t.Errorf("ResetTwoFactor() with another phone number = %v, want %v", err, ErrIdentityMismatch)
+	}
+	if err = ResetTwoFactor("ivan", 998901234567, db); err != nil {
+		t.Fatalf("ResetTwoFactor() = %v", err)
+	}
+	if _, err = Login("ivan", "secret123", Policy{}, db); err != nil {
+		t.Errorf("Login() after the reset = %v", err)
+	}
+}
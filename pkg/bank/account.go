@@ -0,0 +1,181 @@
+package bank
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/queries"
+	"strings"
+)
+
+// Account is an account of a client with the balance in its currency.
+type Account struct {
+	Id       int64
+	Balance  money.Money
+	Currency money.Currency
+	// Nickname is the name the client gave the account, Default marks the account the
+	// transfers and payments of the client start from.
+	Nickname string
+	Default  bool
+}
+
+// AccountRecord is an account as the manager exports and imports it. The accounts of the files
+// without a Currency, as the ones core exported, are in money.DefaultCurrency.
+type AccountRecord struct {
+	Id       int64
+	ClientId int64
+	Balance  money.Money
+	Currency money.Currency
+}
+
+// GetAccounts returns the accounts of login with their currencies and nicknames.
+func GetAccounts(login string, db *sql.DB) (accounts []Account, err error) {
+	rows, err := db.Query(getClientAccountsSQL, login)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var account Account
+		var balance int64
+		var currency string
+		err = rows.Scan(&account.Id, &balance, &currency, &account.Nickname, &account.Default)
+		if err != nil {
+			return nil, err
+		}
+		account.Balance, account.Currency = money.FromKopecks(balance), orDefault(currency)
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+// DefaultAccount returns the id of the default account of login, 0 when there is none.
+func DefaultAccount(login string, db *sql.DB) (accountId int64, err error) {
+	accounts, err := GetAccounts(login, db)
+	for _, account := range accounts {
+		if account.Default {
+			return account.Id, err
+		}
+	}
+	return 0, err
+}
+
+// SetAccountNickname names accountId of login, an empty nickname removes the name.
+func SetAccountNickname(login string, accountId int64, nickname string, db *sql.DB) error {
+	nickname = strings.TrimSpace(nickname)
+	return inTx(db, func(tx *sql.Tx) error {
+		err := checkAccountOwner(tx, login, accountId)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(deleteAccountNicknameSQL, accountId)
+		if err != nil || nickname == "" {
+			return err
+		}
+		_, err = tx.Exec(addAccountNicknameSQL, accountId, nickname)
+		return err
+	})
+}
+
+// SetDefaultAccount makes accountId the default account of login.
+func SetDefaultAccount(login string, accountId int64, db *sql.DB) error {
+	return inTx(db, func(tx *sql.Tx) error {
+		err := checkAccountOwner(tx, login, accountId)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(deleteDefaultAccountSQL, login)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(addDefaultAccountSQL, login, accountId)
+		return err
+	})
+}
+
+func checkAccountOwner(tx *sql.Tx, login string, accountId int64) error {
+	var clientId, ownerId, balance int64
+	err := tx.QueryRow(queries.GetClientIdByLoginSQL, login).Scan(&clientId)
+	if err != nil {
+		return fmt.Errorf("can't find client %s: %w", login, err)
+	}
+	err = tx.QueryRow(getAccountSQL, accountId).Scan(&ownerId, &balance)
+	if err != nil {
+		return fmt.Errorf("can't find account %d: %w", accountId, err)
+	}
+	if ownerId != clientId {
+		return ErrNotOwner
+	}
+	return nil
+}
+
+// GetAccountRecords returns the accounts with their currencies sorted by id.
+func GetAccountRecords(db *sql.DB) (accounts []AccountRecord, err error) {
+	rows, err := db.Query(getAccountRecordsSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var account AccountRecord
+		var balance int64
+		var currency string
+		err = rows.Scan(&account.Id, &account.ClientId, &balance, &currency)
+		if err != nil {
+			return nil, err
+		}
+		account.Balance, account.Currency = money.FromKopecks(balance), orDefault(currency)
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+// ImportAccounts adds or replaces accounts by their ids with their currencies.
+func ImportAccounts(accounts []AccountRecord, db *sql.DB) error {
+	currencies := make([]money.Currency, len(accounts))
+	for idx, account := range accounts {
+		currencies[idx] = money.DefaultCurrency
+		if account.Currency != "" {
+			currency, err := money.ParseCurrency(string(account.Currency))
+			if err != nil {
+				return fmt.Errorf("account %d: %w: %s", account.Id, err, account.Currency)
+			}
+			currencies[idx] = currency
+		}
+		if account.Balance < 0 {
+			return fmt.Errorf("account %d: %w", account.Id, money.ErrNotPositive)
+		}
+	}
+	return inTx(db, func(tx *sql.Tx) error {
+		for idx, account := range accounts {
+			_, err := tx.Exec(
+				queries.UpdateListOfAccountsWithClientIdsSQL,
+				sql.Named("id", account.Id),
+				sql.Named("client_id", account.ClientId),
+				sql.Named("balance", account.Balance.Kopecks()),
+			)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec(deleteAccountCurrencySQL, account.Id)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec(addAccountCurrencySQL, account.Id, string(currencies[idx]))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,133 @@
+package bank
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"testing"
+)
+
+func TestAccountNickname(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	accountId := addTestClient(t, "ivan", 1, 0, db)
+	otherId := addTestClient(t, "anna", 2, 0, db)
+
+	tests := []struct {
+		login     string
+		accountId int64
+		nickname  string
+		err       error
+		want      string
+	}{
+		{"ivan", accountId, "  salary ", nil, "salary"},
+		{"ivan", accountId, "savings", nil, "savings"},
+		{"ivan", otherId, "stolen", ErrNotOwner, "savings"},
+		{"ivan", 100, "missing", sql.ErrNoRows, "savings"},
+		{"nobody", accountId, "missing", sql.ErrNoRows, "savings"},
+		{"ivan", accountId, "   ", nil, ""},
+	}
+	for _, test := range tests {
+		err := SetAccountNickname(test.login, test.accountId, test.nickname, db)
+		if !errors.Is(err, test.err) {
+			t.Errorf("SetAccountNickname(%s, %d, %q) = %v, want %v", test.login, test.accountId, test.nickname, err, test.err)
+		}
+		accounts, err := GetAccounts("ivan", db)
+		if err != nil || len(accounts) != 1 || accounts[0].Nickname != test.want {
+			t.Errorf("GetAccounts() after SetAccountNickname(%q) = %+v, %v, want the nickname %q",
+				test.nickname, accounts, err, test.want)
+		}
+	}
+	if accounts, err := GetAccounts("anna", db); err != nil || accounts[0].Nickname != "" {
+		t.Errorf("accounts of anna = %+v, %v, want no nickname", accounts, err)
+	}
+}
+
+func TestDefaultAccount(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	firstId := addTestClient(t, "ivan", 1, 0, db)
+	secondId, err := AddAccount(1, 0, money.RUB, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherId := addTestClient(t, "anna", 2, 0, db)
+
+	if accountId, err := DefaultAccount("ivan", db); err != nil || accountId != 0 {
+		t.Errorf("DefaultAccount() before one is set = %d, %v, want 0", accountId, err)
+	}
+	if err = SetDefaultAccount("ivan", otherId, db); err != ErrNotOwner {
+		t.Errorf("SetDefaultAccount() of another client = %v, want %v", err, ErrNotOwner)
+	}
+	for _, accountId := range []int64{secondId, firstId} {
+		if err = SetDefaultAccount("ivan", accountId, db); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := DefaultAccount("ivan", db); err != nil || got != accountId {
+			t.Errorf("DefaultAccount() = %d, %v, want %d", got, err, accountId)
+		}
+	}
+	accounts, err := GetAccounts("ivan", db)
+	if err != nil || len(accounts) != 2 || !accounts[0].Default || accounts[1].Default {
+		t.Errorf("GetAccounts() = %+v, %v, want only the first one default", accounts, err)
+	}
+	if accountId, err := DefaultAccount("anna", db); err != nil || accountId != 0 {
+		t.Errorf("DefaultAccount() of another client = %d, %v, want 0", accountId, err)
+	}
+}
+
+func TestAccountRecords(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	rubId := addTestClient(t, "ivan", 1, 123456, db)
+	usdId, err := AddAccount(1, 1050, money.USD, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := GetAccountRecords(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0] != (AccountRecord{rubId, 1, 123456, money.RUB}) ||
+		records[1] != (AccountRecord{usdId, 1, 1050, money.USD}) {
+		t.Fatalf("GetAccountRecords() = %+v", records)
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an account of a file exported by core, with the balance in roubles and no currency
+	data = append(data[:len(data)-1], `,{"Id":10,"ClientId":1,"Balance":12.5}]`...)
+	var imported []AccountRecord
+	if err = json.Unmarshal(data, &imported); err != nil {
+		t.Fatal(err)
+	}
+	imported[1].Balance = 2000
+	if err = ImportAccounts(imported, db); err != nil {
+		t.Fatal(err)
+	}
+	accounts, err := GetAccounts("ivan", db)
+	if err != nil || len(accounts) != 3 {
+		t.Fatalf("GetAccounts() after the import = %+v, %v", accounts, err)
+	}
+	want := []Account{
+		{Id: rubId, Balance: 123456, Currency: money.RUB},
+		{Id: usdId, Balance: 2000, Currency: money.USD},
+		{Id: 10, Balance: 1250, Currency: money.RUB},
+	}
+	for idx, account := range accounts {
+		if account != want[idx] {
+			t.Errorf("imported account = %+v, want %+v", account, want[idx])
+		}
+	}
+
+	err = ImportAccounts([]AccountRecord{{Id: 11, ClientId: 1, Currency: "EUR"}, {Id: 12, ClientId: 1}}, db)
+	if !errors.Is(err, money.ErrUnknownCurrency) {
+		t.Errorf("ImportAccounts() with an unknown currency = %v, want %v", err, money.ErrUnknownCurrency)
+	}
+	if records, err = GetAccountRecords(db); err != nil || len(records) != 3 {
+		t.Errorf("accounts after a refused import = %+v, %v, want 3", records, err)
+	}
+}
@@ -0,0 +1,323 @@
+package bank
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/queries"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The features of ATMs: taking cash in and giving out foreign currency.
+const (
+	FeatureCashIn   = "cash-in"
+	FeatureCurrency = "currency"
+)
+
+var ATMFeatures = []string{FeatureCashIn, FeatureCurrency}
+
+// HoursFormat is the format of the time ATMs open and close at.
+const HoursFormat = "15:04"
+
+// earthRadius is the mean radius of the Earth in kilometres.
+const earthRadius = 6371.0
+
+var (
+	ErrInvalidPoint   = errors.New("invalid coordinates")
+	ErrInvalidHours   = errors.New("invalid opening hours")
+	ErrInvalidFeature = errors.New("invalid ATM feature")
+)
+
+// Point is a place on the map, in degrees.
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// ATM is a core ATM with where it stands, when it works and what it can do. ATMs without
+// Opens and Closes work around the clock, the ones closing earlier than they open work overnight.
+type ATM struct {
+	Id       int64
+	Name     string
+	Location string
+	// Point is nil when the coordinates of the ATM are unknown.
+	Point    *Point   `json:",omitempty" xml:",omitempty"`
+	Opens    string   `json:",omitempty" xml:",omitempty"`
+	Closes   string   `json:",omitempty" xml:",omitempty"`
+	Features []string `json:",omitempty" xml:"Feature,omitempty"`
+}
+
+// ATMFilter selects ATMs, zero fields don't restrict it.
+type ATMFilter struct {
+	// Text is looked for in the name and the location, ignoring case.
+	Text string
+	// Feature is one of ATMFeatures.
+	Feature string
+	// OpenAt keeps the ATMs working at this time.
+	OpenAt time.Time
+	// Near sorts the ATMs by distance from it, the ones with unknown coordinates go last.
+	Near *Point
+}
+
+// Check makes sure the point is on the map.
+func (receiver Point) Check() error {
+	if math.IsNaN(receiver.Latitude) || math.IsNaN(receiver.Longitude) ||
+		math.Abs(receiver.Latitude) > 90 || math.Abs(receiver.Longitude) > 180 {
+		return fmt.Errorf("%w: %g, %g", ErrInvalidPoint, receiver.Latitude, receiver.Longitude)
+	}
+	return nil
+}
+
+// ParsePoint reads the latitude and the longitude separated by a comma or spaces, as in 41.311, 69.279.
+func ParsePoint(value string) (Point, error) {
+	parts := strings.Fields(strings.Replace(value, ",", " ", 1))
+	if len(parts) != 2 {
+		return Point{}, ErrInvalidPoint
+	}
+	var point Point
+	var err error
+	if point.Latitude, err = strconv.ParseFloat(parts[0], 64); err != nil {
+		return Point{}, ErrInvalidPoint
+	}
+	if point.Longitude, err = strconv.ParseFloat(parts[1], 64); err != nil {
+		return Point{}, ErrInvalidPoint
+	}
+	return point, point.Check()
+}
+
+func (receiver Point) String() string {
+	return strconv.FormatFloat(receiver.Latitude, 'f', -1, 64) + ", " + strconv.FormatFloat(receiver.Longitude, 'f', -1, 64)
+}
+
+// Distance returns the great-circle distance to point in kilometres.
+func (receiver Point) Distance(point Point) float64 {
+	radians := func(degrees float64) float64 {
+		return degrees * math.Pi / 180
+	}
+	latitude, pointLatitude := radians(receiver.Latitude), radians(point.Latitude)
+	sinLatitude := math.Sin((pointLatitude - latitude) / 2)
+	sinLongitude := math.Sin(radians(point.Longitude-receiver.Longitude) / 2)
+	haversine := sinLatitude*sinLatitude + math.Cos(latitude)*math.Cos(pointLatitude)*sinLongitude*sinLongitude
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(haversine)))
+}
+
+// Check makes sure the coordinates, the hours and the features of the ATM are valid.
+func (receiver ATM) Check() error {
+	if receiver.Point != nil {
+		if err := receiver.Point.Check(); err != nil {
+			return err
+		}
+	}
+	if (receiver.Opens == "") != (receiver.Closes == "") {
+		return ErrInvalidHours
+	}
+	for _, hours := range []string{receiver.Opens, receiver.Closes} {
+		if _, err := parseHours(hours); hours != "" && err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidHours, hours)
+		}
+	}
+	for _, feature := range receiver.Features {
+		if !IsATMFeature(feature) {
+			return fmt.Errorf("%w: %s", ErrInvalidFeature, feature)
+		}
+	}
+	return nil
+}
+
+// IsATMFeature tells whether feature is one of ATMFeatures.
+func IsATMFeature(feature string) bool {
+	for _, known := range ATMFeatures {
+		if feature == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Has tells whether the ATM has feature.
+func (receiver ATM) Has(feature string) bool {
+	for _, has := range receiver.Features {
+		if has == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// OpenAt tells whether the ATM works at moment, in its local time.
+func (receiver ATM) OpenAt(moment time.Time) bool {
+	opens, err := parseHours(receiver.Opens)
+	if err != nil {
+		return true
+	}
+	closes, err := parseHours(receiver.Closes)
+	if err != nil {
+		return true
+	}
+	now := moment.Hour()*60 + moment.Minute()
+	switch {
+	case opens < closes:
+		return opens <= now && now < closes
+	case opens > closes:
+		return now >= opens || now < closes
+	}
+	return true
+}
+
+// parseHours returns the minutes since midnight of a time in HoursFormat.
+func parseHours(hours string) (int, error) {
+	parsed, err := time.Parse(HoursFormat, hours)
+	if err != nil {
+		return 0, err
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// formatHours writes hours in HoursFormat, as in 09:00 for 9:00.
+func formatHours(hours string) string {
+	parsed, err := time.Parse(HoursFormat, hours)
+	if err != nil {
+		return hours
+	}
+	return parsed.Format(HoursFormat)
+}
+
+// AddATM adds atm with its details, there can be one ATM at a location.
+func AddATM(atm ATM, db *sql.DB) error {
+	if err := atm.Check(); err != nil {
+		return err
+	}
+	var location string
+	err := db.QueryRow(queries.AtmExistSQL, atm.Location).Scan(&location)
+	if err == nil {
+		return core.ErrATMExist
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return inTx(db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(
+			queries.AddAtmSQL,
+			sql.Named("name", atm.Name),
+			sql.Named("location", atm.Location),
+		)
+		if err != nil {
+			return err
+		}
+		err = tx.QueryRow(getAtmIdSQL, atm.Location).Scan(&atm.Id)
+		if err != nil {
+			return err
+		}
+		return setAtmDetails(tx, atm)
+	})
+}
+
+// ImportATMs adds or replaces atms by their ids with their details.
+func ImportATMs(atms []ATM, db *sql.DB) error {
+	for _, atm := range atms {
+		if err := atm.Check(); err != nil {
+			return fmt.Errorf("ATM %d: %w", atm.Id, err)
+		}
+	}
+	return inTx(db, func(tx *sql.Tx) error {
+		for _, atm := range atms {
+			_, err := tx.Exec(
+				queries.UpdateListOfATMsSQL,
+				sql.Named("id", atm.Id),
+				sql.Named("name", atm.Name),
+				sql.Named("location", atm.Location),
+			)
+			if err != nil {
+				return err
+			}
+			if err = setAtmDetails(tx, atm); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func setAtmDetails(tx *sql.Tx, atm ATM) error {
+	var latitude, longitude sql.NullFloat64
+	if atm.Point != nil {
+		latitude = sql.NullFloat64{Float64: atm.Point.Latitude, Valid: true}
+		longitude = sql.NullFloat64{Float64: atm.Point.Longitude, Valid: true}
+	}
+	_, err := tx.Exec(
+		setAtmDetailsSQL,
+		sql.Named("atm_id", atm.Id),
+		sql.Named("latitude", latitude),
+		sql.Named("longitude", longitude),
+		sql.Named("opens", formatHours(atm.Opens)),
+		sql.Named("closes", formatHours(atm.Closes)),
+		sql.Named("features", strings.Join(atm.Features, ",")),
+	)
+	return err
+}
+
+// GetATMs returns the ATMs with their details sorted by id.
+func GetATMs(db *sql.DB) (atms []ATM, err error) {
+	rows, err := db.Query(getATMsSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var atm ATM
+		var latitude, longitude sql.NullFloat64
+		var features string
+		err = rows.Scan(&atm.Id, &atm.Name, &atm.Location, &latitude, &longitude, &atm.Opens, &atm.Closes, &features)
+		if err != nil {
+			return nil, err
+		}
+		if latitude.Valid && longitude.Valid {
+			atm.Point = &Point{Latitude: latitude.Float64, Longitude: longitude.Float64}
+		}
+		if features != "" {
+			atm.Features = strings.Split(features, ",")
+		}
+		atms = append(atms, atm)
+	}
+	return atms, rows.Err()
+}
+
+// FindATMs returns the ATMs selected by filter, sorted by distance when filter.Near is set
+// and by id otherwise.
+func FindATMs(filter ATMFilter, db *sql.DB) ([]ATM, error) {
+	atms, err := GetATMs(db)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.ToLower(strings.TrimSpace(filter.Text))
+	found := make([]ATM, 0, len(atms))
+	for _, atm := range atms {
+		switch {
+		case text != "" && !strings.Contains(strings.ToLower(atm.Name+" "+atm.Location), text):
+		case filter.Feature != "" && !atm.Has(filter.Feature):
+		case !filter.OpenAt.IsZero() && !atm.OpenAt(filter.OpenAt):
+		default:
+			found = append(found, atm)
+		}
+	}
+	if filter.Near != nil {
+		near := *filter.Near
+		sort.SliceStable(found, func(i, j int) bool {
+			if found[i].Point == nil || found[j].Point == nil {
+				return found[j].Point == nil && found[i].Point != nil
+			}
+			return found[i].Point.Distance(near) < found[j].Point.Distance(near)
+		})
+	}
+	return found, nil
+}
@@ -0,0 +1,194 @@
+package bank
+
+import (
+	"errors"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePoint(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Point
+		ok    bool
+	}{
+		{"41.311, 69.279", Point{41.311, 69.279}, true},
+		{"41.311 69.279", Point{41.311, 69.279}, true},
+		{" -33.9,18.4 ", Point{-33.9, 18.4}, true},
+		{"90, -180", Point{90, -180}, true},
+		{"91, 0", Point{}, false},
+		{"0, 180.5", Point{}, false},
+		{"NaN, 0", Point{}, false},
+		{"41.311", Point{}, false},
+		{"41.311, 69.279, 0", Point{}, false},
+		{"north, east", Point{}, false},
+	}
+	for _, test := range tests {
+		point, err := ParsePoint(test.value)
+		if (err == nil) != test.ok || (err != nil && !errors.Is(err, ErrInvalidPoint)) || (test.ok && point != test.want) {
+			t.Errorf("ParsePoint(%q) = %v, %v, want %v, ok %t", test.value, point, err, test.want, test.ok)
+		}
+	}
+	if got := (Point{41.311, -69.5}).String(); got != "41.311, -69.5" {
+		t.Errorf("Point.String() = %q", got)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		from, to Point
+		want     float64
+	}{
+		{Point{41.311, 69.279}, Point{41.311, 69.279}, 0},
+		{Point{0, 0}, Point{1, 0}, earthRadius * math.Pi / 180},
+		{Point{0, 179.5}, Point{0, -179.5}, earthRadius * math.Pi / 180},
+		{Point{90, 0}, Point{-90, 0}, earthRadius * math.Pi},
+		{Point{0, 0}, Point{0, 180}, earthRadius * math.Pi},
+	}
+	for _, test := range tests {
+		if got := test.from.Distance(test.to); math.Abs(got-test.want) > 1e-6 {
+			t.Errorf("%v Distance(%v) = %f, want %f", test.from, test.to, got, test.want)
+		}
+		if there, back := test.from.Distance(test.to), test.to.Distance(test.from); math.Abs(there-back) > 1e-9 {
+			t.Errorf("distances between %v and %v differ: %f and %f", test.from, test.to, there, back)
+		}
+	}
+}
+
+func TestATMCheck(t *testing.T) {
+	tests := []struct {
+		atm ATM
+		err error
+	}{
+		{ATM{}, nil},
+		{ATM{Point: &Point{41.3, 69.2}, Opens: "9:00", Closes: "18:00", Features: []string{FeatureCashIn}}, nil},
+		{ATM{Point: &Point{100, 69.2}}, ErrInvalidPoint},
+		{ATM{Opens: "09:00"}, ErrInvalidHours},
+		{ATM{Closes: "18:00"}, ErrInvalidHours},
+		{ATM{Opens: "09:00", Closes: "24:00"}, ErrInvalidHours},
+		{ATM{Opens: "nine", Closes: "18:00"}, ErrInvalidHours},
+		{ATM{Features: []string{FeatureCurrency, "coffee"}}, ErrInvalidFeature},
+	}
+	for _, test := range tests {
+		if err := test.atm.Check(); !errors.Is(err, test.err) {
+			t.Errorf("%+v Check() = %v, want %v", test.atm, err, test.err)
+		}
+	}
+}
+
+func TestOpenAt(t *testing.T) {
+	at := func(hours string) time.Time {
+		parsed, err := time.Parse(HoursFormat, hours)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	tests := []struct {
+		opens, closes string
+		at            string
+		want          bool
+	}{
+		{"", "", "03:00", true},
+		{"09:00", "18:00", "08:59", false},
+		{"09:00", "18:00", "09:00", true},
+		{"09:00", "18:00", "17:59", true},
+		{"09:00", "18:00", "18:00", false},
+		{"22:00", "06:00", "23:30", true},
+		{"22:00", "06:00", "05:59", true},
+		{"22:00", "06:00", "06:00", false},
+		{"22:00", "06:00", "12:00", false},
+		{"00:00", "00:00", "12:00", true},
+	}
+	for _, test := range tests {
+		atm := ATM{Opens: test.opens, Closes: test.closes}
+		if got := atm.OpenAt(at(test.at)); got != test.want {
+			t.Errorf("ATM open %s to %s OpenAt(%s) = %t, want %t", test.opens, test.closes, test.at, got, test.want)
+		}
+	}
+}
+
+func TestFormatHours(t *testing.T) {
+	tests := []struct {
+		hours string
+		want  string
+	}{
+		{"9:00", "09:00"},
+		{"09:05", "09:05"},
+		{"23:59", "23:59"},
+		{"", ""},
+		{"late", "late"},
+	}
+	for _, test := range tests {
+		if got := formatHours(test.hours); got != test.want {
+			t.Errorf("formatHours(%q) = %q, want %q", test.hours, got, test.want)
+		}
+	}
+}
+
+func TestFindATMs(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	for _, atm := range []ATM{
+		{Name: "Chorsu", Location: "Chorsu bazaar", Point: &Point{41.326, 69.235}, Opens: "8:00", Closes: "20:00"},
+		{Name: "Airport", Location: "Tashkent airport", Point: &Point{41.258, 69.281}, Features: []string{FeatureCurrency}},
+		{Name: "Mall", Location: "Mega Planet", Opens: "10:00", Closes: "22:00", Features: []string{FeatureCashIn, FeatureCurrency}},
+		{Name: "Center", Location: "Amir Temur square", Point: &Point{41.311, 69.279}, Opens: "22:00", Closes: "06:00"},
+	} {
+		if err := AddATM(atm, db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := AddATM(ATM{Name: "Copy", Location: "Mega Planet"}, db); err != core.ErrATMExist {
+		t.Errorf("AddATM() at a taken location = %v, want %v", err, core.ErrATMExist)
+	}
+	if err := AddATM(ATM{Name: "Broken", Location: "Nowhere", Opens: "10:00"}, db); err != ErrInvalidHours {
+		t.Errorf("AddATM() with no closing time = %v, want %v", err, ErrInvalidHours)
+	}
+
+	night, err := time.Parse(HoursFormat, "23:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		filter ATMFilter
+		want   string
+	}{
+		{"all", ATMFilter{}, "Chorsu Airport Mall Center"},
+		{"text in the location", ATMFilter{Text: " AIRPORT "}, "Airport"},
+		{"text in the name", ATMFilter{Text: "mall"}, "Mall"},
+		{"feature", ATMFilter{Feature: FeatureCurrency}, "Airport Mall"},
+		{"open at night", ATMFilter{OpenAt: night}, "Airport Center"},
+		{"near", ATMFilter{Near: &Point{41.31, 69.28}}, "Center Chorsu Airport Mall"},
+		{"near with a feature", ATMFilter{Feature: FeatureCurrency, Near: &Point{41.31, 69.28}}, "Airport Mall"},
+		{"nothing", ATMFilter{Text: "samarkand"}, ""},
+	}
+	for _, test := range tests {
+		atms, err := FindATMs(test.filter, db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, atm := range atms {
+			names = append(names, atm.Name)
+		}
+		if got := strings.Join(names, " "); got != test.want {
+			t.Errorf("%s: FindATMs() = %q, want %q", test.name, got, test.want)
+		}
+	}
+
+	atms, err := GetATMs(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chorsu := atms[0]; chorsu.Opens != "08:00" || chorsu.Point == nil || *chorsu.Point != (Point{41.326, 69.235}) {
+		t.Errorf("ATM read back = %+v", chorsu)
+	}
+	if mall := atms[2]; mall.Point != nil || len(mall.Features) != 2 {
+		t.Errorf("ATM without coordinates read back = %+v", mall)
+	}
+}
@@ -0,0 +1,255 @@
+package bank
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/queries"
+	"time"
+)
+
+// The cli keeps its own ledger of the accounts instead of the core journal. Money movements of
+// apm-ibank-core take float64 roubles and multiply them by 100, which can't express every kopeck
+// amount, and the core journal keeps neither the account of an operation nor its balance. The
+// functions here work like the core ones and run the same queries, but keep amounts in kopecks
+// and record the operations in account_operations only. Init moves the rows of the core journal
+// there once, so statements and the journal read a single table.
+
+// Init creates the tables kept by the cli next to the core ones and moves the new rows of the
+// core journal to account_operations.
+func Init(db *sql.DB) (err error) {
+	ddls := []string{payeesDDL, scheduledTransfersDDL, scheduledRunsDDL, accountOperationsDDL, accountCurrenciesDDL,
+		exchangeRatesDDL, accountExchangesDDL, accountNicknamesDDL, defaultAccountsDDL, serviceCategoriesDDL, serviceFieldsDDL,
+		operationDetailsDDL, atmDetailsDDL}
+	for _, ddl := range ddls {
+		_, err = db.Exec(ddl)
+		if err != nil {
+			return err
+		}
+	}
+	return moveJournal(db)
+}
+
+// moveJournal copies the rows of the core journal after the last one moved before to
+// account_operations. Payments and transfers are debited from the first account of the client,
+// as the journal doesn't tell the account, and transfers are credited to the account they were
+// sent to. The balances of the copied operations are counted back from the current ones.
+func moveJournal(db *sql.DB) error {
+	return inTx(db, func(tx *sql.Tx) error {
+		var lastJournalId, lastOperationId int64
+		err := tx.QueryRow(lastMovedJournalIdSQL).Scan(&lastJournalId)
+		if err != nil {
+			return err
+		}
+		err = tx.QueryRow(lastOperationIdSQL).Scan(&lastOperationId)
+		if err != nil {
+			return err
+		}
+		for _, query := range []string{moveJournalDebitsSQL, moveJournalCreditsSQL} {
+			_, err = tx.Exec(query, lastJournalId)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = tx.Exec(setMovedBalancesSQL, lastOperationId)
+		return err
+	})
+}
+
+var (
+	ErrNotOwner          = errors.New("account does not belong to client")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+)
+
+// AddAccount opens an account in currency for the client with phoneNumber and returns its id.
+func AddAccount(phoneNumber int64, balance money.Money, currency money.Currency, db *sql.DB) (accountId int64, err error) {
+	err = inTx(db, func(tx *sql.Tx) error {
+		var clientId int64
+		err := tx.QueryRow(queries.GetClientIdByPhoneNumberSQL, phoneNumber).Scan(&clientId)
+		if err != nil {
+			return fmt.Errorf("can't find client %d: %w", phoneNumber, err)
+		}
+		_, err = tx.Exec(
+			queries.AddAccountSQL,
+			sql.Named("client_id", clientId),
+			sql.Named("balance", balance.Kopecks()),
+		)
+		if err != nil {
+			return err
+		}
+		err = tx.QueryRow(lastClientAccountIdSQL, clientId).Scan(&accountId)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(addAccountCurrencySQL, accountId, string(currency))
+		if err != nil || balance == 0 {
+			return err
+		}
+		return addOperation(tx, accountId, time.Now(), Deposit, "", balance)
+	})
+	return accountId, err
+}
+
+// PayForService debits amount from accountId of login and keeps details with the payment, they
+// must have a valid value for every field of the service.
+func PayForService(nameOfService string, details []PaymentDetail, accountId int64, login string, amount money.Money, db *sql.DB) (err error) {
+	var serviceId int64
+	err = db.QueryRow(getServiceIdSQL, nameOfService).Scan(&serviceId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.ErrServiceNotExist
+	}
+	if err != nil {
+		return err
+	}
+	return inTx(db, func(tx *sql.Tx) error {
+		fields, err := serviceFields(tx, serviceId)
+		if err != nil {
+			return err
+		}
+		details, err = fillFields(fields, details)
+		if err != nil {
+			return err
+		}
+		err = move(tx, login, accountId, 0, amount, core.Service, nameOfService)
+		if err != nil {
+			return err
+		}
+		return addDetails(tx, accountId, details)
+	})
+}
+
+func TransferToByAccountId(targetAccountId int64, login string, accountId int64, amount money.Money, db *sql.DB) (err error) {
+	return inTx(db, func(tx *sql.Tx) error {
+		return transfer(tx, targetAccountId, 0, login, accountId, amount)
+	})
+}
+
+func TransferToByPhoneNumber(phoneNumber int64, login string, accountId int64, amount money.Money, db *sql.DB) (err error) {
+	return inTx(db, func(tx *sql.Tx) error {
+		return transfer(tx, 0, phoneNumber, login, accountId, amount)
+	})
+}
+
+// transfer sends amount to targetAccountId or, when it is 0, to the first account of phoneNumber.
+func transfer(tx *sql.Tx, targetAccountId, phoneNumber int64, login string, accountId int64, amount money.Money) (err error) {
+	targetAccountId, transferredTo, err := findTarget(tx, targetAccountId, phoneNumber)
+	if err != nil {
+		return err
+	}
+	return move(tx, login, accountId, targetAccountId, amount, core.Transfer, transferredTo)
+}
+
+// findTarget returns the account a transfer to targetAccountId or phoneNumber credits and
+// the counterparty of the transfer, the target client must not be locked.
+func findTarget(tx *sql.Tx, targetAccountId, phoneNumber int64) (accountId int64, transferredTo string, err error) {
+	var status string
+	if targetAccountId != 0 {
+		var targetClientId int64
+		err = tx.QueryRow(queries.GetClientIdByAccountSQL, targetAccountId).Scan(&targetClientId)
+		if err != nil {
+			return 0, "", fmt.Errorf("can't find account %d: %w", targetAccountId, err)
+		}
+		err = tx.QueryRow(queries.GetClientStatusSQL, targetClientId).Scan(&status)
+		transferredTo = fmt.Sprint(targetAccountId)
+	} else {
+		err = tx.QueryRow(queries.GetClientStatusByPhoneNumberSQL, phoneNumber).Scan(&status)
+		if err != nil {
+			return 0, "", fmt.Errorf("can't find client %d: %w", phoneNumber, err)
+		}
+		var targetClientId int64
+		err = tx.QueryRow(queries.GetClientIdByPhoneNumberSQL, phoneNumber).Scan(&targetClientId)
+		if err == nil {
+			err = tx.QueryRow(queries.GetClientAccountIdSQL, targetClientId).Scan(&targetAccountId)
+		}
+		if err != nil {
+			return 0, "", fmt.Errorf("client %d has no accounts: %w", phoneNumber, err)
+		}
+		transferredTo = fmt.Sprint(phoneNumber)
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	if status == core.Locked {
+		return 0, "", core.ErrClientIsLocked
+	}
+	return targetAccountId, transferredTo, nil
+}
+
+// move debits accountId of login, credits targetAccountId unless it is 0 and records the operations.
+// A target account in another currency is credited the amount converted at the current rates.
+func move(tx *sql.Tx, login string, accountId, targetAccountId int64, amount money.Money, operation, transferredTo string) (err error) {
+	var clientId, ownerId, balance int64
+	err = tx.QueryRow(queries.GetClientIdByLoginSQL, login).Scan(&clientId)
+	if err != nil {
+		return fmt.Errorf("can't find client %s: %w", login, err)
+	}
+	err = tx.QueryRow(getAccountSQL, accountId).Scan(&ownerId, &balance)
+	if err != nil {
+		return fmt.Errorf("can't find account %d: %w", accountId, err)
+	}
+	if ownerId != clientId {
+		return ErrNotOwner
+	}
+	if balance < amount.Kopecks() {
+		return ErrInsufficientFunds
+	}
+	now := time.Now()
+	_, err = tx.Exec(
+		queries.UpdateClientBalanceSQL,
+		sql.Named("id", accountId),
+		sql.Named("amount", -amount.Kopecks()),
+	)
+	if err != nil {
+		return err
+	}
+	err = addOperation(tx, accountId, now, operation, transferredTo, -amount)
+	if err != nil {
+		return err
+	}
+	if targetAccountId != 0 {
+		return credit(tx, accountId, targetAccountId, now, amount, operation)
+	}
+	return nil
+}
+
+func credit(tx *sql.Tx, accountId, targetAccountId int64, now time.Time, amount money.Money, operation string) error {
+	conversion, err := convert(tx, accountId, targetAccountId, amount)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		queries.UpdateClientBalanceSQL,
+		sql.Named("id", targetAccountId),
+		sql.Named("amount", conversion.Converted.Kopecks()),
+	)
+	if err != nil {
+		return err
+	}
+	err = addOperation(tx, targetAccountId, now, operation, fmt.Sprint(accountId), conversion.Converted)
+	if err != nil || !conversion.Converts() {
+		return err
+	}
+	err = addExchange(tx, accountId, Exchange{Currency: conversion.To, Amount: conversion.Converted, Rate: conversion.Rate})
+	if err != nil {
+		return err
+	}
+	return addExchange(tx, targetAccountId, Exchange{Currency: conversion.From, Amount: amount, Rate: conversion.Rate})
+}
+
+// inTx runs do in a transaction, committed when do returns no error.
+func inTx(db *sql.DB, do func(tx *sql.Tx) error) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+	return do(tx)
+}
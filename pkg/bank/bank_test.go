@@ -0,0 +1,133 @@
+package bank
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	_ "github.com/mattn/go-sqlite3"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// openTestDB returns a new database with the core and the cli tables, cleanup removes it.
+func openTestDB(t *testing.T) (db *sql.DB, cleanup func()) {
+	dir, err := ioutil.TempDir("", "bank")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err = sql.Open("sqlite3", filepath.Join(dir, "test.sqlite"))
+	if err == nil {
+		err = core.Init(db)
+	}
+	if err == nil {
+		err = Init(db)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// addTestClient adds a client with an account in roubles holding balance kopecks.
+func addTestClient(t *testing.T, login string, phoneNumber int64, balance money.Money, db *sql.DB) (accountId int64) {
+	err := core.AddClient(login, login, "secret", phoneNumber, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountId, err = AddAccount(phoneNumber, balance, money.RUB, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return accountId
+}
+
+// balances returns the balances of the accounts of login in the order they were opened.
+func balances(t *testing.T, login string, db *sql.DB) (balances []money.Money) {
+	rows, err := db.Query(`SELECT a.balance
+FROM accounts a
+         JOIN clients c ON c.id = a.client_id
+WHERE c.login = ?
+ORDER BY a.id`, login)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var balance money.Money
+		if err = rows.Scan(&balance); err != nil {
+			t.Fatal(err)
+		}
+		balances = append(balances, balance)
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return balances
+}
+
+func TestTransfer(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	fromId := addTestClient(t, "payer", 1, 10000, db)
+	toId := addTestClient(t, "payee", 2, 0, db)
+	lockedId := addTestClient(t, "locked", 3, 0, db)
+	_, err := db.Exec(`UPDATE clients SET status = ? WHERE login = 'locked'`, core.Locked)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		target      int64
+		phoneNumber int64
+		login       string
+		accountId   int64
+		amount      money.Money
+		err         error
+		payer       money.Money
+		payee       money.Money
+	}{
+		{"by account", toId, 0, "payer", fromId, 1250, nil, 8750, 1250},
+		{"by phone number", 0, 2, "payer", fromId, 1, nil, 8749, 1251},
+		{"whole balance", toId, 0, "payer", fromId, 8749, nil, 0, 10000},
+		{"more than the balance", toId, 0, "payer", fromId, 1, ErrInsufficientFunds, 0, 10000},
+		{"account of another client", fromId, 0, "payer", toId, 100, ErrNotOwner, 0, 10000},
+		{"locked client by account", lockedId, 0, "payee", toId, 100, core.ErrClientIsLocked, 0, 10000},
+		{"locked client by phone number", 0, 3, "payee", toId, 100, core.ErrClientIsLocked, 0, 10000},
+		{"unknown account", 100, 0, "payee", toId, 100, sql.ErrNoRows, 0, 10000},
+		{"unknown phone number", 0, 4, "payee", toId, 100, sql.ErrNoRows, 0, 10000},
+	}
+	for _, test := range tests {
+		if test.target != 0 {
+			err = TransferToByAccountId(test.target, test.login, test.accountId, test.amount, db)
+		} else {
+			err = TransferToByPhoneNumber(test.phoneNumber, test.login, test.accountId, test.amount, db)
+		}
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: transfer of %s = %v, want %v", test.name, test.amount, err, test.err)
+		}
+		payer, payee := balances(t, "payer", db), balances(t, "payee", db)
+		if payer[0] != test.payer || payee[0] != test.payee {
+			t.Errorf("%s: balances after the transfer = %s and %s, want %s and %s",
+				test.name, payer[0], payee[0], test.payer, test.payee)
+		}
+	}
+	if locked := balances(t, "locked", db); locked[0] != 0 {
+		t.Errorf("balance of the locked client = %s, want 0.00", locked[0])
+	}
+
+	statement, err := GetStatement(toId, date("2000-01-01"), date("2100-01-01"), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statement.Operations) != 3 {
+		t.Errorf("operations of the payee = %+v, want the 3 transfers", statement.Operations)
+	}
+}
@@ -0,0 +1,197 @@
+package bank
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/queries"
+	"time"
+)
+
+var ErrUnknownColumn = errors.New("unknown column")
+
+// ClientColumns are the columns GetClients sorts by.
+var ClientColumns = []string{"name", "login", "phone", "status"}
+
+var clientColumns = map[string]string{
+	"name":   "name",
+	"login":  "login",
+	"phone":  "phone_number",
+	"status": "status",
+}
+
+// GetClients returns a page of the clients sorted by one of ClientColumns and the number of all of them.
+func GetClients(column string, desc bool, limit, offset int64, db *sql.DB) (clients []core.Client, total int64, err error) {
+	order, err := orderBy(clientColumns, column, desc, "id")
+	if err != nil {
+		return nil, 0, err
+	}
+	err = inTx(db, func(tx *sql.Tx) error {
+		err := tx.QueryRow(countClientsSQL).Scan(&total)
+		if err != nil {
+			return err
+		}
+		clients, err = queryClients(tx, fmt.Sprintf(getClientsSQL, order), limit, offset)
+		return err
+	})
+	return clients, total, err
+}
+
+// The searches below stand in for the core ones, which read the password into core.Client and
+// fail on the keys the passwords are stored as.
+
+func SearchClientsByName(name string, db *sql.DB) (clients []core.Client, err error) {
+	err = inTx(db, func(tx *sql.Tx) error {
+		clients, err = queryClients(tx, searchClientsByNameSQL, "%"+name+"%")
+		return err
+	})
+	return clients, err
+}
+
+func SearchClientsByPhoneNumber(phoneNumber int64, db *sql.DB) (clients []core.Client, err error) {
+	err = inTx(db, func(tx *sql.Tx) error {
+		clients, err = queryClients(tx, searchClientsByPhoneNumberSQL, phoneNumber)
+		return err
+	})
+	return clients, err
+}
+
+// GetClientByPhoneNumber returns the client with phoneNumber.
+func GetClientByPhoneNumber(phoneNumber int64, db *sql.DB) (client core.Client, err error) {
+	clients, err := SearchClientsByPhoneNumber(phoneNumber, db)
+	if err != nil {
+		return client, err
+	}
+	if len(clients) == 0 {
+		return client, core.ErrPhoneNumberNotExist
+	}
+	return clients[0], nil
+}
+
+// ClientRecord is a client as the manager exports and imports it. Password is what
+// clients.password keeps: the key of the password for the clients with a Salt and the
+// password itself for the ones without.
+type ClientRecord struct {
+	Id          int64
+	Name        string
+	Login       string
+	Password    StoredPassword
+	Salt        string `json:",omitempty" xml:",omitempty"`
+	PhoneNumber int64
+	Status      string
+}
+
+// StoredPassword is a stored password that can also be read from a JSON number, as core exported passwords.
+type StoredPassword string
+
+func (receiver *StoredPassword) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var password string
+		if err := json.Unmarshal(data, &password); err != nil {
+			return err
+		}
+		*receiver = StoredPassword(password)
+		return nil
+	}
+	if string(data) != "null" {
+		*receiver = StoredPassword(data)
+	}
+	return nil
+}
+
+// GetClientRecords returns the clients with their stored passwords and salts sorted by id.
+func GetClientRecords(db *sql.DB) (clients []ClientRecord, err error) {
+	rows, err := db.Query(getClientRecordsSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var client ClientRecord
+		err = rows.Scan(&client.Id, &client.Name, &client.Login, &client.Password, &client.Salt, &client.PhoneNumber, &client.Status)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+	return clients, rows.Err()
+}
+
+// ImportClients adds or replaces clients by their logins with the salts of their passwords, the
+// sessions of the imported clients are revoked.
+func ImportClients(clients []ClientRecord, db *sql.DB) error {
+	return inTx(db, func(tx *sql.Tx) error {
+		for _, client := range clients {
+			_, err := tx.Exec(
+				queries.UpdateListOfClientsSQL,
+				sql.Named("id", client.Id),
+				sql.Named("name", client.Name),
+				sql.Named("login", client.Login),
+				sql.Named("password", string(client.Password)),
+				sql.Named("phone_number", client.PhoneNumber),
+				sql.Named("status", client.Status),
+			)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec(deleteClientSaltSQL, client.Login)
+			if err != nil {
+				return err
+			}
+			if client.Salt == "" {
+				continue
+			}
+			_, err = tx.Exec(
+				addClientSaltSQL,
+				sql.Named("login", client.Login),
+				sql.Named("salt", client.Salt),
+				sql.Named("revoked_before", time.Now().UnixNano()),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func queryClients(tx *sql.Tx, query string, args ...interface{}) (clients []core.Client, err error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var client core.Client
+		err = rows.Scan(&client.Id, &client.Name, &client.Login, &client.PhoneNumber, &client.Status)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+	return clients, rows.Err()
+}
+
+// orderBy makes the ORDER BY list for column, ending it with tie so pages don't overlap.
+func orderBy(columns map[string]string, column string, desc bool, tie string) (string, error) {
+	expression, ok := columns[column]
+	if !ok {
+		return "", ErrUnknownColumn
+	}
+	if desc {
+		return expression + " DESC, " + tie + " DESC", nil
+	}
+	return expression + ", " + tie, nil
+}
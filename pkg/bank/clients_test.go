@@ -0,0 +1,105 @@
+package bank
+
+import (
+	"encoding/json"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/auth"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"strings"
+	"testing"
+)
+
+func TestOrderBy(t *testing.T) {
+	tests := []struct {
+		column string
+		desc   bool
+		want   string
+		err    error
+	}{
+		{"name", false, "name, id", nil},
+		{"phone", true, "phone_number DESC, id DESC", nil},
+		{"id; DROP TABLE clients", false, "", ErrUnknownColumn},
+		{"", false, "", ErrUnknownColumn},
+	}
+	for _, test := range tests {
+		got, err := orderBy(clientColumns, test.column, test.desc, "id")
+		if got != test.want || err != test.err {
+			t.Errorf("orderBy(%q, %t) = %q, %v, want %q, %v", test.column, test.desc, got, err, test.want, test.err)
+		}
+	}
+}
+
+func TestGetClients(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	for _, client := range []struct {
+		name, login string
+		phoneNumber int64
+	}{
+		{"Boris", "boris", 3},
+		{"Anna", "anna", 2},
+		{"Anna", "anya", 1},
+		{"Viktor", "viktor", 4},
+	} {
+		if err := core.AddClient(client.name, client.login, "secret", client.phoneNumber, db); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		column        string
+		desc          bool
+		limit, offset int64
+		want          string
+	}{
+		{"name", false, 10, 0, "anna anya boris viktor"},
+		{"name", true, 10, 0, "viktor boris anya anna"},
+		{"phone", false, 2, 0, "anya anna"},
+		{"phone", false, 2, 2, "boris viktor"},
+		{"login", true, 3, 3, "anna"},
+		{"login", false, 2, 4, ""},
+	}
+	for _, test := range tests {
+		clients, total, err := GetClients(test.column, test.desc, test.limit, test.offset, db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var logins []string
+		for _, client := range clients {
+			logins = append(logins, client.Login)
+		}
+		if got := strings.Join(logins, " "); got != test.want || total != 4 {
+			t.Errorf("GetClients(%q, %t, %d, %d) = %q of %d, want %q of 4",
+				test.column, test.desc, test.limit, test.offset, got, total, test.want)
+		}
+	}
+	if _, _, err := GetClients("password", false, 10, 0, db); err != ErrUnknownColumn {
+		t.Errorf("GetClients() by the password = %v, want %v", err, ErrUnknownColumn)
+	}
+}
+
+func TestImportClients(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	if err := auth.Init(db); err != nil {
+		t.Fatal(err)
+	}
+	clients := []ClientRecord{
+		{Id: 1, Name: "Anna", Login: "anna", Password: "secret", PhoneNumber: 1, Status: core.Active},
+		{Id: 2, Name: "Boris", Login: "boris", Password: "scrypt:key", Salt: "salt", PhoneNumber: 2, Status: core.Locked},
+	}
+	if err := ImportClients(clients, db); err != nil {
+		t.Fatal(err)
+	}
+	records, err := GetClientRecords(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0] != clients[0] || records[1] != clients[1] {
+		t.Errorf("GetClientRecords() after the import = %+v, want %+v", records, clients)
+	}
+
+	var password StoredPassword
+	if err = json.Unmarshal([]byte("123456"), &password); err != nil || password != "123456" {
+		t.Errorf("password exported by core read as %q, %v", password, err)
+	}
+}
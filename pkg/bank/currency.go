@@ -0,0 +1,179 @@
+package bank
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"time"
+)
+
+// RateDateFormat is the format of the dates the exchange rates were loaded on.
+const RateDateFormat = "2006-01-02 15:04:05"
+
+var ErrNoRate = errors.New("no exchange rate")
+
+// ExchangeRate is the price of a unit of Currency in money.BaseCurrency.
+type ExchangeRate struct {
+	Currency money.Currency
+	Rate     money.Rate
+	Date     string
+}
+
+// Exchange is the other side of an account operation of a transfer between currencies.
+type Exchange struct {
+	Currency money.Currency
+	Amount   money.Money
+	// Rate is the price of a unit of the debited currency in the credited one.
+	Rate money.Rate
+}
+
+// Conversion is what a transfer of Amount in From credits to an account in To.
+type Conversion struct {
+	From      money.Currency
+	To        money.Currency
+	Amount    money.Money
+	Converted money.Money
+	// Rate is the price of a unit of From in To.
+	Rate money.Rate
+}
+
+func (receiver Conversion) Converts() bool {
+	return receiver.From != receiver.To
+}
+
+// orDefault reads the currency of an account, accounts without one are in money.DefaultCurrency.
+func orDefault(currency string) money.Currency {
+	if currency == "" {
+		return money.DefaultCurrency
+	}
+	return money.Currency(currency)
+}
+
+func accountCurrency(tx *sql.Tx, accountId int64) (money.Currency, error) {
+	var currency string
+	err := tx.QueryRow(getAccountCurrencySQL, accountId).Scan(&currency)
+	return orDefault(currency), err
+}
+
+// SetRates replaces the rates of the currencies in rates and leaves the others as they are.
+func SetRates(rates []ExchangeRate, db *sql.DB) error {
+	date := time.Now().Format(RateDateFormat)
+	for _, rate := range rates {
+		if _, err := money.ParseCurrency(string(rate.Currency)); err != nil {
+			return fmt.Errorf("%w: %s", err, rate.Currency)
+		}
+		if rate.Currency == money.BaseCurrency || rate.Rate <= 0 {
+			return fmt.Errorf("%w of %s", money.ErrInvalidRate, rate.Currency)
+		}
+	}
+	return inTx(db, func(tx *sql.Tx) error {
+		for _, rate := range rates {
+			_, err := tx.Exec(deleteExchangeRateSQL, string(rate.Currency))
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec(
+				addExchangeRateSQL,
+				sql.Named("currency", string(rate.Currency)),
+				sql.Named("rate", int64(rate.Rate)),
+				sql.Named("date", date),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func GetRates(db *sql.DB) (rates []ExchangeRate, err error) {
+	rows, err := db.Query(getExchangeRatesSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var rate ExchangeRate
+		var currency string
+		var value int64
+		err = rows.Scan(&currency, &value, &rate.Date)
+		if err != nil {
+			return nil, err
+		}
+		rate.Currency, rate.Rate = money.Currency(currency), money.Rate(value)
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func rate(tx *sql.Tx, currency money.Currency) (money.Rate, error) {
+	if currency == money.BaseCurrency {
+		return money.RateOne, nil
+	}
+	var value int64
+	err := tx.QueryRow(getExchangeRateSQL, string(currency)).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w for %s", ErrNoRate, currency)
+	}
+	return money.Rate(value), err
+}
+
+// QuoteTransfer returns what a transfer of amount from accountId to targetAccountId or, when
+// it is 0, to the client with phoneNumber would credit at the current rates.
+func QuoteTransfer(targetAccountId, phoneNumber, accountId int64, amount money.Money, db *sql.DB) (conversion Conversion, err error) {
+	err = inTx(db, func(tx *sql.Tx) error {
+		targetAccountId, _, err := findTarget(tx, targetAccountId, phoneNumber)
+		if err != nil {
+			return err
+		}
+		conversion, err = convert(tx, accountId, targetAccountId, amount)
+		return err
+	})
+	return conversion, err
+}
+
+func convert(tx *sql.Tx, accountId, targetAccountId int64, amount money.Money) (conversion Conversion, err error) {
+	conversion = Conversion{Amount: amount, Converted: amount, Rate: money.RateOne}
+	conversion.From, err = accountCurrency(tx, accountId)
+	if err != nil {
+		return conversion, err
+	}
+	conversion.To, err = accountCurrency(tx, targetAccountId)
+	if err != nil || !conversion.Converts() {
+		return conversion, err
+	}
+	from, err := rate(tx, conversion.From)
+	if err != nil {
+		return conversion, err
+	}
+	to, err := rate(tx, conversion.To)
+	if err != nil {
+		return conversion, err
+	}
+	conversion.Converted = money.Convert(amount, from, to)
+	conversion.Rate = money.Cross(from, to)
+	return conversion, nil
+}
+
+// addExchange records the other side of the last operation of accountId.
+func addExchange(tx *sql.Tx, accountId int64, exchange Exchange) error {
+	var operationId int64
+	err := tx.QueryRow(lastAccountOperationIdSQL, accountId).Scan(&operationId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		addAccountExchangeSQL,
+		sql.Named("operation_id", operationId),
+		sql.Named("currency", string(exchange.Currency)),
+		sql.Named("amount", exchange.Amount.Kopecks()),
+		sql.Named("rate", int64(exchange.Rate)),
+	)
+	return err
+}
@@ -0,0 +1,109 @@
+package bank
+
+import (
+	"errors"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"testing"
+)
+
+func TestSetRates(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+
+	tests := []struct {
+		rates []ExchangeRate
+		err   error
+	}{
+		{[]ExchangeRate{{Currency: "EUR", Rate: money.RateOne}}, money.ErrUnknownCurrency},
+		{[]ExchangeRate{{Currency: money.BaseCurrency, Rate: money.RateOne}}, money.ErrInvalidRate},
+		{[]ExchangeRate{{Currency: money.USD, Rate: 0}}, money.ErrInvalidRate},
+		{[]ExchangeRate{{Currency: money.USD, Rate: 12650 * money.RateOne}, {Currency: money.RUB, Rate: -1}}, money.ErrInvalidRate},
+		{[]ExchangeRate{{Currency: money.USD, Rate: 12600 * money.RateOne}, {Currency: money.RUB, Rate: 140 * money.RateOne}}, nil},
+		{[]ExchangeRate{{Currency: money.USD, Rate: 12650 * money.RateOne}}, nil},
+	}
+	for _, test := range tests {
+		if err := SetRates(test.rates, db); !errors.Is(err, test.err) {
+			t.Errorf("SetRates(%+v) = %v, want %v", test.rates, err, test.err)
+		}
+	}
+
+	rates, err := GetRates(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[money.Currency]money.Rate{}
+	for _, rate := range rates {
+		got[rate.Currency] = rate.Rate
+	}
+	if len(rates) != 2 || got[money.USD] != 12650*money.RateOne || got[money.RUB] != 140*money.RateOne {
+		t.Errorf("GetRates() = %+v, want the last USD rate and the RUB one", rates)
+	}
+}
+
+func TestTransferBetweenCurrencies(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	rubId := addTestClient(t, "ivan", 1, 0, db)
+	usdId, err := AddAccount(1, 50000, money.USD, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addTestClient(t, "anna", 2, 0, db)
+	otherUsdId, err := AddAccount(2, 0, money.USD, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = QuoteTransfer(rubId, 0, usdId, 10000, db); !errors.Is(err, ErrNoRate) {
+		t.Errorf("QuoteTransfer() without rates = %v, want %v", err, ErrNoRate)
+	}
+	if err = TransferToByAccountId(rubId, "ivan", usdId, 10000, db); !errors.Is(err, ErrNoRate) {
+		t.Errorf("TransferToByAccountId() without rates = %v, want %v", err, ErrNoRate)
+	}
+	err = SetRates([]ExchangeRate{{Currency: money.USD, Rate: 12650 * money.RateOne}, {Currency: money.RUB, Rate: 140 * money.RateOne}}, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 100.00 USD at 12650 UZS is 903571.43 RUB at 140 UZS, rounded to the kopeck.
+	quote, err := QuoteTransfer(rubId, 0, usdId, 10000, db)
+	if err != nil || quote.From != money.USD || quote.To != money.RUB || quote.Converted != 903571 || quote.Rate != 90357143 {
+		t.Errorf("QuoteTransfer() = %+v, %v", quote, err)
+	}
+	if err = TransferToByAccountId(rubId, "ivan", usdId, 10000, db); err != nil {
+		t.Fatal(err)
+	}
+	if err = TransferToByAccountId(otherUsdId, "ivan", usdId, 2500, db); err != nil {
+		t.Fatal(err)
+	}
+	ivan, anna := balances(t, "ivan", db), balances(t, "anna", db)
+	if ivan[0] != 903571 || ivan[1] != 37500 || anna[1] != 2500 {
+		t.Errorf("balances after the transfers = %v and %v, want [9035.71 375.00] and [0.00 25.00]", ivan, anna)
+	}
+
+	journal, err := GetJournal("ivan", JournalFilter{}, "date", false, 10, 0, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journal.Totals) != 2 || journal.Totals[0] != (JournalTotal{money.RUB, 903571, 0}) ||
+		journal.Totals[1] != (JournalTotal{money.USD, 50000, 12500}) {
+		t.Errorf("journal totals = %+v, want 9035.71 RUB in, 500.00 USD in and 125.00 USD out", journal.Totals)
+	}
+
+	statement, err := GetStatement(rubId, date("2000-01-01"), date("2100-01-01"), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credited := statement.Operations[len(statement.Operations)-1]
+	if statement.Currency != money.RUB || credited.Exchange.Currency != money.USD ||
+		credited.Exchange.Amount != 10000 || credited.Exchange.Rate != 90357143 {
+		t.Errorf("credited operation = %+v, want the debited 100.00 USD", credited)
+	}
+	statement, err = GetStatement(otherUsdId, date("2000-01-01"), date("2100-01-01"), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credited = statement.Operations[len(statement.Operations)-1]; credited.Exchange.Currency != "" {
+		t.Errorf("transfer in the same currency has the exchange %+v", credited.Exchange)
+	}
+}
@@ -0,0 +1,156 @@
+package bank
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"sort"
+	"time"
+)
+
+// Incoming is the kind of the transfers received by a client, they are kept with the transfer type.
+const Incoming = "incoming"
+
+// OperationKinds are the values of JournalFilter.Type.
+var OperationKinds = []string{core.Transfer, Incoming, core.Service, Deposit}
+
+// JournalFilter selects operations from the journal of a client, zero fields don't restrict it.
+type JournalFilter struct {
+	// From and To are the first and the last day of the period.
+	From time.Time
+	To   time.Time
+	// Type is one of OperationKinds.
+	Type string
+	// MinAmount and MaxAmount bound the amount regardless of its sign.
+	MinAmount money.Money
+	MaxAmount money.Money
+	// Counterparty is the account id or the phone number of the other side of transfers.
+	Counterparty string
+	// Service and Text are looked for in the service name and in any counterparty and date, ignoring case.
+	Service string
+	Text    string
+}
+
+// JournalColumns are the columns GetJournal sorts by.
+var JournalColumns = []string{"date", "amount", "counterparty"}
+
+var journalColumns = map[string]string{
+	"date":         "o.date",
+	"amount":       "o.amount",
+	"counterparty": "o.counterparty",
+}
+
+// Journal is a page of the filtered operations with the totals of all of them.
+type Journal struct {
+	Operations []Operation
+	Count      int64
+	// Totals are kept apart for every currency of the accounts, sorted by the currency.
+	Totals []JournalTotal
+}
+
+// JournalTotal is the money received and spent in one currency.
+type JournalTotal struct {
+	Currency money.Currency
+	In       money.Money
+	Out      money.Money
+}
+
+// Kind is the type of operation with the incoming transfers told apart.
+func (receiver Operation) Kind() string {
+	if receiver.Type == core.Transfer && receiver.Incoming() {
+		return Incoming
+	}
+	return receiver.Type
+}
+
+// GetJournal returns a page of the operations selected by filter sorted by one of JournalColumns.
+func GetJournal(login string, filter JournalFilter, column string, desc bool, limit, offset int64, db *sql.DB) (journal Journal, err error) {
+	order, err := orderBy(journalColumns, column, desc, "o.id")
+	if err != nil {
+		return journal, err
+	}
+	args := filter.args(login)
+	err = inTx(db, func(tx *sql.Tx) error {
+		err := tx.QueryRow(countJournalSQL, args...).Scan(&journal.Count)
+		if err != nil {
+			return err
+		}
+		journal.Totals, err = queryTotals(tx, args)
+		if err != nil {
+			return err
+		}
+		journal.Operations, err = queryOperations(tx, fmt.Sprintf(getJournalSQL, order),
+			append(args, sql.Named("limit", limit), sql.Named("offset", offset))...)
+		if err != nil {
+			return err
+		}
+		return queryDetails(tx, journal.Operations)
+	})
+	return journal, err
+}
+
+// queryTotals sums the operations selected by args by currency, the accounts without one are
+// in money.DefaultCurrency.
+func queryTotals(tx *sql.Tx, args []interface{}) (totals []JournalTotal, err error) {
+	rows, err := tx.Query(getJournalTotalsSQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	byCurrency := map[money.Currency]int{}
+	for rows.Next() {
+		var currency string
+		var in, out int64
+		err = rows.Scan(&currency, &in, &out)
+		if err != nil {
+			return nil, err
+		}
+		total := JournalTotal{Currency: orDefault(currency), In: money.FromKopecks(in), Out: money.FromKopecks(out)}
+		if idx, ok := byCurrency[total.Currency]; ok {
+			totals[idx].In += total.In
+			totals[idx].Out += total.Out
+			continue
+		}
+		byCurrency[total.Currency] = len(totals)
+		totals = append(totals, total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Currency < totals[j].Currency
+	})
+	return totals, rows.Err()
+}
+
+func (receiver JournalFilter) args(login string) []interface{} {
+	var from, to string
+	if !receiver.From.IsZero() {
+		from = startOfDay(receiver.From).Format(OperationDateFormat)
+	}
+	if !receiver.To.IsZero() {
+		to = startOfDay(receiver.To).AddDate(0, 0, 1).Format(OperationDateFormat)
+	}
+	return []interface{}{
+		sql.Named("login", login),
+		sql.Named("from", from),
+		sql.Named("to", to),
+		sql.Named("type", receiver.Type),
+		sql.Named("min_amount", receiver.MinAmount.Kopecks()),
+		sql.Named("max_amount", receiver.MaxAmount.Kopecks()),
+		sql.Named("counterparty", receiver.Counterparty),
+		sql.Named("service", like(receiver.Service)),
+		sql.Named("text", like(receiver.Text)),
+	}
+}
+
+// like makes a pattern matching the texts containing value, empty values stay empty.
+func like(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "%" + value + "%"
+}
@@ -0,0 +1,60 @@
+package bank
+
+import (
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"testing"
+)
+
+func TestMoveJournal(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	// The accounts are opened and used by core first, as before the cli kept its own ledger.
+	for _, err := range []error{
+		core.AddClient("payer", "payer", "secret", 1, db),
+		core.AddClient("payee", "payee", "secret", 2, db),
+		core.AddAccount(1, 100, db),
+		core.AddAccount(2, 0, db),
+		core.AddService("Mobile", db),
+		core.TransferToByAccountId(2, "payer", 1, 12.5, db),
+		core.TransferToByPhoneNumber(2, "payer", 1, 2.5, db),
+		core.PayForService("Mobile", 1, "payer", 1, db),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := db.Exec(`UPDATE journal SET date = '01-15-2020 10:00:0' || id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = Init(db); err != nil {
+		t.Fatal(err)
+	}
+	if err = TransferToByAccountId(2, "payer", 1, 300, db); err != nil {
+		t.Fatal(err)
+	}
+	if err = Init(db); err != nil {
+		t.Fatalf("Init() again = %v", err)
+	}
+
+	tests := []struct {
+		login   string
+		count   int64
+		in, out money.Money
+	}{
+		{"payer", 4, 0, 1900},
+		{"payee", 3, 1800, 0},
+	}
+	for _, test := range tests {
+		journal, err := GetJournal(test.login, JournalFilter{}, "date", false, 10, 0, db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if journal.Count != test.count || len(journal.Totals) != 1 ||
+			journal.Totals[0] != (JournalTotal{money.RUB, test.in, test.out}) {
+			t.Errorf("GetJournal(%s) count %d, totals %+v, want %d, %s in and %s out",
+				test.login, journal.Count, journal.Totals, test.count, test.in, test.out)
+		}
+	}
+}
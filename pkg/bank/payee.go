@@ -0,0 +1,117 @@
+package bank
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+)
+
+var (
+	ErrPayeeExist    = errors.New("payee exists")
+	ErrPayeeNotExist = errors.New("payee not found")
+)
+
+// Payee is a saved recipient of a client, exactly one of AccountId and PhoneNumber is set.
+type Payee struct {
+	Id          int64
+	Login       string
+	Name        string
+	AccountId   int64
+	PhoneNumber int64
+	// FromAccountId and Amount are set for templates and pre-fill the transfer.
+	FromAccountId int64
+	Amount        money.Money
+}
+
+func (receiver Payee) IsTemplate() bool {
+	return receiver.FromAccountId != 0 && receiver.Amount > 0
+}
+
+func AddPayee(payee Payee, db *sql.DB) (err error) {
+	err = checkPayeeExist(payee.Login, payee.Name, db)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(
+		addPayeeSQL,
+		sql.Named("login", payee.Login),
+		sql.Named("name", payee.Name),
+		sql.Named("account_id", payee.AccountId),
+		sql.Named("phone_number", payee.PhoneNumber),
+		sql.Named("from_account_id", payee.FromAccountId),
+		sql.Named("amount", payee.Amount.Kopecks()),
+	)
+	return err
+}
+
+func GetPayees(login string, db *sql.DB) (payees []Payee, err error) {
+	rows, err := db.Query(getPayeesSQL, login)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var payee Payee
+		var amount int64
+		err = rows.Scan(&payee.Id, &payee.Login, &payee.Name, &payee.AccountId, &payee.PhoneNumber, &payee.FromAccountId, &amount)
+		if err != nil {
+			return nil, err
+		}
+		payee.Amount = money.FromKopecks(amount)
+		payees = append(payees, payee)
+	}
+	return payees, rows.Err()
+}
+
+func RenamePayee(login string, id int64, name string, db *sql.DB) (err error) {
+	err = checkPayeeExist(login, name, db)
+	if err != nil {
+		return err
+	}
+	result, err := db.Exec(
+		renamePayeeSQL,
+		sql.Named("name", name),
+		sql.Named("id", id),
+		sql.Named("login", login),
+	)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+func DeletePayee(login string, id int64, db *sql.DB) (err error) {
+	result, err := db.Exec(deletePayeeSQL, id, login)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+func checkPayeeExist(login, name string, db *sql.DB) (err error) {
+	var id int64
+	err = db.QueryRow(payeeExistSQL, login, name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return ErrPayeeExist
+}
+
+func checkAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrPayeeNotExist
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package bank
+
+import (
+	"testing"
+)
+
+func TestIsTemplate(t *testing.T) {
+	tests := []struct {
+		payee Payee
+		want  bool
+	}{
+		{Payee{AccountId: 2}, false},
+		{Payee{AccountId: 2, FromAccountId: 1}, false},
+		{Payee{PhoneNumber: 2, Amount: 100}, false},
+		{Payee{PhoneNumber: 2, FromAccountId: 1, Amount: 100}, true},
+	}
+	for _, test := range tests {
+		if got := test.payee.IsTemplate(); got != test.want {
+			t.Errorf("%+v IsTemplate() = %t, want %t", test.payee, got, test.want)
+		}
+	}
+}
+
+func TestPayees(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+
+	err := AddPayee(Payee{Login: "ivan", Name: "mother", PhoneNumber: 998901234567}, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = AddPayee(Payee{Login: "ivan", Name: "rent", AccountId: 7, FromAccountId: 1, Amount: 150000}, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = AddPayee(Payee{Login: "ivan", Name: "mother", AccountId: 3}, db); err != ErrPayeeExist {
+		t.Errorf("AddPayee() with a taken name = %v, want %v", err, ErrPayeeExist)
+	}
+	if err = AddPayee(Payee{Login: "anna", Name: "mother", AccountId: 3}, db); err != nil {
+		t.Errorf("AddPayee() with the name of a payee of another client = %v", err)
+	}
+
+	payees, err := GetPayees("ivan", db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payees) != 2 {
+		t.Fatalf("GetPayees() = %+v, want 2 payees", payees)
+	}
+	rent := payees[0]
+	if rent.Name != "rent" {
+		rent = payees[1]
+	}
+	if !rent.IsTemplate() || rent.AccountId != 7 || rent.Amount != 150000 {
+		t.Errorf("template read back = %+v", rent)
+	}
+
+	if err = RenamePayee("ivan", rent.Id, "mother", db); err != ErrPayeeExist {
+		t.Errorf("RenamePayee() to a taken name = %v, want %v", err, ErrPayeeExist)
+	}
+	if err = RenamePayee("anna", rent.Id, "flat", db); err != ErrPayeeNotExist {
+		t.Errorf("RenamePayee() of a payee of another client = %v, want %v", err, ErrPayeeNotExist)
+	}
+	if err = RenamePayee("ivan", rent.Id, "flat", db); err != nil {
+		t.Errorf("RenamePayee() = %v", err)
+	}
+	if err = DeletePayee("anna", rent.Id, db); err != ErrPayeeNotExist {
+		t.Errorf("DeletePayee() of a payee of another client = %v, want %v", err, ErrPayeeNotExist)
+	}
+	if err = DeletePayee("ivan", rent.Id, db); err != nil {
+		t.Errorf("DeletePayee() = %v", err)
+	}
+	payees, err = GetPayees("ivan", db)
+	if err != nil || len(payees) != 1 || payees[0].Name != "mother" {
+		t.Errorf("GetPayees() after the deletion = %+v, %v", payees, err)
+	}
+}
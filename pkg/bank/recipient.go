@@ -0,0 +1,36 @@
+package bank
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// RecipientByAccountId returns the masked name of the owner of accountId.
+func RecipientByAccountId(accountId int64, db *sql.DB) (name string, err error) {
+	err = db.QueryRow(getClientNameByAccountIdSQL, accountId).Scan(&name)
+	if err != nil {
+		return "", err
+	}
+	return MaskName(name), nil
+}
+
+// RecipientByPhoneNumber returns the masked name of the client with phoneNumber.
+func RecipientByPhoneNumber(phoneNumber int64, db *sql.DB) (name string, err error) {
+	err = db.QueryRow(getClientNameByPhoneNumberSQL, phoneNumber).Scan(&name)
+	if err != nil {
+		return "", err
+	}
+	return MaskName(name), nil
+}
+
+// MaskName keeps the first name and the initial of the second one, Иван Петров becomes Иван П.
+func MaskName(name string) string {
+	parts := strings.Fields(name)
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		return parts[0]
+	}
+	return parts[0] + " " + string([]rune(parts[1])[:1]) + "."
+}
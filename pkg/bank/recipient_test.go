@@ -0,0 +1,24 @@
+package bank
+
+import "testing"
+
+func TestMaskName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Ivan", "Ivan"},
+		{"Ivan Petrov", "Ivan P."},
+		{"Ivan Petrov Sidorovich", "Ivan P."},
+		{"  Ivan   petrov ", "Ivan p."},
+		{"Иван Петров", "Иван П."},
+		{"Ёрқин Ўлмасов", "Ёрқин Ў."},
+		{"", ""},
+		{"   ", ""},
+	}
+	for _, test := range tests {
+		if got := MaskName(test.name); got != test.want {
+			t.Errorf("MaskName(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package bank
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"time"
+)
+
+type Period string
+
+const (
+	Once      Period = "once"
+	Weekly    Period = "weekly"
+	Monthly   Period = "monthly"
+	EveryDays Period = "days"
+)
+
+var Periods = []Period{Once, Weekly, Monthly, EveryDays}
+
+const (
+	ScheduleActive    = "active"
+	ScheduleDone      = "done"
+	ScheduleCancelled = "cancelled"
+	ScheduleFailed    = "failed"
+)
+
+const (
+	RunSucceeded = "success"
+	RunFailed    = "failed"
+)
+
+// DateFormat is the format of the dates of scheduled transfers, it keeps them ordered as text.
+const DateFormat = "2006-01-02"
+
+const runTimeFormat = "2006-01-02 15:04:05"
+
+// maxRetryDelay limits the wait before a failed transfer is retried.
+const maxRetryDelay = 24 * time.Hour
+
+var (
+	ErrInvalidSchedule     = errors.New("invalid schedule")
+	ErrScheduledNotExist   = errors.New("scheduled transfer not found")
+	errScheduledAlreadyRun = errors.New("scheduled transfer already run")
+)
+
+// ScheduledTransfer is a transfer order of a client, exactly one of AccountId and PhoneNumber is set.
+type ScheduledTransfer struct {
+	Id            int64
+	Login         string
+	FromAccountId int64
+	AccountId     int64
+	PhoneNumber   int64
+	Amount        money.Money
+	Period        Period
+	// IntervalDays is the number of days between the transfers of the EveryDays period.
+	IntervalDays int64
+	// MonthDay is the day of the first transfer, monthly ones use the last day of shorter months.
+	MonthDay  int
+	NextDate  time.Time
+	Status    string
+	Attempts  int64
+	LastError string
+}
+
+// ScheduledRun records one attempt to execute a scheduled transfer.
+type ScheduledRun struct {
+	Id                  int64
+	ScheduledTransferId int64
+	DueDate             string
+	RunAt               string
+	Attempt             int64
+	Status              string
+	Error               string
+}
+
+// After returns the date following date, ok is false when the transfer does not repeat.
+func (receiver ScheduledTransfer) After(date time.Time) (next time.Time, ok bool) {
+	switch receiver.Period {
+	case Weekly:
+		return date.AddDate(0, 0, 7), true
+	case EveryDays:
+		return date.AddDate(0, 0, int(receiver.IntervalDays)), true
+	case Monthly:
+		year, month, _ := date.Date()
+		firstOfNext := time.Date(year, month+1, 1, 0, 0, 0, 0, date.Location())
+		day := receiver.MonthDay
+		if last := firstOfNext.AddDate(0, 1, -1).Day(); day > last {
+			day = last
+		}
+		return firstOfNext.AddDate(0, 0, day-1), true
+	}
+	return date, false
+}
+
+func AddScheduledTransfer(order ScheduledTransfer, db *sql.DB) (id int64, err error) {
+	order.MonthDay = order.NextDate.Day()
+	if !validSchedule(order) {
+		return 0, ErrInvalidSchedule
+	}
+	err = inTx(db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(
+			addScheduledTransferSQL,
+			sql.Named("login", order.Login),
+			sql.Named("from_account_id", order.FromAccountId),
+			sql.Named("account_id", order.AccountId),
+			sql.Named("phone_number", order.PhoneNumber),
+			sql.Named("amount", order.Amount.Kopecks()),
+			sql.Named("period", string(order.Period)),
+			sql.Named("interval_days", order.IntervalDays),
+			sql.Named("month_day", order.MonthDay),
+			sql.Named("next_date", order.NextDate.Format(DateFormat)),
+			sql.Named("status", ScheduleActive),
+		)
+		if err != nil {
+			return err
+		}
+		return tx.QueryRow(lastScheduledTransferIdSQL, order.Login).Scan(&id)
+	})
+	return id, err
+}
+
+func validSchedule(order ScheduledTransfer) bool {
+	if order.Amount <= 0 || order.FromAccountId <= 0 || (order.AccountId == 0) == (order.PhoneNumber == 0) {
+		return false
+	}
+	for _, period := range Periods {
+		if order.Period == period {
+			return period != EveryDays || order.IntervalDays > 0
+		}
+	}
+	return false
+}
+
+func GetScheduledTransfers(login string, db *sql.DB) (orders []ScheduledTransfer, err error) {
+	return queryScheduledTransfers(db, getScheduledTransfersSQL, login)
+}
+
+func CancelScheduledTransfer(login string, id int64, db *sql.DB) (err error) {
+	result, err := db.Exec(
+		cancelScheduledTransferSQL,
+		sql.Named("id", id),
+		sql.Named("login", login),
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrScheduledNotExist
+	}
+	return nil
+}
+
+// RunScheduled executes the active transfers due on or before today, each one at most once per call.
+// A transfer and the move of its order to the next date share a transaction, so running it again,
+// even concurrently, never repeats a transfer. A failed transfer stays due and is retried by the
+// calls made retryDelay after it, the delay doubling after every failure up to a day, until it
+// fails maxAttempts times, then a repeating one waits for its next date and a one-off one is marked
+// failed. An overdue repeating transfer is executed once and moved to its first date after today,
+// the dates missed in between are skipped.
+func RunScheduled(today time.Time, maxAttempts int64, retryDelay time.Duration, db *sql.DB) (runs []ScheduledRun, err error) {
+	now := time.Now()
+	orders, err := queryScheduledTransfers(db, getDueScheduledTransfersSQL, today.Format(DateFormat), now.Format(runTimeFormat))
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		run, err := runScheduledTransfer(order, today, now, maxAttempts, retryDelay, db)
+		if errors.Is(err, errScheduledAlreadyRun) {
+			continue
+		}
+		if err != nil {
+			return runs, fmt.Errorf("can't run scheduled transfer %d: %w", order.Id, err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+func runScheduledTransfer(
+	order ScheduledTransfer, today, now time.Time, maxAttempts int64, retryDelay time.Duration, db *sql.DB,
+) (run ScheduledRun, err error) {
+	run = ScheduledRun{
+		ScheduledTransferId: order.Id,
+		DueDate:             order.NextDate.Format(DateFormat),
+		RunAt:               now.Format(runTimeFormat),
+		Attempt:             order.Attempts + 1,
+		Status:              RunSucceeded,
+	}
+	next, status := order.following(today)
+	err = inTx(db, func(tx *sql.Tx) error {
+		err := updateScheduledTransfer(tx, order, next, status, 0, time.Time{})
+		if err != nil {
+			return err
+		}
+		err = transfer(tx, order.AccountId, order.PhoneNumber, order.Login, order.FromAccountId, order.Amount)
+		if err != nil {
+			return err
+		}
+		return addScheduledRun(tx, run)
+	})
+	if err == nil || errors.Is(err, errScheduledAlreadyRun) {
+		return run, err
+	}
+
+	run.Status, run.Error = RunFailed, err.Error()
+	attempts := run.Attempt
+	var retry time.Time
+	if attempts < maxAttempts {
+		next, status = order.NextDate, ScheduleActive
+		retry = retryAt(now, retryDelay, attempts)
+	} else {
+		attempts = 0
+		if status == ScheduleDone {
+			status = ScheduleFailed
+		}
+	}
+	err = inTx(db, func(tx *sql.Tx) error {
+		err := updateScheduledTransfer(tx, order, next, status, attempts, retry)
+		if err != nil {
+			return err
+		}
+		return addScheduledRun(tx, run)
+	})
+	return run, err
+}
+
+// following returns the date and the status of order after its due transfer, the first date
+// of a repeating order after today.
+func (receiver ScheduledTransfer) following(today time.Time) (next time.Time, status string) {
+	next, ok := receiver.After(receiver.NextDate)
+	if !ok {
+		return receiver.NextDate, ScheduleDone
+	}
+	day := startOfDay(today)
+	for !next.After(day) {
+		next, _ = receiver.After(next)
+	}
+	return next, ScheduleActive
+}
+
+func startOfDay(date time.Time) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+}
+
+// retryAt returns when a transfer failed attempt times is retried, delay doubles after every
+// failure but the first one and stops at maxRetryDelay.
+func retryAt(now time.Time, delay time.Duration, attempt int64) time.Time {
+	for ; attempt > 1 && delay < maxRetryDelay; attempt-- {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return now.Add(delay)
+}
+
+// updateScheduledTransfer moves order on unless another run did it since order was read,
+// a zero retry lets the next run execute it.
+func updateScheduledTransfer(tx *sql.Tx, order ScheduledTransfer, next time.Time, status string, attempts int64, retry time.Time) error {
+	retryTime := ""
+	if !retry.IsZero() {
+		retryTime = retry.Format(runTimeFormat)
+	}
+	result, err := tx.Exec(
+		updateScheduledTransferSQL,
+		sql.Named("next_date", next.Format(DateFormat)),
+		sql.Named("status", status),
+		sql.Named("attempts", attempts),
+		sql.Named("retry_at", retryTime),
+		sql.Named("id", order.Id),
+		sql.Named("due_date", order.NextDate.Format(DateFormat)),
+		sql.Named("previous_attempts", order.Attempts),
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errScheduledAlreadyRun
+	}
+	return nil
+}
+
+func addScheduledRun(tx *sql.Tx, run ScheduledRun) error {
+	_, err := tx.Exec(
+		addScheduledRunSQL,
+		sql.Named("scheduled_transfer_id", run.ScheduledTransferId),
+		sql.Named("due_date", run.DueDate),
+		sql.Named("run_at", run.RunAt),
+		sql.Named("attempt", run.Attempt),
+		sql.Named("status", run.Status),
+		sql.Named("error", run.Error),
+	)
+	return err
+}
+
+func queryScheduledTransfers(db *sql.DB, query string, args ...interface{}) (orders []ScheduledTransfer, err error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var order ScheduledTransfer
+		var amount int64
+		var period, nextDate string
+		err = rows.Scan(
+			&order.Id, &order.Login, &order.FromAccountId, &order.AccountId, &order.PhoneNumber, &amount, &period,
+			&order.IntervalDays, &order.MonthDay, &nextDate, &order.Status, &order.Attempts, &order.LastError,
+		)
+		if err != nil {
+			return nil, err
+		}
+		order.Amount = money.FromKopecks(amount)
+		order.Period = Period(period)
+		order.NextDate, err = time.ParseInLocation(DateFormat, nextDate, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
@@ -0,0 +1,148 @@
+package bank
+
+import (
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	parsed, err := time.ParseInLocation(DateFormat, value, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func TestAfter(t *testing.T) {
+	tests := []struct {
+		order ScheduledTransfer
+		date  string
+		want  string
+		ok    bool
+	}{
+		{ScheduledTransfer{Period: Once}, "2020-01-31", "2020-01-31", false},
+		{ScheduledTransfer{Period: Weekly}, "2020-12-28", "2021-01-04", true},
+		{ScheduledTransfer{Period: EveryDays, IntervalDays: 10}, "2020-02-25", "2020-03-06", true},
+		{ScheduledTransfer{Period: Monthly, MonthDay: 31}, "2020-01-31", "2020-02-29", true},
+		{ScheduledTransfer{Period: Monthly, MonthDay: 31}, "2020-02-29", "2020-03-31", true},
+		{ScheduledTransfer{Period: Monthly, MonthDay: 30}, "2021-01-30", "2021-02-28", true},
+		{ScheduledTransfer{Period: Monthly, MonthDay: 15}, "2020-12-15", "2021-01-15", true},
+	}
+	for _, test := range tests {
+		next, ok := test.order.After(date(test.date))
+		if next.Format(DateFormat) != test.want || ok != test.ok {
+			t.Errorf("%s After(%s) = %s, %t, want %s, %t",
+				test.order.Period, test.date, next.Format(DateFormat), ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestFollowing(t *testing.T) {
+	tests := []struct {
+		order  ScheduledTransfer
+		today  string
+		want   string
+		status string
+	}{
+		{ScheduledTransfer{Period: Once, NextDate: date("2020-01-01")}, "2020-03-01", "2020-01-01", ScheduleDone},
+		{ScheduledTransfer{Period: Weekly, NextDate: date("2020-03-01")}, "2020-03-01", "2020-03-08", ScheduleActive},
+		{ScheduledTransfer{Period: Weekly, NextDate: date("2020-01-01")}, "2020-03-01", "2020-03-04", ScheduleActive},
+		{ScheduledTransfer{Period: Weekly, NextDate: date("2020-02-23")}, "2020-03-01", "2020-03-08", ScheduleActive},
+		{ScheduledTransfer{Period: Monthly, MonthDay: 31, NextDate: date("2020-01-31")}, "2020-04-15", "2020-04-30", ScheduleActive},
+		{ScheduledTransfer{Period: EveryDays, IntervalDays: 3, NextDate: date("2020-03-01")}, "2020-03-02", "2020-03-04", ScheduleActive},
+	}
+	for _, test := range tests {
+		today := date(test.today).Add(15 * time.Hour)
+		next, status := test.order.following(today)
+		if next.Format(DateFormat) != test.want || status != test.status {
+			t.Errorf("%s from %s following(%s) = %s, %s, want %s, %s", test.order.Period, test.order.NextDate.Format(DateFormat),
+				test.today, next.Format(DateFormat), status, test.want, test.status)
+		}
+	}
+}
+
+func TestRetryAt(t *testing.T) {
+	now := time.Date(2020, 3, 1, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		delay   time.Duration
+		attempt int64
+		want    time.Duration
+	}{
+		{time.Hour, 1, time.Hour},
+		{time.Hour, 2, 2 * time.Hour},
+		{time.Hour, 4, 8 * time.Hour},
+		{time.Hour, 10, maxRetryDelay},
+		{time.Hour, 1000, maxRetryDelay},
+		{48 * time.Hour, 1, maxRetryDelay},
+		{0, 5, 0},
+	}
+	for _, test := range tests {
+		if got := retryAt(now, test.delay, test.attempt).Sub(now); got != test.want {
+			t.Errorf("retryAt(%s, %d) is %s later, want %s", test.delay, test.attempt, got, test.want)
+		}
+	}
+}
+
+func TestRunScheduled(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	fromId := addTestClient(t, "payer", 1, 10000, db)
+	toId := addTestClient(t, "payee", 2, 0, db)
+	today := startOfDay(time.Now())
+
+	overdue, err := AddScheduledTransfer(ScheduledTransfer{
+		Login: "payer", FromAccountId: fromId, AccountId: toId, Amount: 1000,
+		Period: Weekly, NextDate: today.AddDate(0, 0, -15),
+	}, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expensive, err := AddScheduledTransfer(ScheduledTransfer{
+		Login: "payer", FromAccountId: fromId, AccountId: toId, Amount: 100000,
+		Period: Once, NextDate: today,
+	}, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := RunScheduled(today, 2, time.Hour, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].ScheduledTransferId != overdue || runs[0].Status != RunSucceeded ||
+		runs[1].ScheduledTransferId != expensive || runs[1].Status != RunFailed {
+		t.Fatalf("first RunScheduled() = %+v", runs)
+	}
+	orders, err := GetScheduledTransfers("payer", db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := today.AddDate(0, 0, 6); !orders[0].NextDate.Equal(want) {
+		t.Errorf("overdue order moved to %s, want %s", orders[0].NextDate.Format(DateFormat), want.Format(DateFormat))
+	}
+
+	runs, err = RunScheduled(today, 2, time.Hour, db)
+	if err != nil || len(runs) != 0 {
+		t.Errorf("RunScheduled() before the retry delay = %+v, %v, want no runs", runs, err)
+	}
+
+	_, err = db.Exec(`UPDATE scheduled_transfers SET retry_at = '' WHERE id = ?`, expensive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runs, err = RunScheduled(today, 2, time.Hour, db)
+	if err != nil || len(runs) != 1 || runs[0].Attempt != 2 || runs[0].Status != RunFailed {
+		t.Fatalf("RunScheduled() after the retry delay = %+v, %v", runs, err)
+	}
+	orders, err = GetScheduledTransfers("payer", db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orders[1].Status != ScheduleFailed {
+		t.Errorf("order failed twice has status %s, want %s", orders[1].Status, ScheduleFailed)
+	}
+
+	if got := balances(t, "payee", db); len(got) != 1 || got[0] != 1000 {
+		t.Errorf("balances of the payee = %v, want one with 10.00", got)
+	}
+}
@@ -0,0 +1,337 @@
+package bank
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/queries"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// The kinds of payment fields: any text, digits only, and a phone number of 9 to 12 digits
+// that may be typed with a plus, spaces, dashes and brackets.
+const (
+	FieldText   = "text"
+	FieldDigits = "digits"
+	FieldPhone  = "phone"
+)
+
+var FieldKinds = []string{FieldText, FieldDigits, FieldPhone}
+
+const (
+	minPhoneLength = 9
+	maxPhoneLength = 12
+)
+
+var (
+	ErrInvalidField = errors.New("invalid payment field")
+	ErrMissingValue = errors.New("value required")
+	ErrInvalidValue = errors.New("invalid value")
+	ErrUnknownField = errors.New("unknown payment field")
+)
+
+// Service is a service clients pay for, they enter a value for each of Fields with the payment.
+type Service struct {
+	Id       int64
+	Name     string
+	Category string
+	Fields   []ServiceField
+}
+
+// ServiceField is a detail of a payment with the rules its value follows. MinLength and
+// MaxLength count characters and are not checked when 0, Pattern is a regular expression
+// the whole value matches.
+type ServiceField struct {
+	Name      string
+	Kind      string
+	MinLength int
+	MaxLength int
+	Pattern   string
+}
+
+// PaymentDetail is the value of a field of a service entered with a payment.
+type PaymentDetail struct {
+	Name  string
+	Value string
+}
+
+// FieldError tells which field of a payment has no value or a wrong one, or isn't a field of the service.
+type FieldError struct {
+	Field ServiceField
+	Err   error
+}
+
+func (receiver *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", receiver.Field.Name, receiver.Err)
+}
+
+func (receiver *FieldError) Unwrap() error {
+	return receiver.Err
+}
+
+// Check makes sure the rules of the field can be followed.
+func (receiver ServiceField) Check() error {
+	known := false
+	for _, kind := range FieldKinds {
+		known = known || receiver.Kind == kind
+	}
+	if strings.TrimSpace(receiver.Name) == "" || !known {
+		return ErrInvalidField
+	}
+	if receiver.MinLength < 0 || receiver.MaxLength < 0 ||
+		(receiver.MaxLength > 0 && receiver.MinLength > receiver.MaxLength) {
+		return fmt.Errorf("%w: length %d to %d", ErrInvalidField, receiver.MinLength, receiver.MaxLength)
+	}
+	if _, err := regexp.Compile(receiver.Pattern); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidField, err)
+	}
+	return nil
+}
+
+// Validate returns value as it is kept with the payment, a phone number with digits only,
+// or ErrInvalidValue when it breaks the rules of the field.
+func (receiver ServiceField) Validate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", ErrMissingValue
+	}
+	if receiver.Kind == FieldPhone {
+		value = strings.TrimPrefix(strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(value), "+")
+		if len(value) < minPhoneLength || len(value) > maxPhoneLength {
+			return "", ErrInvalidValue
+		}
+	}
+	if receiver.Kind != FieldText {
+		for _, char := range value {
+			if char < '0' || char > '9' {
+				return "", ErrInvalidValue
+			}
+		}
+	}
+	length := utf8.RuneCountInString(value)
+	if length < receiver.MinLength || (receiver.MaxLength > 0 && length > receiver.MaxLength) {
+		return "", ErrInvalidValue
+	}
+	if receiver.Pattern != "" {
+		matched, err := regexp.MatchString("^(?:"+receiver.Pattern+")$", value)
+		if err != nil || !matched {
+			return "", ErrInvalidValue
+		}
+	}
+	return value, nil
+}
+
+// AddService adds service with its category and fields.
+func AddService(service Service, db *sql.DB) error {
+	for _, field := range service.Fields {
+		if err := field.Check(); err != nil {
+			return err
+		}
+	}
+	var name string
+	err := db.QueryRow(queries.ServiceExistSQL, service.Name).Scan(&name)
+	if err == nil {
+		return core.ErrServiceExist
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return inTx(db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(queries.AddServiceSQL, sql.Named("name", service.Name))
+		if err != nil {
+			return err
+		}
+		var serviceId int64
+		err = tx.QueryRow(getServiceIdSQL, service.Name).Scan(&serviceId)
+		if err != nil {
+			return err
+		}
+		if category := strings.TrimSpace(service.Category); category != "" {
+			_, err = tx.Exec(addServiceCategorySQL, serviceId, category)
+			if err != nil {
+				return err
+			}
+		}
+		for idx, field := range service.Fields {
+			_, err = tx.Exec(
+				addServiceFieldSQL,
+				sql.Named("service_id", serviceId),
+				sql.Named("position", idx+1),
+				sql.Named("name", strings.TrimSpace(field.Name)),
+				sql.Named("kind", field.Kind),
+				sql.Named("min_length", field.MinLength),
+				sql.Named("max_length", field.MaxLength),
+				sql.Named("pattern", field.Pattern),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetServices returns the services with their fields sorted by category and name.
+func GetServices(db *sql.DB) (services []Service, err error) {
+	err = inTx(db, func(tx *sql.Tx) error {
+		services, err = queryServices(tx)
+		if err != nil {
+			return err
+		}
+		fields, err := queryFields(tx, getServiceFieldsSQL)
+		if err != nil {
+			return err
+		}
+		for idx := range services {
+			services[idx].Fields = fields[services[idx].Id]
+		}
+		return nil
+	})
+	return services, err
+}
+
+func queryServices(tx *sql.Tx) (services []Service, err error) {
+	rows, err := tx.Query(getServicesSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var service Service
+		err = rows.Scan(&service.Id, &service.Name, &service.Category)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, service)
+	}
+	return services, rows.Err()
+}
+
+// queryFields returns the fields selected by query by the id of their service.
+func queryFields(tx *sql.Tx, query string, args ...interface{}) (fields map[int64][]ServiceField, err error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	fields = make(map[int64][]ServiceField)
+	for rows.Next() {
+		var serviceId int64
+		var field ServiceField
+		err = rows.Scan(&serviceId, &field.Name, &field.Kind, &field.MinLength, &field.MaxLength, &field.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		fields[serviceId] = append(fields[serviceId], field)
+	}
+	return fields, rows.Err()
+}
+
+func serviceFields(tx *sql.Tx, serviceId int64) ([]ServiceField, error) {
+	fields, err := queryFields(tx, getFieldsOfServiceSQL, serviceId)
+	return fields[serviceId], err
+}
+
+// fillFields returns the valid values of fields in their order, the names of details are
+// matched ignoring case.
+func fillFields(fields []ServiceField, details []PaymentDetail) ([]PaymentDetail, error) {
+	for _, detail := range details {
+		known := false
+		for _, field := range fields {
+			known = known || strings.EqualFold(strings.TrimSpace(detail.Name), field.Name)
+		}
+		if !known {
+			return nil, &FieldError{Field: ServiceField{Name: detail.Name}, Err: ErrUnknownField}
+		}
+	}
+	filled := make([]PaymentDetail, 0, len(fields))
+	for _, field := range fields {
+		var value string
+		for _, detail := range details {
+			if strings.EqualFold(strings.TrimSpace(detail.Name), field.Name) {
+				value = detail.Value
+			}
+		}
+		value, err := field.Validate(value)
+		if err != nil {
+			return nil, &FieldError{Field: field, Err: err}
+		}
+		filled = append(filled, PaymentDetail{Name: field.Name, Value: value})
+	}
+	return filled, nil
+}
+
+// addDetails keeps details with the last operation of accountId.
+func addDetails(tx *sql.Tx, accountId int64, details []PaymentDetail) error {
+	if len(details) == 0 {
+		return nil
+	}
+	var operationId int64
+	err := tx.QueryRow(lastAccountOperationIdSQL, accountId).Scan(&operationId)
+	if err != nil {
+		return err
+	}
+	for idx, detail := range details {
+		_, err = tx.Exec(
+			addOperationDetailSQL,
+			sql.Named("operation_id", operationId),
+			sql.Named("position", idx+1),
+			sql.Named("name", detail.Name),
+			sql.Named("value", detail.Value),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryDetails reads the details of the service payments among operations.
+func queryDetails(tx *sql.Tx, operations []Operation) error {
+	for idx := range operations {
+		if operations[idx].Type != core.Service {
+			continue
+		}
+		details, err := operationDetails(tx, operations[idx].Id)
+		if err != nil {
+			return err
+		}
+		operations[idx].Details = details
+	}
+	return nil
+}
+
+func operationDetails(tx *sql.Tx, operationId int64) (details []PaymentDetail, err error) {
+	rows, err := tx.Query(getOperationDetailsSQL, operationId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var detail PaymentDetail
+		err = rows.Scan(&detail.Name, &detail.Value)
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, detail)
+	}
+	return details, rows.Err()
+}
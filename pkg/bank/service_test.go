@@ -0,0 +1,132 @@
+package bank
+
+import (
+	"errors"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"testing"
+)
+
+func TestServiceFieldCheck(t *testing.T) {
+	tests := []struct {
+		field ServiceField
+		ok    bool
+	}{
+		{ServiceField{Name: "account", Kind: FieldDigits, MinLength: 5, MaxLength: 10}, true},
+		{ServiceField{Name: "comment", Kind: FieldText, Pattern: "[a-z]+"}, true},
+		{ServiceField{Name: "phone", Kind: FieldPhone, MinLength: 3}, true},
+		{ServiceField{Name: "  ", Kind: FieldText}, false},
+		{ServiceField{Name: "account", Kind: "number"}, false},
+		{ServiceField{Name: "account", Kind: FieldDigits, MinLength: -1}, false},
+		{ServiceField{Name: "account", Kind: FieldDigits, MinLength: 10, MaxLength: 5}, false},
+		{ServiceField{Name: "account", Kind: FieldDigits, Pattern: "[0-9"}, false},
+	}
+	for _, test := range tests {
+		err := test.field.Check()
+		if (err == nil) != test.ok || (err != nil && !errors.Is(err, ErrInvalidField)) {
+			t.Errorf("%+v Check() = %v, want ok %t", test.field, err, test.ok)
+		}
+	}
+}
+
+func TestServiceFieldValidate(t *testing.T) {
+	account := ServiceField{Name: "account", Kind: FieldDigits, MinLength: 5, MaxLength: 6}
+	phone := ServiceField{Name: "phone", Kind: FieldPhone}
+	comment := ServiceField{Name: "comment", Kind: FieldText, MaxLength: 5, Pattern: "[a-zа-я]+"}
+	tests := []struct {
+		field ServiceField
+		value string
+		want  string
+		err   error
+	}{
+		{account, " 12345 ", "12345", nil},
+		{account, "1234", "", ErrInvalidValue},
+		{account, "1234567", "", ErrInvalidValue},
+		{account, "12a45", "", ErrInvalidValue},
+		{account, "   ", "", ErrMissingValue},
+		{phone, "+998 (90) 123-45-67", "998901234567", nil},
+		{phone, "901234567", "901234567", nil},
+		{phone, "12345678", "", ErrInvalidValue},
+		{phone, "+998 90 123 45 678", "", ErrInvalidValue},
+		{phone, "90-123-45-6x", "", ErrInvalidValue},
+		{comment, "привет", "", ErrInvalidValue},
+		{comment, "мир", "мир", nil},
+		{comment, "hi5", "", ErrInvalidValue},
+		{comment, "", "", ErrMissingValue},
+	}
+	for _, test := range tests {
+		got, err := test.field.Validate(test.value)
+		if got != test.want || err != test.err {
+			t.Errorf("%s Validate(%q) = %q, %v, want %q, %v", test.field.Name, test.value, got, err, test.want, test.err)
+		}
+	}
+}
+
+func TestFillFields(t *testing.T) {
+	fields := []ServiceField{
+		{Name: "account", Kind: FieldDigits},
+		{Name: "phone", Kind: FieldPhone},
+	}
+	filled, err := fillFields(fields, []PaymentDetail{{" Phone", "+998901234567"}, {"ACCOUNT", "42"}})
+	if err != nil || len(filled) != 2 || filled[0] != (PaymentDetail{"account", "42"}) ||
+		filled[1] != (PaymentDetail{"phone", "998901234567"}) {
+		t.Errorf("fillFields() = %+v, %v, want the values in the order of the fields", filled, err)
+	}
+
+	tests := []struct {
+		details []PaymentDetail
+		field   string
+		err     error
+	}{
+		{[]PaymentDetail{{"account", "42"}}, "phone", ErrMissingValue},
+		{[]PaymentDetail{{"account", "4x"}, {"phone", "998901234567"}}, "account", ErrInvalidValue},
+		{[]PaymentDetail{{"account", "42"}, {"phone", "998901234567"}, {"comment", "hi"}}, "comment", ErrUnknownField},
+	}
+	for _, test := range tests {
+		_, err = fillFields(fields, test.details)
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field.Name != test.field || !errors.Is(err, test.err) {
+			t.Errorf("fillFields(%+v) = %v, want %v of %s", test.details, err, test.err, test.field)
+		}
+	}
+	if filled, err = fillFields(nil, nil); err != nil || len(filled) != 0 {
+		t.Errorf("fillFields() of a service without fields = %+v, %v", filled, err)
+	}
+}
+
+func TestPayForService(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	accountId := addTestClient(t, "ivan", 1, 10000, db)
+	service := Service{Name: "internet", Category: "home", Fields: []ServiceField{{Name: "contract", Kind: FieldDigits}}}
+	if err := AddService(service, db); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddService(service, db); err != core.ErrServiceExist {
+		t.Errorf("AddService() again = %v, want %v", err, core.ErrServiceExist)
+	}
+	if err := AddService(Service{Name: "tv", Fields: []ServiceField{{Name: "id", Kind: "any"}}}, db); err != ErrInvalidField {
+		t.Errorf("AddService() with an invalid field = %v, want %v", err, ErrInvalidField)
+	}
+
+	err := PayForService("internet", []PaymentDetail{{"contract", "x1"}}, accountId, "ivan", 1000, db)
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("PayForService() with an invalid value = %v, want %v", err, ErrInvalidValue)
+	}
+	if err = PayForService("water", nil, accountId, "ivan", 1000, db); err != core.ErrServiceNotExist {
+		t.Errorf("PayForService() of an unknown service = %v, want %v", err, core.ErrServiceNotExist)
+	}
+	if err = PayForService("internet", []PaymentDetail{{"contract", "123"}}, accountId, "ivan", 1000, db); err != nil {
+		t.Fatal(err)
+	}
+	if balance := balances(t, "ivan", db); balance[0] != 9000 {
+		t.Errorf("balance after the payment = %s, want 90.00", balance[0])
+	}
+	statement, err := GetStatement(accountId, date("2000-01-01"), date("2100-01-01"), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paid := statement.Operations[len(statement.Operations)-1]
+	if paid.Counterparty != "internet" || len(paid.Details) != 1 || paid.Details[0] != (PaymentDetail{"contract", "123"}) {
+		t.Errorf("payment in the statement = %+v", paid)
+	}
+}
@@ -0,0 +1,472 @@
+package bank
+
+const getAccountSQL = `SELECT client_id, balance
+FROM accounts
+WHERE id = ?;`
+
+const lastClientAccountIdSQL = `SELECT MAX(id)
+FROM accounts
+WHERE client_id = ?;`
+
+const getClientNameByAccountIdSQL = `SELECT clients.name
+FROM clients
+         JOIN accounts ON accounts.client_id = clients.id
+WHERE accounts.id = ?;`
+
+const getClientNameByPhoneNumberSQL = `SELECT name
+FROM clients
+WHERE phone_number = ?;`
+
+const payeesDDL = `CREATE TABLE IF NOT EXISTS payees
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    login           TEXT    NOT NULL,
+    name            TEXT    NOT NULL,
+    account_id      INTEGER NOT NULL DEFAULT 0,
+    phone_number    INTEGER NOT NULL DEFAULT 0,
+    from_account_id INTEGER NOT NULL DEFAULT 0,
+    amount          INTEGER NOT NULL DEFAULT 0 check ( amount >= 0 ),
+    UNIQUE (login, name)
+);`
+
+const addPayeeSQL = `INSERT INTO payees(login, name, account_id, phone_number, from_account_id, amount)
+VALUES (:login, :name, :account_id, :phone_number, :from_account_id, :amount);`
+
+const payeeExistSQL = `SELECT id
+FROM payees
+WHERE login = ?
+  AND name = ?;`
+
+const getPayeesSQL = `SELECT id, login, name, account_id, phone_number, from_account_id, amount
+FROM payees
+WHERE login = ?
+ORDER BY name;`
+
+const renamePayeeSQL = `UPDATE payees
+SET name = :name
+WHERE id = :id
+  AND login = :login;`
+
+const deletePayeeSQL = `DELETE
+FROM payees
+WHERE id = ?
+  AND login = ?;`
+
+const scheduledTransfersDDL = `CREATE TABLE IF NOT EXISTS scheduled_transfers
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    login           TEXT    NOT NULL,
+    from_account_id INTEGER NOT NULL,
+    account_id      INTEGER NOT NULL DEFAULT 0,
+    phone_number    INTEGER NOT NULL DEFAULT 0,
+    amount          INTEGER NOT NULL check ( amount > 0 ),
+    period          TEXT    NOT NULL,
+    interval_days   INTEGER NOT NULL DEFAULT 0,
+    month_day       INTEGER NOT NULL,
+    next_date       TEXT    NOT NULL,
+    status          TEXT    NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    retry_at        TEXT    NOT NULL DEFAULT ''
+);`
+
+const scheduledRunsDDL = `CREATE TABLE IF NOT EXISTS scheduled_runs
+(
+    id                    INTEGER PRIMARY KEY AUTOINCREMENT,
+    scheduled_transfer_id INTEGER NOT NULL REFERENCES scheduled_transfers,
+    due_date              TEXT    NOT NULL,
+    run_at                TEXT    NOT NULL,
+    attempt               INTEGER NOT NULL,
+    status                TEXT    NOT NULL,
+    error                 TEXT    NOT NULL DEFAULT '',
+    UNIQUE (scheduled_transfer_id, due_date, attempt)
+);`
+
+const addScheduledTransferSQL = `INSERT INTO scheduled_transfers(login, from_account_id, account_id, phone_number, amount, period,
+                                interval_days, month_day, next_date, status)
+VALUES (:login, :from_account_id, :account_id, :phone_number, :amount, :period, :interval_days, :month_day, :next_date,
+        :status);`
+
+const lastScheduledTransferIdSQL = `SELECT MAX(id)
+FROM scheduled_transfers
+WHERE login = ?;`
+
+const scheduledTransferColumns = `id, login, from_account_id, account_id, phone_number, amount, period, interval_days, month_day,
+       next_date, status, attempts,
+       COALESCE((SELECT error
+                 FROM scheduled_runs
+                 WHERE scheduled_transfer_id = scheduled_transfers.id
+                 ORDER BY scheduled_runs.id DESC
+                 LIMIT 1), '')`
+
+const getScheduledTransfersSQL = `SELECT ` + scheduledTransferColumns + `
+FROM scheduled_transfers
+WHERE login = ?
+ORDER BY id;`
+
+const getDueScheduledTransfersSQL = `SELECT ` + scheduledTransferColumns + `
+FROM scheduled_transfers
+WHERE status = 'active'
+  AND next_date <= ?
+  AND retry_at <= ?
+ORDER BY next_date, id;`
+
+const cancelScheduledTransferSQL = `UPDATE scheduled_transfers
+SET status = 'cancelled'
+WHERE id = :id
+  AND login = :login
+  AND status = 'active';`
+
+const updateScheduledTransferSQL = `UPDATE scheduled_transfers
+SET next_date = :next_date,
+    status    = :status,
+    attempts  = :attempts,
+    retry_at  = :retry_at
+WHERE id = :id
+  AND next_date = :due_date
+  AND attempts = :previous_attempts
+  AND status = 'active';`
+
+const addScheduledRunSQL = `INSERT INTO scheduled_runs(scheduled_transfer_id, due_date, run_at, attempt, status, error)
+VALUES (:scheduled_transfer_id, :due_date, :run_at, :attempt, :status, :error);`
+
+// accountOperationsDDL keeps the operations of the accounts, journal_id is set on the ones moved
+// from the core journal.
+const accountOperationsDDL = `CREATE TABLE IF NOT EXISTS account_operations
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id   INTEGER NOT NULL REFERENCES accounts,
+    date         TEXT    NOT NULL,
+    type         TEXT    NOT NULL,
+    counterparty TEXT    NOT NULL,
+    amount       INTEGER NOT NULL,
+    balance      INTEGER NOT NULL,
+    journal_id   INTEGER
+);`
+
+const addAccountOperationSQL = `INSERT INTO account_operations(account_id, date, type, counterparty, amount, balance)
+VALUES (:account_id, :date, :type, :counterparty, :amount, :balance);`
+
+const lastOperationIdSQL = `SELECT COALESCE(MAX(id), 0)
+FROM account_operations;`
+
+const lastMovedJournalIdSQL = `SELECT COALESCE(MAX(journal_id), 0)
+FROM account_operations;`
+
+// legacyDateSQL turns the dates of the core journal, as in 01-31-2020 15:04:05, into OperationDateFormat.
+const legacyDateSQL = `substr(j.date, 7, 4) || '-' || substr(j.date, 1, 2) || '-' || substr(j.date, 4, 2) || substr(j.date, 11)`
+
+// firstAccountSQL is the first account of the client of the journal row j, the one core
+// credits the transfers by phone number to.
+const firstAccountSQL = `(SELECT MIN(id) FROM accounts WHERE client_id = j.client_id)`
+
+// moveJournalDebitsSQL copies the journal rows after the given id as debits of the first account
+// of their client, the core journal doesn't keep the account debited.
+const moveJournalDebitsSQL = `INSERT INTO account_operations(account_id, date, type, counterparty, amount, balance, journal_id)
+SELECT ` + firstAccountSQL + `, ` + legacyDateSQL + `, j.type, j.transferred_to, -CAST(ROUND(j.amount) AS INTEGER), 0, j.id
+FROM journal j
+WHERE j.id > ?
+  AND ` + firstAccountSQL + ` IS NOT NULL
+ORDER BY j.id;`
+
+// moveJournalCreditsSQL copies the transfers of the journal after the given id as credits of the
+// first account of the client with the phone number transferred to, or of the account with that id.
+const moveJournalCreditsSQL = `INSERT INTO account_operations(account_id, date, type, counterparty, amount, balance, journal_id)
+SELECT t.account_id, t.date, t.type, t.counterparty, t.amount, 0, t.journal_id
+FROM (SELECT COALESCE((SELECT MIN(a.id)
+                       FROM accounts a
+                                JOIN clients c ON c.id = a.client_id
+                       WHERE CAST(c.phone_number AS TEXT) = j.transferred_to),
+                      (SELECT a.id FROM accounts a WHERE CAST(a.id AS TEXT) = j.transferred_to)) AS account_id,
+             ` + legacyDateSQL + ` AS date,
+             j.type,
+             COALESCE(CAST(` + firstAccountSQL + ` AS TEXT), '') AS counterparty,
+             CAST(ROUND(j.amount) AS INTEGER) AS amount,
+             j.id AS journal_id
+      FROM journal j
+      WHERE j.id > ?
+        AND j.type = 'transfer') t
+WHERE t.account_id IS NOT NULL
+ORDER BY t.journal_id;`
+
+// setMovedBalancesSQL counts the balances of the operations after the given id back from the
+// current balances of their accounts.
+const setMovedBalancesSQL = `UPDATE account_operations
+SET balance = (SELECT balance FROM accounts WHERE accounts.id = account_operations.account_id) -
+              (SELECT COALESCE(SUM(l.amount), 0)
+               FROM account_operations l
+               WHERE l.account_id = account_operations.account_id
+                 AND (l.date > account_operations.date OR (l.date = account_operations.date AND l.id > account_operations.id)))
+WHERE id > ?;`
+
+const getAccountBalanceSQL = `SELECT balance
+FROM accounts
+WHERE id = ?;`
+
+const lastAccountOperationIdSQL = `SELECT MAX(id)
+FROM account_operations
+WHERE account_id = ?;`
+
+const sumAccountOperationsSinceSQL = `SELECT COALESCE(SUM(amount), 0)
+FROM account_operations
+WHERE account_id = ?
+  AND date >= ?;`
+
+// operationColumns are read by queryOperations from account_operations o, the currency and
+// the exchange are empty when the account has no currency or the operation no exchange.
+const operationColumns = `o.id, o.account_id, o.date, o.type, o.counterparty, o.amount, o.balance,
+       COALESCE(ac.currency, ''), COALESCE(x.currency, ''), COALESCE(x.amount, 0), COALESCE(x.rate, 0)`
+
+const operationJoins = `
+         LEFT JOIN account_currencies ac ON ac.account_id = o.account_id
+         LEFT JOIN account_exchanges x ON x.operation_id = o.id`
+
+const getAccountOperationsSQL = `SELECT ` + operationColumns + `
+FROM account_operations o` + operationJoins + `
+WHERE o.account_id = ?
+  AND o.date >= ?
+  AND o.date < ?
+ORDER BY o.date, o.id;`
+
+// journalFilterSQL selects the operations of all the accounts of a client, the empty
+// and zero parameters match everything.
+const journalFilterSQL = `
+FROM account_operations o
+         JOIN accounts a ON a.id = o.account_id
+         JOIN clients c ON c.id = a.client_id` + operationJoins + `
+WHERE c.login = :login
+  AND (:from = '' OR o.date >= :from)
+  AND (:to = '' OR o.date < :to)
+  AND (:type = ''
+    OR (:type = 'incoming' AND o.type = 'transfer' AND o.amount > 0)
+    OR (:type = 'transfer' AND o.type = 'transfer' AND o.amount < 0)
+    OR (:type <> 'incoming' AND :type <> 'transfer' AND o.type = :type))
+  AND (:min_amount = 0 OR ABS(o.amount) >= :min_amount)
+  AND (:max_amount = 0 OR ABS(o.amount) <= :max_amount)
+  AND (:counterparty = '' OR (o.type <> 'service' AND o.counterparty = :counterparty))
+  AND (:service = '' OR (o.type = 'service' AND LOWER(o.counterparty) LIKE LOWER(:service)))
+  AND (:text = '' OR LOWER(o.counterparty || ' ' || o.date) LIKE LOWER(:text))`
+
+// getJournalSQL takes the ORDER BY list, made of the known columns only.
+const getJournalSQL = `SELECT ` + operationColumns + journalFilterSQL + `
+ORDER BY %s
+LIMIT :limit OFFSET :offset;`
+
+const countJournalSQL = `SELECT COUNT(*)` + journalFilterSQL + `;`
+
+const getJournalTotalsSQL = `SELECT COALESCE(ac.currency, ''),
+       COALESCE(SUM(CASE WHEN o.amount > 0 THEN o.amount ELSE 0 END), 0),
+       COALESCE(SUM(CASE WHEN o.amount < 0 THEN -o.amount ELSE 0 END), 0)` + journalFilterSQL + `
+GROUP BY COALESCE(ac.currency, '');`
+
+// getClientsSQL takes the ORDER BY list, made of the known columns only.
+const getClientsSQL = `SELECT id, name, login, phone_number, status
+FROM clients
+ORDER BY %s
+LIMIT ? OFFSET ?;`
+
+const countClientsSQL = `SELECT COUNT(*)
+FROM clients;`
+
+const searchClientsByNameSQL = `SELECT id, name, login, phone_number, status
+FROM clients
+WHERE name LIKE ?
+ORDER BY id;`
+
+const searchClientsByPhoneNumberSQL = `SELECT id, name, login, phone_number, status
+FROM clients
+WHERE phone_number = ?
+ORDER BY id;`
+
+const getClientRecordsSQL = `SELECT c.id, c.name, c.login, c.password, COALESCE(p.salt, ''), c.phone_number, c.status
+FROM clients c
+         LEFT JOIN client_passwords p ON p.login = c.login
+ORDER BY c.id;`
+
+// deleteClientSaltSQL and addClientSaltSQL write the client_passwords of auth on import.
+const deleteClientSaltSQL = `DELETE
+FROM client_passwords
+WHERE login = ?;`
+
+const addClientSaltSQL = `INSERT INTO client_passwords(login, salt, revoked_before)
+VALUES (:login, :salt, :revoked_before);`
+
+const accountCurrenciesDDL = `CREATE TABLE IF NOT EXISTS account_currencies
+(
+    account_id INTEGER PRIMARY KEY REFERENCES accounts,
+    currency   TEXT NOT NULL
+);`
+
+// exchangeRatesDDL keeps the prices of a unit of the currencies in money.BaseCurrency, in millionths.
+const exchangeRatesDDL = `CREATE TABLE IF NOT EXISTS exchange_rates
+(
+    currency TEXT PRIMARY KEY,
+    rate     INTEGER NOT NULL check ( rate > 0 ),
+    date     TEXT    NOT NULL
+);`
+
+// accountExchangesDDL records the other side of the account operations of transfers between
+// currencies: its currency and amount, and the price of a unit of the debited currency in the
+// credited one, in millionths.
+const accountExchangesDDL = `CREATE TABLE IF NOT EXISTS account_exchanges
+(
+    operation_id INTEGER PRIMARY KEY REFERENCES account_operations,
+    currency     TEXT    NOT NULL,
+    amount       INTEGER NOT NULL,
+    rate         INTEGER NOT NULL
+);`
+
+const addAccountCurrencySQL = `INSERT INTO account_currencies(account_id, currency)
+VALUES (?, ?);`
+
+const getAccountCurrencySQL = `SELECT COALESCE((SELECT currency FROM account_currencies WHERE account_id = ?), '');`
+
+const deleteAccountCurrencySQL = `DELETE
+FROM account_currencies
+WHERE account_id = ?;`
+
+const getAccountRecordsSQL = `SELECT a.id, a.client_id, a.balance, COALESCE(ac.currency, '')
+FROM accounts a
+         LEFT JOIN account_currencies ac ON ac.account_id = a.id
+ORDER BY a.id;`
+
+const deleteExchangeRateSQL = `DELETE
+FROM exchange_rates
+WHERE currency = ?;`
+
+const addExchangeRateSQL = `INSERT INTO exchange_rates(currency, rate, date)
+VALUES (:currency, :rate, :date);`
+
+const getExchangeRateSQL = `SELECT rate
+FROM exchange_rates
+WHERE currency = ?;`
+
+const getExchangeRatesSQL = `SELECT currency, rate, date
+FROM exchange_rates
+ORDER BY currency;`
+
+const addAccountExchangeSQL = `INSERT INTO account_exchanges(operation_id, currency, amount, rate)
+VALUES (:operation_id, :currency, :amount, :rate);`
+
+const getClientAccountsSQL = `SELECT a.id, a.balance, COALESCE(ac.currency, ''), COALESCE(n.nickname, ''), d.login IS NOT NULL
+FROM accounts a
+         JOIN clients c ON c.id = a.client_id
+         LEFT JOIN account_currencies ac ON ac.account_id = a.id
+         LEFT JOIN account_nicknames n ON n.account_id = a.id
+         LEFT JOIN default_accounts d ON d.login = c.login AND d.account_id = a.id
+WHERE c.login = ?
+ORDER BY a.id;`
+
+const accountNicknamesDDL = `CREATE TABLE IF NOT EXISTS account_nicknames
+(
+    account_id INTEGER PRIMARY KEY REFERENCES accounts,
+    nickname   TEXT NOT NULL
+);`
+
+// defaultAccountsDDL keeps the account the transfers and payments of a client start from.
+const defaultAccountsDDL = `CREATE TABLE IF NOT EXISTS default_accounts
+(
+    login      TEXT PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts
+);`
+
+const deleteAccountNicknameSQL = `DELETE
+FROM account_nicknames
+WHERE account_id = ?;`
+
+const addAccountNicknameSQL = `INSERT INTO account_nicknames(account_id, nickname)
+VALUES (?, ?);`
+
+const deleteDefaultAccountSQL = `DELETE
+FROM default_accounts
+WHERE login = ?;`
+
+const addDefaultAccountSQL = `INSERT INTO default_accounts(login, account_id)
+VALUES (?, ?);`
+
+const serviceCategoriesDDL = `CREATE TABLE IF NOT EXISTS service_categories
+(
+    service_id INTEGER PRIMARY KEY REFERENCES services,
+    category   TEXT NOT NULL
+);`
+
+// serviceFieldsDDL keeps the details a client enters to pay for a service, in the order they are asked.
+const serviceFieldsDDL = `CREATE TABLE IF NOT EXISTS service_fields
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    service_id INTEGER NOT NULL REFERENCES services,
+    position   INTEGER NOT NULL,
+    name       TEXT    NOT NULL,
+    kind       TEXT    NOT NULL,
+    min_length INTEGER NOT NULL DEFAULT 0,
+    max_length INTEGER NOT NULL DEFAULT 0,
+    pattern    TEXT    NOT NULL DEFAULT '',
+    UNIQUE (service_id, name)
+);`
+
+const operationDetailsDDL = `CREATE TABLE IF NOT EXISTS operation_details
+(
+    operation_id INTEGER NOT NULL REFERENCES account_operations,
+    position     INTEGER NOT NULL,
+    name         TEXT    NOT NULL,
+    value        TEXT    NOT NULL,
+    PRIMARY KEY (operation_id, position)
+);`
+
+const getServiceIdSQL = `SELECT id
+FROM services
+WHERE name = ?;`
+
+// getServicesSQL lists the services by category, the ones without a category go last.
+const getServicesSQL = `SELECT s.id, s.name, COALESCE(c.category, '')
+FROM services s
+         LEFT JOIN service_categories c ON c.service_id = s.id
+ORDER BY COALESCE(c.category, '') = '', COALESCE(c.category, ''), s.name;`
+
+const getServiceFieldsSQL = `SELECT service_id, name, kind, min_length, max_length, pattern
+FROM service_fields
+ORDER BY service_id, position;`
+
+const getFieldsOfServiceSQL = `SELECT service_id, name, kind, min_length, max_length, pattern
+FROM service_fields
+WHERE service_id = ?
+ORDER BY position;`
+
+const addServiceCategorySQL = `INSERT INTO service_categories(service_id, category)
+VALUES (?, ?);`
+
+const addServiceFieldSQL = `INSERT INTO service_fields(service_id, position, name, kind, min_length, max_length, pattern)
+VALUES (:service_id, :position, :name, :kind, :min_length, :max_length, :pattern);`
+
+const addOperationDetailSQL = `INSERT INTO operation_details(operation_id, position, name, value)
+VALUES (:operation_id, :position, :name, :value);`
+
+const getOperationDetailsSQL = `SELECT name, value
+FROM operation_details
+WHERE operation_id = ?
+ORDER BY position;`
+
+// atmDetailsDDL keeps where an ATM stands in degrees, the time it opens and closes at as
+// HH:MM and its features separated by commas.
+const atmDetailsDDL = `CREATE TABLE IF NOT EXISTS atm_details
+(
+    atm_id    INTEGER PRIMARY KEY REFERENCES atms,
+    latitude  DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    opens     TEXT NOT NULL DEFAULT '',
+    closes    TEXT NOT NULL DEFAULT '',
+    features  TEXT NOT NULL DEFAULT ''
+);`
+
+const getAtmIdSQL = `SELECT id
+FROM atms
+WHERE location = ?;`
+
+const setAtmDetailsSQL = `INSERT OR
+REPLACE INTO atm_details (atm_id, latitude, longitude, opens, closes, features)
+VALUES (:atm_id, :latitude, :longitude, :opens, :closes, :features);`
+
+const getATMsSQL = `SELECT a.id, a.name, a.location, d.latitude, d.longitude,
+       COALESCE(d.opens, ''), COALESCE(d.closes, ''), COALESCE(d.features, '')
+FROM atms a
+         LEFT JOIN atm_details d ON d.atm_id = a.id
+ORDER BY a.id;`
@@ -0,0 +1,147 @@
+package bank
+
+import (
+	"database/sql"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"time"
+)
+
+// Deposit is the operation putting the opening balance on a new account.
+const Deposit = "deposit"
+
+// OperationDateFormat is the format of the dates of account operations, it keeps them ordered as text.
+const OperationDateFormat = "2006-01-02 15:04:05"
+
+// Operation is a change of the balance of an account. Amount is negative for debits,
+// Counterparty is the target of outgoing transfers, the source account of incoming ones
+// and the name of a paid service.
+type Operation struct {
+	Id           int64
+	AccountId    int64
+	Date         time.Time
+	Type         string
+	Counterparty string
+	Amount       money.Money
+	Balance      money.Money
+	// Currency is the one of the account, Exchange is set for transfers between currencies.
+	Currency money.Currency
+	Exchange Exchange
+	// Details are the values entered with a service payment.
+	Details []PaymentDetail
+}
+
+func (receiver Operation) Incoming() bool {
+	return receiver.Amount > 0
+}
+
+type Statement struct {
+	AccountId  int64
+	Currency   money.Currency
+	From       time.Time
+	To         time.Time
+	Opening    money.Money
+	Closing    money.Money
+	TotalIn    money.Money
+	TotalOut   money.Money
+	Operations []Operation
+}
+
+// GetStatement lists the operations of accountId from the start of from to the end of to.
+// The balances are counted back from the current one, so they stay right for periods
+// older than the operations kept.
+func GetStatement(accountId int64, from, to time.Time, db *sql.DB) (statement Statement, err error) {
+	from = startOfDay(from)
+	end := startOfDay(to).AddDate(0, 0, 1)
+	statement = Statement{AccountId: accountId, From: from, To: startOfDay(to)}
+
+	err = inTx(db, func(tx *sql.Tx) error {
+		var balance, sinceFrom, sinceEnd int64
+		err := tx.QueryRow(getAccountBalanceSQL, accountId).Scan(&balance)
+		if err != nil {
+			return err
+		}
+		statement.Currency, err = accountCurrency(tx, accountId)
+		if err != nil {
+			return err
+		}
+		err = tx.QueryRow(sumAccountOperationsSinceSQL, accountId, from.Format(OperationDateFormat)).Scan(&sinceFrom)
+		if err != nil {
+			return err
+		}
+		err = tx.QueryRow(sumAccountOperationsSinceSQL, accountId, end.Format(OperationDateFormat)).Scan(&sinceEnd)
+		if err != nil {
+			return err
+		}
+		statement.Opening = money.FromKopecks(balance - sinceFrom)
+		statement.Closing = money.FromKopecks(balance - sinceEnd)
+
+		statement.Operations, err = queryOperations(tx, getAccountOperationsSQL,
+			accountId, from.Format(OperationDateFormat), end.Format(OperationDateFormat))
+		if err != nil {
+			return err
+		}
+		return queryDetails(tx, statement.Operations)
+	})
+	for _, operation := range statement.Operations {
+		if operation.Incoming() {
+			statement.TotalIn += operation.Amount
+		} else {
+			statement.TotalOut -= operation.Amount
+		}
+	}
+	return statement, err
+}
+
+func addOperation(tx *sql.Tx, accountId int64, date time.Time, operation, counterparty string, amount money.Money) error {
+	var balance int64
+	err := tx.QueryRow(getAccountBalanceSQL, accountId).Scan(&balance)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		addAccountOperationSQL,
+		sql.Named("account_id", accountId),
+		sql.Named("date", date.Format(OperationDateFormat)),
+		sql.Named("type", operation),
+		sql.Named("counterparty", counterparty),
+		sql.Named("amount", amount.Kopecks()),
+		sql.Named("balance", balance),
+	)
+	return err
+}
+
+func queryOperations(tx *sql.Tx, query string, args ...interface{}) (operations []Operation, err error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for rows.Next() {
+		var operation Operation
+		var date, currency, exchangeCurrency string
+		var amount, balance, exchangeAmount, exchangeRate int64
+		err = rows.Scan(&operation.Id, &operation.AccountId, &date, &operation.Type, &operation.Counterparty, &amount, &balance,
+			&currency, &exchangeCurrency, &exchangeAmount, &exchangeRate)
+		if err != nil {
+			return nil, err
+		}
+		operation.Currency = orDefault(currency)
+		operation.Exchange = Exchange{
+			Currency: money.Currency(exchangeCurrency),
+			Amount:   money.FromKopecks(exchangeAmount),
+			Rate:     money.Rate(exchangeRate),
+		}
+		operation.Date, err = time.ParseInLocation(OperationDateFormat, date, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		operation.Amount, operation.Balance = money.FromKopecks(amount), money.FromKopecks(balance)
+		operations = append(operations, operation)
+	}
+	return operations, rows.Err()
+}
@@ -0,0 +1,130 @@
+package bank
+
+import (
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/money"
+	"github.com/JAbduvohidov/apm-ibank-core/pkg/core"
+	"testing"
+)
+
+func TestGetStatement(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	accountId := addTestClient(t, "ivan", 1, 10000, db)
+	otherId := addTestClient(t, "anna", 2, 1000, db)
+	for _, err := range []error{
+		TransferToByAccountId(otherId, "ivan", accountId, 1000, db),
+		TransferToByAccountId(otherId, "ivan", accountId, 500, db),
+		TransferToByAccountId(accountId, "anna", otherId, 200, db),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The deposit and the transfers are moved back to known dates.
+	for i, moment := range []string{"2020-01-01 10:00:00", "2020-01-10 00:00:00", "2020-01-20 23:59:59", "2020-02-01 09:30:00"} {
+		_, err := db.Exec(`UPDATE account_operations SET date = ?
+WHERE id = (SELECT id FROM account_operations WHERE account_id = ? ORDER BY id LIMIT 1 OFFSET ?)`, moment, accountId, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		from, to   string
+		opening    int64
+		closing    int64
+		totalIn    int64
+		totalOut   int64
+		operations int
+	}{
+		{"2019-12-01", "2019-12-31", 0, 0, 0, 0, 0},
+		{"2020-01-01", "2020-01-01", 0, 10000, 10000, 0, 1},
+		{"2020-01-10", "2020-01-20", 10000, 8500, 0, 1500, 2},
+		{"2020-01-21", "2020-01-31", 8500, 8500, 0, 0, 0},
+		{"2020-01-01", "2020-02-29", 0, 8700, 10200, 1500, 4},
+		{"2020-03-01", "2020-03-31", 8700, 8700, 0, 0, 0},
+	}
+	for _, test := range tests {
+		statement, err := GetStatement(accountId, date(test.from), date(test.to), db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if statement.Opening.Kopecks() != test.opening || statement.Closing.Kopecks() != test.closing ||
+			statement.TotalIn.Kopecks() != test.totalIn || statement.TotalOut.Kopecks() != test.totalOut ||
+			len(statement.Operations) != test.operations {
+			t.Errorf("GetStatement(%s, %s) = %s..%s, in %s, out %s, %d operations, want %d..%d, in %d, out %d, %d operations",
+				test.from, test.to, statement.Opening, statement.Closing, statement.TotalIn, statement.TotalOut,
+				len(statement.Operations), test.opening, test.closing, test.totalIn, test.totalOut, test.operations)
+		}
+		if statement.Opening+statement.TotalIn-statement.TotalOut != statement.Closing {
+			t.Errorf("GetStatement(%s, %s) totals don't add up to the closing balance", test.from, test.to)
+		}
+	}
+}
+
+func TestGetStatementOfMovedJournal(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+	for _, err := range []error{
+		core.AddClient("payer", "payer", "secret", 1, db),
+		core.AddClient("payee", "payee", "secret", 2, db),
+		core.AddAccount(1, 100, db),
+		core.AddAccount(2, 0, db),
+		core.AddService("Mobile", db),
+		core.TransferToByAccountId(2, "payer", 1, 12.5, db),
+		core.TransferToByPhoneNumber(2, "payer", 1, 2.5, db),
+		core.PayForService("Mobile", 1, "payer", 1, db),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := db.Exec(`UPDATE journal SET date = '01-15-2020 10:00:0' || id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = Init(db); err != nil {
+		t.Fatal(err)
+	}
+	if err = TransferToByAccountId(2, "payer", 1, 300, db); err != nil {
+		t.Fatal(err)
+	}
+
+	statement, err := GetStatement(1, date("2020-01-01"), date("2100-01-01"), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statement.Opening != 10000 || statement.Closing != 8100 || len(statement.Operations) != 4 {
+		t.Fatalf("statement of the payer = %s..%s with %+v", statement.Opening, statement.Closing, statement.Operations)
+	}
+	wants := []struct {
+		kind, counterparty string
+		amount, balance    money.Money
+	}{
+		{core.Transfer, "2", -1250, 8750},
+		{core.Transfer, "2", -250, 8500},
+		{core.Service, "Mobile", -100, 8400},
+		{core.Transfer, "2", -300, 8100},
+	}
+	for idx, want := range wants {
+		operation := statement.Operations[idx]
+		if operation.Kind() != want.kind || operation.Counterparty != want.counterparty ||
+			operation.Amount != want.amount || operation.Balance != want.balance {
+			t.Errorf("operation %d of the payer = %+v, want %+v", idx+1, operation, want)
+		}
+	}
+	if date := statement.Operations[0].Date.Format(OperationDateFormat); date != "2020-01-15 10:00:01" {
+		t.Errorf("date of the moved operation = %s, want 2020-01-15 10:00:01", date)
+	}
+
+	statement, err = GetStatement(2, date("2020-01-15"), date("2020-01-15"), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statement.Opening != 0 || statement.Closing != 1500 || len(statement.Operations) != 2 ||
+		statement.Operations[0].Kind() != Incoming || statement.Operations[0].Counterparty != "1" ||
+		statement.Operations[1].Balance != 1500 {
+		t.Errorf("statement of the payee on the day of the core transfers = %s..%s with %+v",
+			statement.Opening, statement.Closing, statement.Operations)
+	}
+}
@@ -0,0 +1,347 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/auth"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/logger"
+	"github.com/JAbduvohidov/apm-ibank-cli/pkg/storage"
+	"gopkg.in/yaml.v2"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	envPrefix = "IBANK_"
+	fileFlag  = "config"
+)
+
+// DefaultFiles are looked up in the working directory when no config file is given.
+var DefaultFiles = []string{"ibank.toml", "ibank.yaml", "ibank.yml", "ibank.json"}
+
+var (
+	ErrUnknownFormat = errors.New("unknown config file format")
+	ErrUnknownKey    = errors.New("unknown config keys")
+)
+
+// maskedPassword stands for the passwords in the dsn printed by Write.
+const maskedPassword = "xxxxx"
+
+// passwordRegexp finds the password in a key=value dsn, quoted or not.
+var passwordRegexp = regexp.MustCompile(`(?i)(\bpassword\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+type Config struct {
+	// File is the config file the settings were read from, empty if there was none.
+	File      string `json:"-" toml:"-" yaml:"-"`
+	Driver    string `json:"driver" toml:"driver" yaml:"driver"`
+	DSN       string `json:"dsn" toml:"dsn" yaml:"dsn"`
+	LogPath   string `json:"log_path" toml:"log_path" yaml:"log_path"`
+	LogLevel  string `json:"log_level" toml:"log_level" yaml:"log_level"`
+	LogFormat string `json:"log_format" toml:"log_format" yaml:"log_format"`
+	// LogMaxSize is in megabytes.
+	LogMaxSize int64 `json:"log_max_size" toml:"log_max_size" yaml:"log_max_size"`
+	// LogMaxAge is in days.
+	LogMaxAge     int64  `json:"log_max_age" toml:"log_max_age" yaml:"log_max_age"`
+	LogMaxBackups int64  `json:"log_max_backups" toml:"log_max_backups" yaml:"log_max_backups"`
+	PageSize      int64  `json:"page_size" toml:"page_size" yaml:"page_size"`
+	Language      string `json:"language" toml:"language" yaml:"language"`
+	ExportDir     string `json:"export_dir" toml:"export_dir" yaml:"export_dir"`
+	// SessionIdle and SessionMax are in minutes.
+	SessionIdle int64 `json:"session_idle" toml:"session_idle" yaml:"session_idle"`
+	SessionMax  int64 `json:"session_max" toml:"session_max" yaml:"session_max"`
+	// LoginMaxFailures failed logins within LoginWindow minutes lock the client,
+	// LoginDelay is the wait in seconds after the first failure, doubled after every next one.
+	LoginMaxFailures int64 `json:"login_max_failures" toml:"login_max_failures" yaml:"login_max_failures"`
+	LoginWindow      int64 `json:"login_window" toml:"login_window" yaml:"login_window"`
+	LoginDelay       int64 `json:"login_delay" toml:"login_delay" yaml:"login_delay"`
+}
+
+func Default() Config {
+	return Config{
+		Driver:           storage.SQLite,
+		DSN:              "db.sqlite",
+		LogLevel:         "info",
+		LogFormat:        logger.TextFormat,
+		LogMaxSize:       10,
+		LogMaxAge:        7,
+		LogMaxBackups:    5,
+		PageSize:         10,
+		ExportDir:        ".",
+		SessionIdle:      5,
+		SessionMax:       60,
+		LoginMaxFailures: 5,
+		LoginWindow:      15,
+		LoginDelay:       1,
+	}
+}
+
+type setting struct {
+	name  string
+	usage string
+	set   func(config *Config, value string) error
+}
+
+var settings = []setting{
+	{name: "driver", usage: "database driver: " + strings.Join(storage.Drivers, ", "), set: func(config *Config, value string) error {
+		config.Driver = value
+		return nil
+	}},
+	{name: "dsn", usage: "database data source name", set: func(config *Config, value string) error {
+		config.DSN = value
+		return nil
+	}},
+	{name: "log-path", usage: "log file path", set: func(config *Config, value string) error {
+		config.LogPath = value
+		return nil
+	}},
+	{name: "log-level", usage: "log level: " + strings.Join(logger.LevelNames, ", "), set: func(config *Config, value string) error {
+		config.LogLevel = value
+		return nil
+	}},
+	{name: "log-format", usage: "log format: " + strings.Join(logger.Formats, ", "), set: func(config *Config, value string) error {
+		config.LogFormat = value
+		return nil
+	}},
+	{name: "log-max-size", usage: "log size in megabytes that starts a new file, 0 for no limit", set: func(config *Config, value string) (err error) {
+		config.LogMaxSize, err = strconv.ParseInt(value, 10, 64)
+		return err
+	}},
+	{name: "log-max-age", usage: "log age in days that starts a new file, 0 for no limit", set: func(config *Config, value string) (err error) {
+		config.LogMaxAge, err = strconv.ParseInt(value, 10, 64)
+		return err
+	}},
+	{name: "log-max-backups", usage: "number of old log files to keep", set: func(config *Config, value string) (err error) {
+		config.LogMaxBackups, err = strconv.ParseInt(value, 10, 64)
+		return err
+	}},
+	{name: "page-size", usage: "number of rows on a page", set: func(config *Config, value string) (err error) {
+		config.PageSize, err = strconv.ParseInt(value, 10, 64)
+		return err
+	}},
+	{name: "lang", usage: "interface language: ru, en, uz", set: func(config *Config, value string) error {
+		config.Language = value
+		return nil
+	}},
+	{name: "export-dir", usage: "directory for exported files", set: func(config *Config, value string) error {
+		config.ExportDir = value
+		return nil
+	}},
+	{name: "session-idle", usage: "minutes without input that end a client session, 0 for no limit", set: func(config *Config, value string) (err error) {
+		config.SessionIdle, err = strconv.ParseInt(value, 10, 64)
+		return err
+	}},
+	{name: "session-max", usage: "minutes a client session lasts at most, 0 for no limit", set: func(config *Config, value string) (err error) {
+		config.SessionMax, err = strconv.ParseInt(value, 10, 64)
+		return err
+	}},
+	{name: "login-max-failures", usage: "failed logins that lock the client, 0 for no limit", set: func(config *Config, value string) (err error) {
+		config.LoginMaxFailures, err = strconv.ParseInt(value, 10, 64)
+		return err
+	}},
+	{name: "login-window", usage: "minutes the failed logins are counted for", set: func(config *Config, value string) (err error) {
+		config.LoginWindow, err = strconv.ParseInt(value, 10, 64)
+		return err
+	}},
+	{name: "login-delay", usage: "seconds to wait after a failed login, doubled after every next one", set: func(config *Config, value string) (err error) {
+		config.LoginDelay, err = strconv.ParseInt(value, 10, 64)
+		return err
+	}},
+}
+
+// EnvName returns the environment variable overriding the setting with flag name.
+func EnvName(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// Flags registers the config file flag and a flag for every setting on flags.
+func Flags(flags *flag.FlagSet) {
+	flags.String(fileFlag, "", "config file (.toml, .json, .yaml), default $"+EnvName(fileFlag)+" or ibank.* in the working directory")
+	for _, item := range settings {
+		flags.String(item.name, "", item.usage+" (default $"+EnvName(item.name)+")")
+	}
+}
+
+// Load applies the config file, the IBANK_* environment variables and the flags set on
+// the command line on top of defaults, each source overriding the previous one.
+// flags must be parsed and have the flags registered by Flags.
+func Load(defaults Config, flags *flag.FlagSet) (config Config, err error) {
+	config = defaults
+	set := make(map[string]string)
+	flags.Visit(func(visited *flag.Flag) {
+		set[visited.Name] = visited.Value.String()
+	})
+
+	path, ok := set[fileFlag]
+	if !ok {
+		path = os.Getenv(EnvName(fileFlag))
+	}
+	if path == "" {
+		path = findDefaultFile()
+	}
+	if path != "" {
+		err = readFile(path, &config)
+		if err != nil {
+			return defaults, fmt.Errorf("can't read config %s: %w", path, err)
+		}
+		config.File = path
+	}
+
+	for _, item := range settings {
+		if value, ok := os.LookupEnv(EnvName(item.name)); ok {
+			if err = item.set(&config, value); err != nil {
+				return defaults, fmt.Errorf("invalid %s: %w", EnvName(item.name), err)
+			}
+		}
+	}
+	for _, item := range settings {
+		if value, ok := set[item.name]; ok {
+			if err = item.set(&config, value); err != nil {
+				return defaults, fmt.Errorf("invalid --%s: %w", item.name, err)
+			}
+		}
+	}
+
+	return config, config.validate()
+}
+
+func findDefaultFile() string {
+	for _, name := range DefaultFiles {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+func readFile(path string, config *Config) (err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		var meta toml.MetaData
+		meta, err = toml.Decode(string(data), config)
+		if undecoded := meta.Undecoded(); err == nil && len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for idx, key := range undecoded {
+				keys[idx] = key.String()
+			}
+			err = fmt.Errorf("%w: %s", ErrUnknownKey, strings.Join(keys, ", "))
+		}
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(config)
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, config)
+	default:
+		err = ErrUnknownFormat
+	}
+	return err
+}
+
+func (receiver Config) validate() error {
+	if !contains(storage.Drivers, receiver.Driver) {
+		return fmt.Errorf("driver must be one of %s, got %q", strings.Join(storage.Drivers, ", "), receiver.Driver)
+	}
+	if receiver.DSN == "" {
+		return errors.New("dsn is empty")
+	}
+	if receiver.LogPath == "" {
+		return errors.New("log_path is empty")
+	}
+	if receiver.PageSize <= 0 {
+		return fmt.Errorf("page_size must be positive, got %d", receiver.PageSize)
+	}
+	if _, err := logger.ParseLevel(receiver.LogLevel); err != nil {
+		return fmt.Errorf("log_level must be one of %s, got %q", strings.Join(logger.LevelNames, ", "), receiver.LogLevel)
+	}
+	if !contains(logger.Formats, receiver.LogFormat) {
+		return fmt.Errorf("log_format must be one of %s, got %q", strings.Join(logger.Formats, ", "), receiver.LogFormat)
+	}
+	if receiver.LogMaxSize < 0 || receiver.LogMaxAge < 0 || receiver.LogMaxBackups < 0 {
+		return errors.New("log_max_size, log_max_age and log_max_backups can't be negative")
+	}
+	if receiver.SessionIdle < 0 || receiver.SessionMax < 0 {
+		return errors.New("session_idle and session_max can't be negative")
+	}
+	if receiver.LoginMaxFailures < 0 || receiver.LoginWindow < 0 || receiver.LoginDelay < 0 {
+		return errors.New("login_max_failures, login_window and login_delay can't be negative")
+	}
+	return nil
+}
+
+func (receiver Config) LoggerOptions() logger.Options {
+	level, _ := logger.ParseLevel(receiver.LogLevel)
+	return logger.Options{
+		Path:       receiver.LogPath,
+		Level:      level,
+		Format:     receiver.LogFormat,
+		MaxSize:    receiver.LogMaxSize << 20,
+		MaxAge:     time.Duration(receiver.LogMaxAge) * 24 * time.Hour,
+		MaxBackups: int(receiver.LogMaxBackups),
+	}
+}
+
+// SessionLimits returns SessionIdle and SessionMax as durations.
+func (receiver Config) SessionLimits() (idle, max time.Duration) {
+	return time.Duration(receiver.SessionIdle) * time.Minute, time.Duration(receiver.SessionMax) * time.Minute
+}
+
+func (receiver Config) LoginPolicy() auth.Policy {
+	return auth.Policy{
+		MaxFailures: receiver.LoginMaxFailures,
+		Window:      time.Duration(receiver.LoginWindow) * time.Minute,
+		Delay:       time.Duration(receiver.LoginDelay) * time.Second,
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Write prints the settings in TOML, so the output can be used as a config file once the
+// password masked in the dsn is filled in.
+func (receiver Config) Write(writer io.Writer) error {
+	receiver.DSN = MaskDSN(receiver.DSN)
+	if receiver.File != "" {
+		_, err := fmt.Fprintf(writer, "# %s\n", receiver.File)
+		if err != nil {
+			return err
+		}
+	}
+	return toml.NewEncoder(writer).Encode(receiver)
+}
+
+// MaskDSN hides the password in dsn, given as a URL or as key=value pairs.
+func MaskDSN(dsn string) string {
+	if parsed, err := url.Parse(dsn); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+		if _, ok := parsed.User.Password(); ok {
+			parsed.User = url.UserPassword(parsed.User.Username(), maskedPassword)
+		}
+		query := parsed.Query()
+		if query.Get("password") != "" {
+			query.Set("password", maskedPassword)
+			parsed.RawQuery = query.Encode()
+		}
+		return parsed.String()
+	}
+	return passwordRegexp.ReplaceAllString(dsn, "${1}"+maskedPassword)
+}
@@ -0,0 +1,219 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var LevelNames = []string{"debug", "info", "warn", "error"}
+
+func (receiver Level) String() string {
+	if receiver < LevelDebug || receiver > LevelError {
+		return fmt.Sprintf("level(%d)", int(receiver))
+	}
+	return LevelNames[receiver]
+}
+
+func ParseLevel(name string) (Level, error) {
+	for index, levelName := range LevelNames {
+		if strings.EqualFold(name, levelName) {
+			return Level(index), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+)
+
+var Formats = []string{TextFormat, JSONFormat}
+
+const (
+	timeFormat = "2006-01-02T15:04:05.000Z07:00"
+	sessionKey = "session"
+)
+
+type Options struct {
+	Path   string
+	Level  Level
+	Format string
+	// MaxSize is the size in bytes after which the file is rotated, 0 disables it.
+	MaxSize int64
+	// MaxAge is the age of the first entry after which the file is rotated, 0 disables it.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files kept, older ones are removed.
+	MaxBackups int
+}
+
+type Logger struct {
+	mutex  sync.Mutex
+	out    io.Writer
+	level  Level
+	json   bool
+	fields map[string]string
+}
+
+var std = New(os.Stderr, LevelInfo, TextFormat)
+
+func New(out io.Writer, level Level, format string) *Logger {
+	return &Logger{out: out, level: level, json: format == JSONFormat, fields: make(map[string]string)}
+}
+
+// Open sends the entries of the package level functions and of the standard log package
+// to a rotating file.
+func Open(options Options) error {
+	file, err := OpenRotatingFile(options.Path, options.MaxSize, options.MaxAge, options.MaxBackups)
+	if err != nil {
+		return err
+	}
+	std.mutex.Lock()
+	std.out = file
+	std.level = options.Level
+	std.json = options.Format == JSONFormat
+	std.mutex.Unlock()
+
+	log.SetFlags(0)
+	log.SetOutput(writerFunc(func(line []byte) {
+		std.write(LevelInfo, strings.TrimSuffix(string(line), "\n"))
+	}))
+	return nil
+}
+
+// Close closes the file opened by Open, later entries go to stderr.
+func Close() error {
+	std.mutex.Lock()
+	defer std.mutex.Unlock()
+	closer, ok := std.out.(io.Closer)
+	std.out = os.Stderr
+	log.SetOutput(os.Stderr)
+	if !ok {
+		return nil
+	}
+	return closer.Close()
+}
+
+type writerFunc func(line []byte)
+
+func (receiver writerFunc) Write(line []byte) (int, error) {
+	receiver(line)
+	return len(line), nil
+}
+
+// NewSession starts a new correlation id, every following entry carries it.
+func NewSession() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		id = []byte(fmt.Sprint(time.Now().UnixNano()))
+	}
+	session := hex.EncodeToString(id)
+	SetField(sessionKey, session)
+	return session
+}
+
+func Session() string {
+	std.mutex.Lock()
+	defer std.mutex.Unlock()
+	return std.fields[sessionKey]
+}
+
+// SetField adds key to every following entry, an empty value removes it.
+func SetField(key, value string) {
+	std.mutex.Lock()
+	defer std.mutex.Unlock()
+	if value == "" {
+		delete(std.fields, key)
+		return
+	}
+	std.fields[key] = value
+}
+
+func (receiver *Logger) write(level Level, message string) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	if level < receiver.level {
+		return
+	}
+
+	now := time.Now().Format(timeFormat)
+	keys := make([]string, 0, len(receiver.fields))
+	for key := range receiver.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var line string
+	if receiver.json {
+		entry := make(map[string]string, len(keys)+3)
+		for _, key := range keys {
+			entry[key] = receiver.fields[key]
+		}
+		entry["time"] = now
+		entry["level"] = level.String()
+		entry["msg"] = message
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return
+		}
+		line = string(data)
+	} else {
+		var builder strings.Builder
+		builder.WriteString(fmt.Sprintf("%s %-5s %s", now, strings.ToUpper(level.String()), message))
+		for _, key := range keys {
+			builder.WriteString(fmt.Sprintf(" %s=%q", key, receiver.fields[key]))
+		}
+		line = builder.String()
+	}
+	_, _ = io.WriteString(receiver.out, line+"\n")
+}
+
+func Debug(args ...interface{}) {
+	std.write(LevelDebug, fmt.Sprint(args...))
+}
+
+func Debugf(format string, args ...interface{}) {
+	std.write(LevelDebug, fmt.Sprintf(format, args...))
+}
+
+func Info(args ...interface{}) {
+	std.write(LevelInfo, fmt.Sprint(args...))
+}
+
+func Infof(format string, args ...interface{}) {
+	std.write(LevelInfo, fmt.Sprintf(format, args...))
+}
+
+func Warn(args ...interface{}) {
+	std.write(LevelWarn, fmt.Sprint(args...))
+}
+
+func Warnf(format string, args ...interface{}) {
+	std.write(LevelWarn, fmt.Sprintf(format, args...))
+}
+
+func Error(args ...interface{}) {
+	std.write(LevelError, fmt.Sprint(args...))
+}
+
+func Errorf(format string, args ...interface{}) {
+	std.write(LevelError, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		level Level
+		ok    bool
+	}{
+		{"debug", LevelDebug, true},
+		{"INFO", LevelInfo, true},
+		{"Warn", LevelWarn, true},
+		{"error", LevelError, true},
+		{"warning", LevelInfo, false},
+		{"", LevelInfo, false},
+	}
+	for _, test := range tests {
+		level, err := ParseLevel(test.name)
+		if level != test.level || (err == nil) != test.ok {
+			t.Errorf("ParseLevel(%q) = %s, %v, want %s", test.name, level, err, test.level)
+		}
+	}
+	if got := Level(7).String(); got != "level(7)" {
+		t.Errorf("Level(7).String() = %q", got)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New(&buffer, LevelWarn, TextFormat)
+	logger.fields["session"] = "abc"
+	logger.fields["login"] = "ivan"
+	logger.write(LevelInfo, "skipped")
+	logger.write(LevelWarn, "kept")
+	logger.write(LevelError, "failed")
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("write() wrote %d lines, want 2:\n%s", len(lines), buffer.String())
+	}
+	if !strings.HasSuffix(lines[0], ` WARN  kept login="ivan" session="abc"`) {
+		t.Errorf("text entry = %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], ` ERROR failed login="ivan" session="abc"`) {
+		t.Errorf("text entry = %q", lines[1])
+	}
+	if _, err := time.Parse(timeFormat, strings.SplitN(lines[0], " ", 2)[0]); err != nil {
+		t.Errorf("text entry starts without the time: %v", err)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New(&buffer, LevelDebug, JSONFormat)
+	logger.fields["session"] = "abc"
+	logger.write(LevelDebug, `say "hi"`)
+
+	var entry map[string]string
+	if err := json.Unmarshal(buffer.Bytes(), &entry); err != nil {
+		t.Fatalf("JSON entry %q: %v", buffer.String(), err)
+	}
+	if entry["level"] != "debug" || entry["msg"] != `say "hi"` || entry["session"] != "abc" || entry["time"] == "" {
+		t.Errorf("JSON entry = %v", entry)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.log")
+
+	file, err := OpenRotatingFile(path, 20, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	for _, line := range []string{"first line 1\n", "second line\n", "third line 3\n", "fourth line\n"} {
+		if _, err = file.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+		// backups are named by the time of rotation in milliseconds
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil || string(data) != "fourth line\n" {
+		t.Errorf("current log = %q, %v, want the last line", data, err)
+	}
+	backups, err := filepath.Glob(path + ".*")
+	if err != nil || len(backups) != 2 {
+		t.Fatalf("backups = %v, %v, want 2", backups, err)
+	}
+	data, err = ioutil.ReadFile(backups[0])
+	if err != nil || string(data) != "second line\n" {
+		t.Errorf("oldest kept backup = %q, %v, want the second line", data, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != fileMode {
+		t.Errorf("log file mode = %v, %v, want %v", info.Mode().Perm(), err, os.FileMode(fileMode))
+	}
+}
+
+func TestFirstEntryTime(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fallback := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"text.log", "2020-03-01T10:15:00.000Z INFO  started\n", "2020-03-01T10:15:00Z"},
+		{"json.log", `{"level":"info","msg":"started","time":"2020-03-01T10:15:00.000+05:00"}` + "\n", "2020-03-01T05:15:00Z"},
+		{"broken.log", "started\n", "2020-01-01T00:00:00Z"},
+		{"partial.log", "2020-03-01T10:15:00.000Z INFO", "2020-01-01T00:00:00Z"},
+	}
+	for _, test := range tests {
+		path := filepath.Join(dir, test.name)
+		if err := ioutil.WriteFile(path, []byte(test.content), fileMode); err != nil {
+			t.Fatal(err)
+		}
+		if got := firstEntryTime(path, fallback).UTC().Format(time.RFC3339); got != test.want {
+			t.Errorf("firstEntryTime(%s) = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fileMode         = 0600
+	backupTimeFormat = "20060102-150405.000"
+)
+
+// RotatingFile is a log file that is renamed to path.<time> once it grows past maxSize bytes
+// or its first entry gets older than maxAge, keeping at most maxBackups renamed files.
+type RotatingFile struct {
+	mutex      sync.Mutex
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	file       *os.File
+	size       int64
+	started    time.Time
+}
+
+func OpenRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*RotatingFile, error) {
+	rotating := &RotatingFile{path: path, maxSize: maxSize, maxAge: maxAge, maxBackups: maxBackups}
+	err := rotating.open()
+	if err != nil {
+		return nil, err
+	}
+	return rotating, nil
+}
+
+func (receiver *RotatingFile) open() (err error) {
+	receiver.file, err = os.OpenFile(receiver.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, fileMode)
+	if err != nil {
+		return err
+	}
+	// files created by older versions may be readable by everyone
+	if err = receiver.file.Chmod(fileMode); err != nil {
+		return err
+	}
+	info, err := receiver.file.Stat()
+	if err != nil {
+		return err
+	}
+	receiver.size = info.Size()
+	receiver.started = time.Now()
+	if receiver.size > 0 {
+		receiver.started = firstEntryTime(receiver.path, info.ModTime())
+	}
+	return nil
+}
+
+// firstEntryTime reads the time of the first entry in a text or JSON log file.
+func firstEntryTime(path string, fallback time.Time) time.Time {
+	file, err := os.Open(path)
+	if err != nil {
+		return fallback
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	line, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil {
+		return fallback
+	}
+	var entry struct {
+		Time string `json:"time"`
+	}
+	value := strings.SplitN(line, " ", 2)[0]
+	if json.Unmarshal([]byte(line), &entry) == nil {
+		value = entry.Time
+	}
+	started, err := time.Parse(timeFormat, value)
+	if err != nil {
+		return fallback
+	}
+	return started
+}
+
+func (receiver *RotatingFile) Write(data []byte) (int, error) {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	if receiver.size > 0 && receiver.needsRotation(int64(len(data))) {
+		if err := receiver.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	count, err := receiver.file.Write(data)
+	receiver.size += int64(count)
+	return count, err
+}
+
+func (receiver *RotatingFile) needsRotation(size int64) bool {
+	if receiver.maxSize > 0 && receiver.size+size > receiver.maxSize {
+		return true
+	}
+	return receiver.maxAge > 0 && time.Since(receiver.started) > receiver.maxAge
+}
+
+func (receiver *RotatingFile) rotate() error {
+	err := receiver.file.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Rename(receiver.path, receiver.path+"."+time.Now().Format(backupTimeFormat))
+	if err != nil {
+		return err
+	}
+	err = receiver.open()
+	if err != nil {
+		return err
+	}
+	return receiver.removeOldBackups()
+}
+
+func (receiver *RotatingFile) removeOldBackups() error {
+	backups, err := filepath.Glob(receiver.path + ".*")
+	if err != nil {
+		return err
+	}
+	// backup names end with a sortable time
+	sort.Strings(backups)
+	for len(backups) > receiver.maxBackups {
+		if err = os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+func (receiver *RotatingFile) Close() error {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	return receiver.file.Close()
+}
@@ -0,0 +1,183 @@
+package money
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in kopecks.
+type Money int64
+
+type Currency string
+
+const (
+	UZS Currency = "UZS"
+	USD Currency = "USD"
+	RUB Currency = "RUB"
+)
+
+// DefaultCurrency is the currency of the amounts with no currency of their own, the accounts
+// opened before there were currencies are in roubles.
+const DefaultCurrency = RUB
+
+// Currencies are the currencies the bank keeps accounts in.
+var Currencies = []Currency{UZS, USD, RUB}
+
+var symbols = map[Currency]string{
+	USD: "$",
+	RUB: "₽",
+}
+
+const (
+	kopecksInRouble = 100
+	maxDigits       = 15
+)
+
+var (
+	ErrInvalid         = errors.New("invalid amount")
+	ErrNotPositive     = errors.New("amount must be positive")
+	ErrUnknownCurrency = errors.New("unknown currency")
+)
+
+func FromKopecks(kopecks int64) Money {
+	return Money(kopecks)
+}
+
+func (receiver Money) Kopecks() int64 {
+	return int64(receiver)
+}
+
+// Parse reads a non-negative amount with up to two decimals. Spaces and underscores
+// separate thousands, so do commas when a point is present, a single comma
+// followed by one or two digits is read as the decimal separator.
+func Parse(value string) (Money, error) {
+	value = strings.NewReplacer(" ", "", " ", "", "_", "").Replace(strings.TrimSpace(value))
+	comma := strings.LastIndex(value, ",")
+	if comma >= 0 && !strings.Contains(value, ".") && strings.Count(value, ",") == 1 && len(value)-comma <= 3 {
+		value = value[:comma] + "." + value[comma+1:]
+	}
+	value = strings.ReplaceAll(value, ",", "")
+
+	parts := strings.SplitN(value, ".", 2)
+	whole := parts[0]
+	fraction := ""
+	if len(parts) == 2 {
+		fraction = parts[1]
+	}
+	if whole == "" || len(whole) > maxDigits || len(fraction) > 2 || !digits(whole) || !digits(fraction) {
+		return 0, ErrInvalid
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	return Money(amount), nil
+}
+
+// ParseAmount reads an amount to transfer or pay, it has to be greater than zero.
+func ParseAmount(value string) (Money, error) {
+	amount, err := Parse(value)
+	if err != nil {
+		return 0, err
+	}
+	if amount <= 0 {
+		return 0, ErrNotPositive
+	}
+	return amount, nil
+}
+
+// ParseCurrency reads one of Currencies, ignoring case.
+func ParseCurrency(value string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(value)))
+	for _, known := range Currencies {
+		if currency == known {
+			return currency, nil
+		}
+	}
+	return "", ErrUnknownCurrency
+}
+
+func digits(value string) bool {
+	for _, char := range value {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal formats the amount for machines, as in 1234.50.
+func (receiver Money) Decimal() string {
+	sign, whole, fraction := receiver.split()
+	return sign + strconv.FormatInt(whole, 10) + "." + fraction
+}
+
+// Format formats the amount for people with thousands separated, as in 1 234.50 ₽.
+func (receiver Money) Format(currency Currency) string {
+	sign, whole, fraction := receiver.split()
+	number := strconv.FormatInt(whole, 10)
+	var builder strings.Builder
+	builder.WriteString(sign)
+	for index, char := range number {
+		if index > 0 && (len(number)-index)%3 == 0 {
+			builder.WriteRune(' ')
+		}
+		builder.WriteRune(char)
+	}
+	builder.WriteString("." + fraction + " ")
+	if symbol, ok := symbols[currency]; ok {
+		builder.WriteString(symbol)
+	} else {
+		builder.WriteString(string(currency))
+	}
+	return builder.String()
+}
+
+func (receiver Money) String() string {
+	return receiver.Format(DefaultCurrency)
+}
+
+// MarshalJSON writes the amount as a number with two decimals.
+func (receiver Money) MarshalJSON() ([]byte, error) {
+	return []byte(receiver.Decimal()), nil
+}
+
+func (receiver *Money) UnmarshalJSON(data []byte) error {
+	return receiver.UnmarshalText([]byte(strings.Trim(string(data), `"`)))
+}
+
+// MarshalText writes the amount like MarshalJSON, xml uses it.
+func (receiver Money) MarshalText() ([]byte, error) {
+	return []byte(receiver.Decimal()), nil
+}
+
+func (receiver *Money) UnmarshalText(text []byte) error {
+	value := string(text)
+	negative := strings.HasPrefix(value, "-")
+	amount, err := Parse(strings.TrimPrefix(value, "-"))
+	if err != nil {
+		return err
+	}
+	if negative {
+		amount = -amount
+	}
+	*receiver = amount
+	return nil
+}
+
+func (receiver Money) split() (sign string, whole int64, fraction string) {
+	kopecks := int64(receiver)
+	if kopecks < 0 {
+		sign = "-"
+		kopecks = -kopecks
+	}
+	fraction = strconv.FormatInt(kopecks%kopecksInRouble, 10)
+	if len(fraction) < 2 {
+		fraction = "0" + fraction
+	}
+	return sign, kopecks / kopecksInRouble, fraction
+}
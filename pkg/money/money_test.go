@@ -0,0 +1,201 @@
+package money
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Money
+		err   error
+	}{
+		{"0", 0, nil},
+		{"100", 10000, nil},
+		{"1.5", 150, nil},
+		{"1.05", 105, nil},
+		{".5", 0, ErrInvalid},
+		{"  12.34 ", 1234, nil},
+		{"1 234.50", 123450, nil},
+		{"1 234,50", 123450, nil},
+		{"1_000_000", 100000000, nil},
+		{"1,5", 150, nil},
+		{"1,50", 150, nil},
+		{"1,500", 150000, nil},
+		{"1,234.5", 123450, nil},
+		{"1,234,567", 123456700, nil},
+		{"1.234", 0, ErrInvalid},
+		{"1.2.3", 0, ErrInvalid},
+		{"-1", 0, ErrInvalid},
+		{"1e3", 0, ErrInvalid},
+		{"", 0, ErrInvalid},
+		{"abc", 0, ErrInvalid},
+		{"999999999999999.99", 99999999999999999, nil},
+		{"1000000000000000", 0, ErrInvalid},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.value)
+		if got != test.want || err != test.err {
+			t.Errorf("Parse(%q) = %d, %v, want %d, %v", test.value, got, err, test.want, test.err)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	if _, err := ParseAmount("0.00"); err != ErrNotPositive {
+		t.Errorf("ParseAmount(0.00) error = %v, want %v", err, ErrNotPositive)
+	}
+	if got, err := ParseAmount("0.01"); got != 1 || err != nil {
+		t.Errorf("ParseAmount(0.01) = %d, %v, want 1", got, err)
+	}
+}
+
+func TestParseCurrency(t *testing.T) {
+	if got, err := ParseCurrency(" usd "); got != USD || err != nil {
+		t.Errorf("ParseCurrency(usd) = %q, %v, want %q", got, err, USD)
+	}
+	if _, err := ParseCurrency("EUR"); err != ErrUnknownCurrency {
+		t.Errorf("ParseCurrency(EUR) error = %v, want %v", err, ErrUnknownCurrency)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount   Money
+		currency Currency
+		want     string
+	}{
+		{0, RUB, "0.00 ₽"},
+		{5, RUB, "0.05 ₽"},
+		{123450, RUB, "1 234.50 ₽"},
+		{100000000, USD, "1 000 000.00 $"},
+		{12345, UZS, "123.45 UZS"},
+		{-123456, RUB, "-1 234.56 ₽"},
+		{-5, USD, "-0.05 $"},
+	}
+	for _, test := range tests {
+		if got := test.amount.Format(test.currency); got != test.want {
+			t.Errorf("%d.Format(%s) = %q, want %q", test.amount, test.currency, got, test.want)
+		}
+	}
+	if got := Money(123450).String(); got != "1 234.50 ₽" {
+		t.Errorf("String() = %q, want %q", got, "1 234.50 ₽")
+	}
+	if got := Money(-123450).Decimal(); got != "-1234.50" {
+		t.Errorf("Decimal() = %q, want %q", got, "-1234.50")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	for _, amount := range []Money{0, 1, 123450, -99} {
+		data, err := json.Marshal(amount)
+		if err != nil {
+			t.Fatalf("Marshal(%d) error = %v", amount, err)
+		}
+		var got Money
+		if err = json.Unmarshal(data, &got); err != nil || got != amount {
+			t.Errorf("Unmarshal(%s) = %d, %v, want %d", data, got, err, amount)
+		}
+	}
+	var got Money
+	if err := json.Unmarshal([]byte(`"12.5"`), &got); err != nil || got != 1250 {
+		t.Errorf(`Unmarshal("12.5") = %d, %v, want 1250`, got, err)
+	}
+}
+
+func TestXML(t *testing.T) {
+	type record struct {
+		Balance Money
+	}
+	data, err := xml.Marshal(record{Balance: 123450})
+	if err != nil || string(data) != "<record><Balance>1234.50</Balance></record>" {
+		t.Fatalf("Marshal() = %s, %v", data, err)
+	}
+	var got record
+	if err = xml.Unmarshal(data, &got); err != nil || got.Balance != 123450 {
+		t.Errorf("Unmarshal(%s) = %d, %v, want 123450", data, got.Balance, err)
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Rate
+		err   error
+	}{
+		{"1", RateOne, nil},
+		{"12650.5", 12650500000, nil},
+		{"12 650.123456", 12650123456, nil},
+		{"0.000001", 1, nil},
+		{"0.0000001", 0, ErrInvalidRate},
+		{"0", 0, ErrInvalidRate},
+		{"-1", 0, ErrInvalidRate},
+		{"1,5", 0, ErrInvalidRate},
+		{"1000000000", 0, ErrInvalidRate},
+		{"", 0, ErrInvalidRate},
+	}
+	for _, test := range tests {
+		got, err := ParseRate(test.value)
+		if got != test.want || err != test.err {
+			t.Errorf("ParseRate(%q) = %d, %v, want %d, %v", test.value, got, err, test.want, test.err)
+		}
+	}
+}
+
+func TestRateString(t *testing.T) {
+	tests := []struct {
+		rate Rate
+		want string
+	}{
+		{RateOne, "1.00"},
+		{12650500000, "12650.50"},
+		{12650123456, "12650.123456"},
+		{1, "0.000001"},
+		{1500, "0.0015"},
+	}
+	for _, test := range tests {
+		if got := test.rate.String(); got != test.want {
+			t.Errorf("Rate(%d).String() = %q, want %q", test.rate, got, test.want)
+		}
+	}
+}
+
+func TestCross(t *testing.T) {
+	usd, rub := Rate(12650000000), Rate(140000000)
+	if got := Cross(usd, rub); got != 90357143 {
+		t.Errorf("Cross(USD, RUB) = %d, want 90357143", got)
+	}
+	if got := Cross(rub, usd); got != 11067 {
+		t.Errorf("Cross(RUB, USD) = %d, want 11067", got)
+	}
+	if got := Cross(usd, usd); got != RateOne {
+		t.Errorf("Cross(USD, USD) = %d, want %d", got, RateOne)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	usd, rub := Rate(12650000000), Rate(140000000)
+	tests := []struct {
+		name     string
+		amount   Money
+		from, to Rate
+		want     Money
+	}{
+		{"same currency", 12345, usd, usd, 12345},
+		{"to base currency", 100, usd, RateOne, 1265000},
+		{"from base currency", 1265000, RateOne, usd, 100},
+		{"rounded down", 1, rub, usd, 0},
+		{"half rounded up", 1, Rate(3), Rate(6), 1},
+		{"just under half rounded down", 1, Rate(499999), RateOne, 0},
+		{"negative half away from zero", -1, Rate(3), Rate(6), -1},
+		{"cross", 10000, usd, rub, 903571},
+		{"product over int64", 99999999999999999, RateOne, usd, 7905138339921},
+	}
+	for _, test := range tests {
+		if got := Convert(test.amount, test.from, test.to); got != test.want {
+			t.Errorf("%s: Convert(%d, %d, %d) = %d, want %d", test.name, test.amount, test.from, test.to, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// BaseCurrency is the currency exchange rates are given in.
+const BaseCurrency = UZS
+
+// Rate is the price of a unit of a currency in another one, in millionths.
+type Rate int64
+
+// RateOne is the rate of a currency to itself.
+const RateOne Rate = 1000000
+
+const rateDecimals = 6
+
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// ParseRate reads a positive rate with up to six decimals, as in 12650.5.
+func ParseRate(value string) (Rate, error) {
+	value = strings.NewReplacer(" ", "", "_", "").Replace(strings.TrimSpace(value))
+	parts := strings.SplitN(value, ".", 2)
+	whole := parts[0]
+	fraction := ""
+	if len(parts) == 2 {
+		fraction = parts[1]
+	}
+	if whole == "" || len(whole) > maxDigits-rateDecimals || len(fraction) > rateDecimals || !digits(whole) || !digits(fraction) {
+		return 0, ErrInvalidRate
+	}
+	fraction += strings.Repeat("0", rateDecimals-len(fraction))
+	rate, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil || rate <= 0 {
+		return 0, ErrInvalidRate
+	}
+	return Rate(rate), nil
+}
+
+// String formats the rate without the trailing zeros of its decimals, keeping at least two.
+func (receiver Rate) String() string {
+	fraction := strconv.FormatInt(int64(receiver)%int64(RateOne), 10)
+	fraction = strings.Repeat("0", rateDecimals-len(fraction)) + fraction
+	for len(fraction) > 2 && strings.HasSuffix(fraction, "0") {
+		fraction = fraction[:len(fraction)-1]
+	}
+	return strconv.FormatInt(int64(receiver)/int64(RateOne), 10) + "." + fraction
+}
+
+func (receiver *Rate) UnmarshalText(text []byte) error {
+	rate, err := ParseRate(string(text))
+	if err != nil {
+		return err
+	}
+	*receiver = rate
+	return nil
+}
+
+// UnmarshalJSON reads the rate from a number or a string.
+func (receiver *Rate) UnmarshalJSON(data []byte) error {
+	return receiver.UnmarshalText([]byte(strings.Trim(string(data), `"`)))
+}
+
+func (receiver Rate) MarshalJSON() ([]byte, error) {
+	return []byte(receiver.String()), nil
+}
+
+// Cross returns the price of a unit of the currency priced from in the currency priced to,
+// both given in the same currency.
+func Cross(from, to Rate) Rate {
+	return Rate(divide(big.NewInt(int64(from)), int64(RateOne), int64(to)))
+}
+
+// Convert changes amount in the currency priced from into the currency priced to, rounding
+// half a kopeck away from zero.
+func Convert(amount Money, from, to Rate) Money {
+	return Money(divide(big.NewInt(int64(amount)), int64(from), int64(to)))
+}
+
+// divide returns value * multiplier / divisor rounded half away from zero, the product
+// doesn't fit int64 for large amounts.
+func divide(value *big.Int, multiplier, divisor int64) int64 {
+	product := new(big.Int).Mul(value, big.NewInt(multiplier))
+	negative := product.Sign() < 0
+	product.Abs(product)
+	quotient, remainder := new(big.Int).QuoRem(product, big.NewInt(divisor), new(big.Int))
+	if remainder.Mul(remainder, big.NewInt(2)).Cmp(big.NewInt(divisor)) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if negative {
+		quotient.Neg(quotient)
+	}
+	return quotient.Int64()
+}
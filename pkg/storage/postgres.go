@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const postgresDriverName = "ibank-postgres"
+
+func init() {
+	sql.Register(postgresDriverName, postgresDriver{})
+}
+
+var errMissingArgument = errors.New("missing query argument")
+
+var (
+	autoIncrementRegexp = regexp.MustCompile(`(?i)\bINTEGER\s+PRIMARY\s+KEY\s+AUTOINCREMENT\b`)
+	integerRegexp       = regexp.MustCompile(`(?i)\bINTEGER\b`)
+	createTableRegexp   = regexp.MustCompile(`(?i)^\s*CREATE\s+TABLE\b`)
+	insertOrRegexp      = regexp.MustCompile(`(?is)^\s*INSERT\s+OR\s+(REPLACE|IGNORE)\s+INTO\s+(\w+)\s*\(([^)]*)\)(.*?)\s*;?\s*$`)
+	insertWithIdRegexp  = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+(\w+)\s*\(\s*id\s*,`)
+	likeRegexp          = regexp.MustCompile(`(?i)\b([a-z_][\w.]*)\s+(NOT\s+)?LIKE\b`)
+)
+
+// postgresDriver wraps lib/pq and translates SQLite flavoured queries to Postgres.
+type postgresDriver struct{}
+
+func (postgresDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := pq.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &postgresConn{conn: conn}, nil
+}
+
+type postgresConn struct {
+	conn driver.Conn
+}
+
+func (receiver *postgresConn) Prepare(query string) (driver.Stmt, error) {
+	return receiver.PrepareContext(context.Background(), query)
+}
+
+func (receiver *postgresConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	statement := translate(query)
+	stmt, err := receiver.conn.Prepare(statement.query)
+	if err != nil {
+		return nil, err
+	}
+	return &postgresStmt{stmt: stmt, statement: statement}, nil
+}
+
+func (receiver *postgresConn) Close() error {
+	return receiver.conn.Close()
+}
+
+func (receiver *postgresConn) Begin() (driver.Tx, error) {
+	return receiver.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (receiver *postgresConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return receiver.conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (receiver *postgresConn) Ping(ctx context.Context) error {
+	return receiver.conn.(driver.Pinger).Ping(ctx)
+}
+
+// CheckNamedValue keeps the names of sql.Named arguments, they are bound to positions by translate.
+func (receiver *postgresConn) CheckNamedValue(value *driver.NamedValue) (err error) {
+	value.Value, err = driver.DefaultParameterConverter.ConvertValue(value.Value)
+	return err
+}
+
+func (receiver *postgresConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	statement := translate(query)
+	values, err := statement.bind(args)
+	if err != nil {
+		return nil, err
+	}
+	execer := receiver.conn.(driver.ExecerContext)
+	result, err := execer.ExecContext(ctx, statement.query, values)
+	if err != nil || statement.serialTable == "" {
+		return result, err
+	}
+	_, err = execer.ExecContext(ctx, resetSequenceSQL(statement.serialTable), nil)
+	return result, err
+}
+
+func (receiver *postgresConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	statement := translate(query)
+	values, err := statement.bind(args)
+	if err != nil {
+		return nil, err
+	}
+	return receiver.conn.(driver.QueryerContext).QueryContext(ctx, statement.query, values)
+}
+
+type postgresStmt struct {
+	stmt      driver.Stmt
+	statement statement
+}
+
+func (receiver *postgresStmt) Close() error {
+	return receiver.stmt.Close()
+}
+
+func (receiver *postgresStmt) NumInput() int {
+	return -1
+}
+
+func (receiver *postgresStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return receiver.stmt.Exec(args)
+}
+
+func (receiver *postgresStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return receiver.stmt.Query(args)
+}
+
+func (receiver *postgresStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	values, err := receiver.statement.bindValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return receiver.stmt.Exec(values)
+}
+
+func (receiver *postgresStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	values, err := receiver.statement.bindValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return receiver.stmt.Query(values)
+}
+
+// statement is a query translated to Postgres.
+type statement struct {
+	query string
+	// params holds the name of every $n placeholder, empty for the ones made from "?".
+	params []string
+	// serialTable is set for inserts with explicit ids, the id sequence of the table
+	// has to be moved past them afterwards.
+	serialTable string
+}
+
+// translate rewrites the SQLite specific parts of query:
+// AUTOINCREMENT keys and 32-bit INTEGER columns, INSERT OR REPLACE/IGNORE,
+// LIKE on non-text columns and with case folding, and ? and :name placeholders.
+// INSERT OR REPLACE takes the first listed column as the conflict target.
+func translate(query string) statement {
+	var result statement
+	if createTableRegexp.MatchString(query) {
+		query = autoIncrementRegexp.ReplaceAllString(query, "BIGSERIAL PRIMARY KEY")
+		query = integerRegexp.ReplaceAllString(query, "BIGINT")
+	}
+	if match := insertOrRegexp.FindStringSubmatch(query); match != nil {
+		query = translateInsertOr(strings.ToUpper(match[1]), match[2], match[3], match[4])
+	}
+	if match := insertWithIdRegexp.FindStringSubmatch(query); match != nil {
+		result.serialTable = match[1]
+	}
+	query = likeRegexp.ReplaceAllString(query, "CAST($1 AS TEXT) ${2}ILIKE")
+	result.query, result.params = translatePlaceholders(query)
+	return result
+}
+
+func translateInsertOr(conflict, table, columnList, rest string) string {
+	columns := strings.Split(columnList, ",")
+	for index := range columns {
+		columns[index] = strings.TrimSpace(columns[index])
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s)%s ON CONFLICT", table, strings.Join(columns, ", "), rest)
+	if conflict == "IGNORE" || len(columns) == 1 {
+		return query + " DO NOTHING"
+	}
+	updates := make([]string, 0, len(columns)-1)
+	for _, column := range columns[1:] {
+		updates = append(updates, column+" = excluded."+column)
+	}
+	return fmt.Sprintf("%s (%s) DO UPDATE SET %s", query, columns[0], strings.Join(updates, ", "))
+}
+
+// translatePlaceholders replaces ? and :name outside of literals with $n, repeated names share n.
+func translatePlaceholders(query string) (string, []string) {
+	var builder strings.Builder
+	var params []string
+	positions := make(map[string]int)
+	var quote byte
+	for index := 0; index < len(query); index++ {
+		char := query[index]
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '\'' || char == '"':
+			quote = char
+		case char == '?':
+			params = append(params, "")
+			builder.WriteString("$" + strconv.Itoa(len(params)))
+			continue
+		case char == ':' && index+1 < len(query) && isNameStart(query[index+1]) && (index == 0 || query[index-1] != ':'):
+			end := index + 1
+			for end < len(query) && isNamePart(query[end]) {
+				end++
+			}
+			name := query[index+1 : end]
+			position, ok := positions[name]
+			if !ok {
+				params = append(params, name)
+				position = len(params)
+				positions[name] = position
+			}
+			builder.WriteString("$" + strconv.Itoa(position))
+			index = end - 1
+			continue
+		}
+		builder.WriteByte(char)
+	}
+	return builder.String(), params
+}
+
+func isNameStart(char byte) bool {
+	return char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func isNamePart(char byte) bool {
+	return isNameStart(char) || (char >= '0' && char <= '9')
+}
+
+// bind orders args by the placeholders of the statement.
+func (receiver statement) bind(args []driver.NamedValue) ([]driver.NamedValue, error) {
+	named := make(map[string]driver.NamedValue)
+	var positional []driver.NamedValue
+	for _, arg := range args {
+		if arg.Name != "" {
+			named[arg.Name] = arg
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	if len(receiver.params) == 0 {
+		return positional, nil
+	}
+
+	values := make([]driver.NamedValue, len(receiver.params))
+	for index, name := range receiver.params {
+		var arg driver.NamedValue
+		if name == "" {
+			if len(positional) == 0 {
+				return nil, fmt.Errorf("%w for $%d", errMissingArgument, index+1)
+			}
+			arg, positional = positional[0], positional[1:]
+		} else {
+			var ok bool
+			arg, ok = named[name]
+			if !ok {
+				return nil, fmt.Errorf("%w :%s", errMissingArgument, name)
+			}
+		}
+		values[index] = driver.NamedValue{Ordinal: index + 1, Value: arg.Value}
+	}
+	return values, nil
+}
+
+func (receiver statement) bindValues(args []driver.NamedValue) ([]driver.Value, error) {
+	named, err := receiver.bind(args)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]driver.Value, len(named))
+	for index, arg := range named {
+		values[index] = arg.Value
+	}
+	return values, nil
+}
+
+func resetSequenceSQL(table string) string {
+	return fmt.Sprintf(
+		"SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), GREATEST((SELECT MAX(id) FROM %[1]s), 1))",
+		table)
+}
@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  statement
+	}{
+		{
+			name:  "create table",
+			query: "CREATE TABLE IF NOT EXISTS payees (id INTEGER PRIMARY KEY AUTOINCREMENT, amount INTEGER NOT NULL)",
+			want:  statement{query: "CREATE TABLE IF NOT EXISTS payees (id BIGSERIAL PRIMARY KEY, amount BIGINT NOT NULL)"},
+		},
+		{
+			name:  "integer outside of create table",
+			query: "SELECT CAST(amount AS INTEGER) FROM payees",
+			want:  statement{query: "SELECT CAST(amount AS INTEGER) FROM payees"},
+		},
+		{
+			name:  "insert or replace",
+			query: "INSERT OR REPLACE INTO atm_details (atm_id, opens, closes)\nVALUES (:atm_id, :opens, :closes);",
+			want: statement{
+				query:  "INSERT INTO atm_details (atm_id, opens, closes)\nVALUES ($1, $2, $3) ON CONFLICT (atm_id) DO UPDATE SET opens = excluded.opens, closes = excluded.closes",
+				params: []string{"atm_id", "opens", "closes"},
+			},
+		},
+		{
+			name:  "insert or replace with id",
+			query: "INSERT OR REPLACE INTO payees (id, name) VALUES (?, ?)",
+			want: statement{
+				query:       "INSERT INTO payees (id, name) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET name = excluded.name",
+				params:      []string{"", ""},
+				serialTable: "payees",
+			},
+		},
+		{
+			name:  "insert or replace of one column",
+			query: "INSERT OR REPLACE INTO settings (name) VALUES (?)",
+			want:  statement{query: "INSERT INTO settings (name) VALUES ($1) ON CONFLICT DO NOTHING", params: []string{""}},
+		},
+		{
+			name:  "insert or ignore",
+			query: "insert or ignore into client_salts (client_id, salt) values (?, ?)",
+			want:  statement{query: "INSERT INTO client_salts (client_id, salt) values ($1, $2) ON CONFLICT DO NOTHING", params: []string{"", ""}},
+		},
+		{
+			name:  "insert with id",
+			query: "INSERT INTO clients (id, name) VALUES (:id, :name)",
+			want:  statement{query: "INSERT INTO clients (id, name) VALUES ($1, $2)", params: []string{"id", "name"}, serialTable: "clients"},
+		},
+		{
+			name:  "like",
+			query: "SELECT id FROM clients c WHERE name LIKE ? OR c.phone_number NOT like ?",
+			want: statement{
+				query:  "SELECT id FROM clients c WHERE CAST(name AS TEXT) ILIKE $1 OR CAST(c.phone_number AS TEXT) NOT ILIKE $2",
+				params: []string{"", ""},
+			},
+		},
+	}
+	for _, test := range tests {
+		if got := translate(test.query); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: translate(%q) = %#v, want %#v", test.name, test.query, got, test.want)
+		}
+	}
+}
+
+func TestTranslatePlaceholders(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		want   string
+		params []string
+	}{
+		{"positional", "SELECT id FROM t WHERE a = ? AND b = ?", "SELECT id FROM t WHERE a = $1 AND b = $2", []string{"", ""}},
+		{"none", "SELECT id FROM t", "SELECT id FROM t", nil},
+		{
+			"in literals",
+			`SELECT '?', ':name', "?" FROM t WHERE a = ?`,
+			`SELECT '?', ':name', "?" FROM t WHERE a = $1`,
+			[]string{""},
+		},
+		{"escaped quote", "SELECT 'it''s ?' FROM t WHERE a = :a", "SELECT 'it''s ?' FROM t WHERE a = $1", []string{"a"}},
+		{
+			"repeated names",
+			"UPDATE t SET a = :a WHERE b = :b OR c = :a",
+			"UPDATE t SET a = $1 WHERE b = $2 OR c = $1",
+			[]string{"a", "b"},
+		},
+		{"names and positions", "SELECT id FROM t WHERE a = :a AND b = ?", "SELECT id FROM t WHERE a = $1 AND b = $2", []string{"a", ""}},
+		{"cast", "SELECT a::text FROM t WHERE b = :b", "SELECT a::text FROM t WHERE b = $1", []string{"b"}},
+		{
+			"datetime",
+			"SELECT id FROM t WHERE date >= '2020-01-31 10:15:00.000' AND date < :date AND time = '23:59'",
+			"SELECT id FROM t WHERE date >= '2020-01-31 10:15:00.000' AND date < $1 AND time = '23:59'",
+			[]string{"date"},
+		},
+		{"name with digits", "SELECT :name_2, :name_10", "SELECT $1, $2", []string{"name_2", "name_10"}},
+	}
+	for _, test := range tests {
+		got, params := translatePlaceholders(test.query)
+		if got != test.want || !reflect.DeepEqual(params, test.params) {
+			t.Errorf("%s: translatePlaceholders(%q) = %q, %q, want %q, %q", test.name, test.query, got, params, test.want, test.params)
+		}
+	}
+}
+
+func TestBind(t *testing.T) {
+	named := func(name string, value driver.Value) driver.NamedValue {
+		return driver.NamedValue{Name: name, Value: value}
+	}
+	positional := func(ordinal int, value driver.Value) driver.NamedValue {
+		return driver.NamedValue{Ordinal: ordinal, Value: value}
+	}
+	tests := []struct {
+		name  string
+		query string
+		args  []driver.NamedValue
+		want  []driver.Value
+		err   error
+	}{
+		{
+			name:  "positional",
+			query: "SELECT id FROM t WHERE a = ? AND b = ?",
+			args:  []driver.NamedValue{positional(1, int64(1)), positional(2, "b")},
+			want:  []driver.Value{int64(1), "b"},
+		},
+		{
+			name:  "named out of order",
+			query: "INSERT INTO t (a, b) VALUES (:a, :b)",
+			args:  []driver.NamedValue{named("b", "b"), named("a", int64(1))},
+			want:  []driver.Value{int64(1), "b"},
+		},
+		{
+			name:  "repeated name",
+			query: "UPDATE t SET a = :a WHERE b = :b OR c = :a",
+			args:  []driver.NamedValue{named("a", int64(1)), named("b", "b")},
+			want:  []driver.Value{int64(1), "b"},
+		},
+		{
+			name:  "names and positions",
+			query: "SELECT id FROM t WHERE a = ? AND b = :b AND c = ?",
+			args:  []driver.NamedValue{named("b", "b"), positional(2, int64(1)), positional(3, int64(3))},
+			want:  []driver.Value{int64(1), "b", int64(3)},
+		},
+		{
+			name:  "upsert",
+			query: "INSERT OR REPLACE INTO t (id, a) VALUES (:id, :a)",
+			args:  []driver.NamedValue{named("a", "a"), named("id", int64(7))},
+			want:  []driver.Value{int64(7), "a"},
+		},
+		{
+			name:  "missing name",
+			query: "SELECT id FROM t WHERE a = :a AND b = :b",
+			args:  []driver.NamedValue{named("a", int64(1))},
+			err:   errMissingArgument,
+		},
+		{
+			name:  "missing position",
+			query: "SELECT id FROM t WHERE a = ? AND b = ?",
+			args:  []driver.NamedValue{positional(1, int64(1))},
+			err:   errMissingArgument,
+		},
+	}
+	for _, test := range tests {
+		values, err := translate(test.query).bindValues(test.args)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("%s: bindValues() error = %v, want %v", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(values, test.want) {
+			t.Errorf("%s: bindValues() = %v, %v, want %v", test.name, values, err, test.want)
+		}
+	}
+
+	values, err := translate("SELECT id FROM t WHERE a = :a AND b = :b").bind([]driver.NamedValue{named("a", int64(1)), named("b", "b")})
+	if err != nil {
+		t.Fatalf("bind() error = %v", err)
+	}
+	for index, value := range values {
+		if value.Ordinal != index+1 || value.Name != "" {
+			t.Errorf("bind() value %d = %+v, want ordinal %d without a name", index, value, index+1)
+		}
+	}
+}
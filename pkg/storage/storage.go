@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	SQLite   = "sqlite3"
+	Postgres = "postgres"
+)
+
+var Drivers = []string{SQLite, Postgres}
+
+var ErrUnknownDriver = errors.New("unknown database driver")
+
+// Open opens the database with one of Drivers. The queries of apm-ibank-core and the CLI
+// are written for SQLite, on Postgres they are translated by the driver registered in this package.
+func Open(driver, dsn string) (*sql.DB, error) {
+	switch driver {
+	case SQLite:
+		return sql.Open(SQLite, dsn)
+	case Postgres:
+		return sql.Open(postgresDriverName, dsn)
+	}
+	return nil, ErrUnknownDriver
+}